@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"goph-keeper/internal/audit"
+	"goph-keeper/internal/breach"
+	"goph-keeper/internal/redact"
+	"goph-keeper/internal/services/client/audit_client"
+	"goph-keeper/internal/storage/sqlite"
+	"log/slog"
+	"os"
+)
+
+func main() {
+	opts := audit.DefaultOptions
+
+	login := flag.String("login", "", "user login in the local cache")
+	flag.Float64Var(&opts.MinEntropy, "min-entropy", opts.MinEntropy, "passwords below this entropy in bits are weak")
+	flag.IntVar(&opts.MaxAgeDays, "max-age-days", opts.MaxAgeDays, "passwords not changed for this many days are old, 0 - skip")
+	hibp := flag.String("hibp", "", "HIBP range dataset: directory, sorted file or http(s) range server URL")
+	dataDir := flag.String("data-dir", os.Getenv("GOPH_KEEPER_DATA_DIR"), "client data directory, current directory by default")
+	flag.Parse()
+
+	if *login == "" {
+		fmt.Fprintln(os.Stderr, "error: -login is required")
+		os.Exit(2)
+	}
+
+	if err := run(*login, *hibp, *dataDir, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(login, hibp, dataDir string, opts audit.Options) error {
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level:       slog.LevelWarn,
+		ReplaceAttr: redact.ReplaceAttr,
+	}))
+
+	db, err := sqlite.NewSqlStorage(log, dataDir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var checker *breach.Checker
+	if hibp != "" {
+		source, err := breach.NewSource(hibp)
+		if err != nil {
+			return err
+		}
+		checker = breach.NewChecker(source)
+	}
+
+	report, err := audit_client.NewService(log, db, checker).ReportForLogin(context.Background(), login, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -1,11 +1,17 @@
 package main
 
-import "goph-keeper/internal/api/client"
+import (
+	"fmt"
+	"goph-keeper/internal/api/client"
+	"os"
+)
 
 func main() {
 
-	// запускаем приложение
-	if err := client.RunClient(); err != nil {
-		panic(err)
+	// запускаем приложение, с подкомандой - без интерфейса
+	code, err := client.RunClient(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 	}
+	os.Exit(code)
 }
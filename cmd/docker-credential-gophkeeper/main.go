@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"goph-keeper/internal/api/client"
+	"os"
+)
+
+// Помощник docker-credential. Docker запускает его как `docker-credential-gophkeeper <операция>`
+// при "credsStore": "gophkeeper" в ~/.docker/config.json.
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: docker-credential-gophkeeper [flags] get|store|erase|list")
+		os.Exit(2)
+	}
+
+	// операция - последний аргумент, перед ней подставляется подкоманда клиента
+	op := args[len(args)-1]
+	args = append(args[:len(args)-1:len(args)-1], "docker-credential", op)
+
+	code, err := client.RunClient(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"goph-keeper/internal/api/client"
+	"os"
+)
+
+// Помощник git-credential. Git запускает его как `git-credential-goph-keeper [флаги] <операция>`
+// при `git config credential.helper goph-keeper`, флаги передаются клиенту как глобальные.
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: git-credential-goph-keeper [flags] get|store|erase")
+		os.Exit(2)
+	}
+
+	// операция - последний аргумент, перед ней подставляется подкоманда клиента
+	op := args[len(args)-1]
+	args = append(args[:len(args)-1:len(args)-1], "credential", op)
+
+	code, err := client.RunClient(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goph-keeper/internal/passgen"
+	"os"
+)
+
+func main() {
+	p := passgen.DefaultPolicy
+	pp := passgen.DefaultPassphrasePolicy
+
+	flag.IntVar(&p.Length, "length", p.Length, "password length")
+	flag.BoolVar(&p.Lower, "lower", p.Lower, "use lowercase letters")
+	flag.BoolVar(&p.Upper, "upper", p.Upper, "use uppercase letters")
+	flag.BoolVar(&p.Digits, "digits", p.Digits, "use digits")
+	flag.BoolVar(&p.Symbols, "symbols", p.Symbols, "use symbols")
+	flag.BoolVar(&p.ExcludeAmbiguous, "no-ambiguous", p.ExcludeAmbiguous, "exclude ambiguous characters (Il1O0...)")
+	passphrase := flag.Bool("passphrase", false, "generate a diceware passphrase instead of a password")
+	flag.IntVar(&pp.Words, "words", pp.Words, "number of words in the passphrase")
+	flag.StringVar(&pp.Separator, "separator", pp.Separator, "passphrase word separator")
+	flag.BoolVar(&pp.Capitalize, "capitalize", pp.Capitalize, "capitalize passphrase words")
+	count := flag.Int("n", 1, "number of passwords to generate")
+	flag.Parse()
+
+	var (
+		generate func() (string, error)
+		entropy  float64
+	)
+	if *passphrase {
+		generate = func() (string, error) { return passgen.Passphrase(pp) }
+		entropy = pp.Entropy()
+	} else {
+		generate = func() (string, error) { return passgen.Generate(p) }
+		entropy = p.Entropy()
+	}
+
+	for i := 0; i < *count; i++ {
+		password, err := generate()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		fmt.Println(password)
+	}
+
+	// оценка в stderr, чтобы stdout можно было передавать в другие команды
+	fmt.Fprintf(os.Stderr, "entropy: %.0f bits (%s)\n", entropy, passgen.Strength(entropy))
+}
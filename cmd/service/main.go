@@ -2,6 +2,7 @@ package main
 
 import (
 	"goph-keeper/internal/api/service"
+	"goph-keeper/internal/redact"
 	"log/slog"
 	"os"
 )
@@ -28,11 +29,13 @@ func setupLogger(env string) *slog.Logger {
 	case envLocal:
 		log = slog.New(slog.NewTextHandler(
 			os.Stdout, &slog.HandlerOptions{
-				Level: slog.LevelDebug}))
+				Level:       slog.LevelDebug,
+				ReplaceAttr: redact.ReplaceAttr}))
 	case envProd:
 		log = slog.New(slog.NewJSONHandler(
 			os.Stdout, &slog.HandlerOptions{
-				Level: slog.LevelInfo}))
+				Level:       slog.LevelInfo,
+				ReplaceAttr: redact.ReplaceAttr}))
 
 	}
 
@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/vaultcrypto"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeBackend struct {
+	items []models.Item
+	token string
+}
+
+func (b *fakeBackend) Resolve(_ context.Context, token, ref string) (models.Item, error) {
+	b.token = token
+	for _, item := range b.items {
+		if item.Title == ref {
+			return item, nil
+		}
+	}
+	return models.Item{}, ErrItemNotFound
+}
+
+func (b *fakeBackend) ListItems(_ context.Context, token string, _ models.ItemType) ([]models.Item, error) {
+	b.token = token
+	return append([]models.Item(nil), b.items...), nil
+}
+
+func sealedItem(t *testing.T, key []byte, title string, payload models.Payload) models.Item {
+	t.Helper()
+	plain, err := models.EncodePayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, wrapped, err := vaultcrypto.Seal(key, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := models.EncodePayload(models.Payload{Sealed: &models.SealedPayload{Ciphertext: ciphertext, WrappedKey: wrapped}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.Item{ID: 1, Type: models.ItemTypeLogin, Title: title, Payload: sealed}
+}
+
+func startAgent(t *testing.T, backend Backend, idle time.Duration) *Client {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent", "agent.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), backend, idle).Serve(ctx, ln)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+	return NewClient(path)
+}
+
+func TestAgent(t *testing.T) {
+	ctx := context.Background()
+	key := vaultcrypto.DeriveKey("alice", "master")
+	backend := &fakeBackend{items: []models.Item{
+		sealedItem(t, key, "github", models.Payload{Login: &models.LoginPayload{Login: "alice", Password: "pw"}}),
+	}}
+	client := startAgent(t, backend, 0)
+
+	info, err := os.Stat(client.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if _, err := client.Get(ctx, "github"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("locked agent: err = %v, want ErrLocked", err)
+	}
+
+	status, err := client.Unlock(ctx, "alice", "tok", key)
+	if err != nil || !status.Unlocked || !status.HasKey {
+		t.Fatalf("unlock: %+v, %v", status, err)
+	}
+
+	item, err := client.Get(ctx, "github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil || payload.Login == nil || payload.Login.Password != "pw" {
+		t.Errorf("item is not decrypted: %+v, %v", payload, err)
+	}
+	if backend.token != "tok" {
+		t.Errorf("backend got token %q", backend.token)
+	}
+
+	if _, err := client.Get(ctx, "missing"); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("err = %v, want ErrItemNotFound", err)
+	}
+
+	items, err := client.List(ctx, models.ItemTypeUnspecified)
+	if err != nil || len(items) != 1 {
+		t.Errorf("list: %v, %v", items, err)
+	}
+
+	if err := client.Lock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Token(ctx); !errors.Is(err, ErrLocked) {
+		t.Errorf("token after lock: err = %v, want ErrLocked", err)
+	}
+}
+
+func TestAgentIdleLock(t *testing.T) {
+	ctx := context.Background()
+	client := startAgent(t, &fakeBackend{}, 50*time.Millisecond)
+
+	if _, err := client.Unlock(ctx, "alice", "tok", nil); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := client.Token(ctx); err != nil || token != "tok" {
+		t.Fatalf("token = %q, %v", token, err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.Token(ctx); !errors.Is(err, ErrLocked) {
+		t.Errorf("err = %v, want ErrLocked after idle timeout", err)
+	}
+}
+
+func TestClientNotRunning(t *testing.T) {
+	client := NewClient(filepath.Join(t.TempDir(), "none.sock"))
+	if _, err := client.Status(context.Background()); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("err = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestListenRejectsRunningAgent(t *testing.T) {
+	client := startAgent(t, &fakeBackend{}, 0)
+	if _, err := Listen(client.Path()); err == nil {
+		t.Error("second agent must not take over the socket")
+	}
+}
+
+func TestListenRejectsInsecureDir(t *testing.T) {
+	shared := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(shared, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// каталог, открытый другим пользователям, например созданный заранее в /tmp
+	if err := os.Chmod(shared, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(filepath.Join(shared, "agent.sock")); !errors.Is(err, ErrInsecureSocket) {
+		t.Errorf("err = %v, want ErrInsecureSocket for mode 0777", err)
+	}
+
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(t.TempDir(), link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(filepath.Join(link, "agent.sock")); !errors.Is(err, ErrInsecureSocket) {
+		t.Errorf("err = %v, want ErrInsecureSocket for symlink", err)
+	}
+}
+
+func TestListenSocketMode(t *testing.T) {
+	client := startAgent(t, &fakeBackend{}, 0)
+
+	info, err := os.Stat(client.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket mode = %04o, want 0600", perm)
+	}
+	dir, err := os.Stat(filepath.Dir(client.Path()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := dir.Mode().Perm(); perm != 0o700 {
+		t.Errorf("socket dir mode = %04o, want 0700", perm)
+	}
+}
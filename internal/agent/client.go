@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"goph-keeper/internal/models"
+	"net"
+	"os"
+	"syscall"
+)
+
+// Client - клиент агента.
+type Client struct {
+	path string
+}
+
+// NewClient - клиент агента на сокете path.
+func NewClient(path string) *Client {
+	return &Client{path: path}
+}
+
+// Path - путь к сокету агента.
+func (c *Client) Path() string {
+	return c.path
+}
+
+// do - отправляет запрос и читает ответ. Если агент не запущен - ErrNotRunning.
+func (c *Client) do(ctx context.Context, req Request) (Response, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return Response{}, ErrNotRunning
+		}
+		return Response{}, err
+	}
+	defer conn.Close()
+
+	// токен и ключ уходят только агенту того же пользователя
+	if err := checkPeer(conn); err != nil {
+		return Response{}, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, err
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, err
+	}
+	if resp.Error != "" {
+		return resp, codeError(resp.Code, resp.Error)
+	}
+	return resp, nil
+}
+
+// Status - состояние агента.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	resp, err := c.do(ctx, Request{Op: OpStatus})
+	if err != nil || resp.Status == nil {
+		return Status{}, err
+	}
+	return *resp.Status, nil
+}
+
+// Unlock - передает агенту токен сессии и ключ хранилища (может быть nil).
+func (c *Client) Unlock(ctx context.Context, login, token string, key []byte) (Status, error) {
+	resp, err := c.do(ctx, Request{Op: OpUnlock, Login: login, Token: token, Key: key})
+	if err != nil || resp.Status == nil {
+		return Status{}, err
+	}
+	return *resp.Status, nil
+}
+
+// Lock - блокирует агента.
+func (c *Client) Lock(ctx context.Context) error {
+	_, err := c.do(ctx, Request{Op: OpLock})
+	return err
+}
+
+// Token - токен сессии из агента.
+func (c *Client) Token(ctx context.Context) (string, error) {
+	resp, err := c.do(ctx, Request{Op: OpToken})
+	return resp.Token, err
+}
+
+// Get - запись по id или названию, расшифрованная агентом.
+func (c *Client) Get(ctx context.Context, ref string) (models.Item, error) {
+	resp, err := c.do(ctx, Request{Op: OpGet, Ref: ref})
+	if err != nil {
+		return models.Item{}, err
+	}
+	if resp.Item == nil {
+		return models.Item{}, ErrItemNotFound
+	}
+	return *resp.Item, nil
+}
+
+// List - все записи типа itemType (ItemTypeUnspecified - все типы).
+func (c *Client) List(ctx context.Context, itemType models.ItemType) ([]models.Item, error) {
+	resp, err := c.do(ctx, Request{Op: OpList, Type: itemType})
+	return resp.Items, err
+}
+
+// Pending - запросы подписи SSH, ожидающие подтверждения.
+func (c *Client) Pending(ctx context.Context) ([]SignRequest, error) {
+	resp, err := c.do(ctx, Request{Op: OpPending})
+	return resp.Pending, err
+}
+
+// Confirm - разрешает или отклоняет запрос подписи.
+func (c *Client) Confirm(ctx context.Context, id uint64, allow bool) error {
+	_, err := c.do(ctx, Request{Op: OpConfirm, ID: id, Allow: allow})
+	return err
+}
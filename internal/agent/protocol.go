@@ -0,0 +1,115 @@
+// Package agent - локальный агент клиента: держит токен сессии и ключ хранилища в памяти
+// и отвечает на запросы get и list по Unix-сокету, доступному только владельцу.
+//
+// Протокол - одна строка JSON с Request и одна строка JSON с Response на соединение.
+package agent
+
+import (
+	"errors"
+	"goph-keeper/internal/models"
+	"time"
+)
+
+// Операции протокола.
+const (
+	OpStatus = "status"
+	OpUnlock = "unlock"
+	OpLock   = "lock"
+	OpToken  = "token"
+	OpGet    = "get"
+	OpList   = "list"
+
+	// OpPending и OpConfirm - подтверждение подписей SSH
+	OpPending = "pending"
+	OpConfirm = "confirm"
+)
+
+// Коды ошибок в ответе.
+const (
+	codeLocked    = "locked"
+	codeNotFound  = "not_found"
+	codeAmbiguous = "ambiguous"
+	codeInvalid   = "invalid"
+	codeDenied    = "denied"
+	codeInternal  = "internal"
+)
+
+var (
+	ErrLocked         = errors.New("agent is locked, run `unlock`")
+	ErrNotRunning     = errors.New("agent is not running")
+	ErrItemNotFound   = errors.New("item not found")
+	ErrAmbiguousName  = errors.New("several items have this title, use id")
+	ErrInvalidRequest = errors.New("invalid agent request")
+	ErrInsecureSocket = errors.New("insecure agent socket directory")
+	ErrForeignPeer    = errors.New("agent socket peer belongs to another user")
+)
+
+// Request - запрос к агенту.
+type Request struct {
+	Op    string          `json:"op"`
+	Ref   string          `json:"ref,omitempty"`
+	Type  models.ItemType `json:"type,omitempty"`
+	Login string          `json:"login,omitempty"`
+	Token string          `json:"token,omitempty"`
+	Key   []byte          `json:"key,omitempty"`
+	ID    uint64          `json:"id,omitempty"`
+	Allow bool            `json:"allow,omitempty"`
+}
+
+// Response - ответ агента. Error заполнен, если запрос не выполнен.
+type Response struct {
+	Error  string        `json:"error,omitempty"`
+	Code   string        `json:"code,omitempty"`
+	Status *Status       `json:"status,omitempty"`
+	Token  string        `json:"token,omitempty"`
+	Item   *models.Item  `json:"item,omitempty"`
+	Items  []models.Item `json:"items,omitempty"`
+
+	Pending []SignRequest `json:"pending,omitempty"`
+}
+
+// Status - состояние агента.
+type Status struct {
+	Unlocked    bool          `json:"unlocked"`
+	Login       string        `json:"login,omitempty"`
+	HasKey      bool          `json:"has_key"`
+	IdleTimeout time.Duration `json:"idle_timeout"`
+	LockAt      time.Time     `json:"lock_at,omitempty"`
+	Confirm     bool          `json:"confirm"`
+}
+
+// errorCode - код ответа для ошибки.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrLocked):
+		return codeLocked
+	case errors.Is(err, ErrItemNotFound):
+		return codeNotFound
+	case errors.Is(err, ErrAmbiguousName):
+		return codeAmbiguous
+	case errors.Is(err, ErrInvalidRequest):
+		return codeInvalid
+	case errors.Is(err, ErrSigningDenied):
+		return codeDenied
+	default:
+		return codeInternal
+	}
+}
+
+// codeError - ошибка по коду ответа.
+func codeError(code, msg string) error {
+	switch code {
+	case codeLocked:
+		return ErrLocked
+	case codeNotFound:
+		return ErrItemNotFound
+	case codeAmbiguous:
+		return ErrAmbiguousName
+	case codeInvalid:
+		return ErrInvalidRequest
+	case codeDenied:
+		return ErrSigningDenied
+	default:
+		return errors.New(msg)
+	}
+}
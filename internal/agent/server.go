@@ -0,0 +1,281 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/vaultcrypto"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// maxRequestSize - ограничение размера строки запроса.
+const maxRequestSize = 64 * 1024
+
+// Backend - источник записей агента, обычно локальный кэш клиента.
+type Backend interface {
+	Resolve(ctx context.Context, token, ref string) (models.Item, error)
+	ListItems(ctx context.Context, token string, itemType models.ItemType) ([]models.Item, error)
+}
+
+// Server - агент. Токен и ключ хранилища живут только в памяти и стираются
+// блокировкой: по запросу lock или после IdleTimeout без обращений.
+type Server struct {
+	log     *slog.Logger
+	backend Backend
+	idle    time.Duration
+	now     func() time.Time
+
+	mu     sync.Mutex
+	login  string
+	token  string
+	key    []byte
+	lockAt time.Time
+	timer  *time.Timer
+
+	// confirmTimeout, pending - подтверждение подписей SSH, см. ConfirmSigning
+	confirmTimeout time.Duration
+	pending        map[uint64]*pendingSign
+	nextSignID     uint64
+}
+
+// NewServer - конструктор агента. idle == 0 - не блокироваться по простою.
+func NewServer(log *slog.Logger, backend Backend, idle time.Duration) *Server {
+	return &Server{
+		log:     log,
+		backend: backend,
+		idle:    idle,
+		now:     time.Now,
+	}
+}
+
+// Serve - принимает соединения, пока не отменен ctx. При выходе агент блокируется.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	defer s.Lock()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := checkPeer(conn); err != nil {
+			s.log.Warn("rejected agent connection", "error", err)
+			conn.Close()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn - обрабатывает один запрос.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	var resp Response
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+
+	var req Request
+	switch {
+	case !scanner.Scan():
+		return
+	case json.Unmarshal(scanner.Bytes(), &req) != nil:
+		resp = errorResponse(ErrInvalidRequest)
+	default:
+		resp = s.Handle(ctx, req)
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		s.log.Error("failed to write agent response", "error", err)
+	}
+}
+
+// Handle - выполняет запрос.
+func (s *Server) Handle(ctx context.Context, req Request) Response {
+	switch req.Op {
+	case OpStatus:
+		return Response{Status: s.status()}
+	case OpUnlock:
+		if req.Token == "" {
+			return errorResponse(ErrInvalidRequest)
+		}
+		if req.Key != nil && len(req.Key) != vaultcrypto.KeySize {
+			return errorResponse(ErrInvalidRequest)
+		}
+		s.unlock(req.Login, req.Token, req.Key)
+		return Response{Status: s.status()}
+	case OpLock:
+		s.Lock()
+		return Response{Status: s.status()}
+	case OpPending:
+		return Response{Pending: s.pendingRequests()}
+	case OpConfirm:
+		if err := s.decide(req.ID, req.Allow); err != nil {
+			return errorResponse(err)
+		}
+		return Response{}
+	}
+
+	token, key, err := s.use()
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	switch req.Op {
+	case OpToken:
+		return Response{Token: token}
+	case OpGet:
+		item, err := s.backend.Resolve(ctx, token, req.Ref)
+		if err != nil {
+			return errorResponse(err)
+		}
+		if item, err = open(item, key); err != nil {
+			return errorResponse(err)
+		}
+		return Response{Item: &item}
+	case OpList:
+		items, err := s.backend.ListItems(ctx, token, req.Type)
+		if err != nil {
+			return errorResponse(err)
+		}
+		for i := range items {
+			if items[i], err = open(items[i], key); err != nil {
+				return errorResponse(err)
+			}
+		}
+		return Response{Items: items}
+	default:
+		return errorResponse(fmt.Errorf("%w: unknown operation %q", ErrInvalidRequest, req.Op))
+	}
+}
+
+// errorResponse - ответ с ошибкой.
+func errorResponse(err error) Response {
+	return Response{Error: err.Error(), Code: errorCode(err)}
+}
+
+// unlock - запоминает токен и ключ и запускает таймер простоя.
+func (s *Server) unlock(login, token string, key []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.wipe()
+	s.login = login
+	s.token = token
+	if key != nil {
+		s.key = append([]byte(nil), key...)
+	}
+	s.touch()
+	s.log.Info("agent unlocked", "login", login)
+}
+
+// Lock - стирает токен и ключ.
+func (s *Server) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" {
+		s.log.Info("agent locked", "login", s.login)
+	}
+	s.wipe()
+}
+
+// wipe - обнуляет секреты. Вызывается под s.mu.
+func (s *Server) wipe() {
+	for i := range s.key {
+		s.key[i] = 0
+	}
+	s.key = nil
+	s.token = ""
+	s.login = ""
+	s.lockAt = time.Time{}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// touch - переносит момент блокировки по простою. Вызывается под s.mu.
+func (s *Server) touch() {
+	if s.idle <= 0 {
+		return
+	}
+	s.lockAt = s.now().Add(s.idle)
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.idle, s.Lock)
+		return
+	}
+	s.timer.Reset(s.idle)
+}
+
+// use - токен и копия ключа для запроса, продлевает работу без блокировки.
+func (s *Server) use() (string, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" {
+		return "", nil, ErrLocked
+	}
+	s.touch()
+	return s.token, append([]byte(nil), s.key...), nil
+}
+
+// status - состояние агента.
+func (s *Server) status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &Status{
+		Unlocked:    s.token != "",
+		Login:       s.login,
+		HasKey:      len(s.key) > 0,
+		IdleTimeout: s.idle,
+		LockAt:      s.lockAt,
+		Confirm:     s.confirmTimeout > 0,
+	}
+}
+
+// open - расшифровывает запись, зашифрованную на клиенте: внутри sealed лежит
+// сериализованный models.Payload. Без ключа запись возвращается как есть.
+func open(item models.Item, key []byte) (models.Item, error) {
+	if len(key) == 0 {
+		return item, nil
+	}
+
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil || payload.Sealed == nil {
+		return item, nil
+	}
+
+	plain, err := vaultcrypto.Open(key, payload.Sealed.Ciphertext, payload.Sealed.WrappedKey)
+	if err != nil {
+		if errors.Is(err, vaultcrypto.ErrDecryptFailed) {
+			return models.Item{}, fmt.Errorf("item %d: %w", item.ID, err)
+		}
+		return models.Item{}, err
+	}
+
+	item.Payload = plain
+	return item, nil
+}
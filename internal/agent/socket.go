@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// EnvSocket - переменная окружения с путем к сокету агента.
+const EnvSocket = "GOPH_KEEPER_AGENT_SOCK"
+
+// SocketPath - путь к сокету: из GOPH_KEEPER_AGENT_SOCK, иначе в $XDG_RUNTIME_DIR
+// или во временном каталоге пользователя.
+func SocketPath() string {
+	if path := os.Getenv(EnvSocket); path != "" {
+		return path
+	}
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), fmt.Sprintf("goph-keeper-%d", os.Getuid()))
+	} else {
+		dir = filepath.Join(dir, "goph-keeper")
+	}
+	return filepath.Join(dir, "agent.sock")
+}
+
+// Listen - открывает сокет агента. Каталог сокета создается с правами 0700, существующий
+// каталог должен принадлежать пользователю и быть закрыт для остальных: иначе чужой процесс
+// может подменить сокет и получить токен и ключ хранилища при unlock. Сокет сразу создается
+// с правами 0600. Сокет, оставшийся от завершившегося агента, удаляется; работающий агент - ошибка.
+func Listen(path string) (net.Listener, error) {
+	if err := secureDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("agent is already running on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return listenUnix(path)
+}
+
+// secureDir - создает каталог сокета или проверяет существующий. Ссылка на каталог
+// не принимается: ее владелец мог бы подменить цель после проверки.
+func secureDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrInsecureSocket, dir)
+	}
+	return checkDir(dir, info)
+}
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package agent
+
+import (
+	"net"
+	"os"
+)
+
+// checkDir - без прав доступа unix каталог не проверяется.
+func checkDir(dir string, info os.FileInfo) error {
+	return nil
+}
+
+// listenUnix - открывает сокет и оставляет доступ к нему только владельцу.
+func listenUnix(path string) (net.Listener, error) {
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// checkDir - каталог сокета должен принадлежать текущему пользователю и быть закрыт для остальных.
+func checkDir(dir string, info os.FileInfo) error {
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Errorf("%w: %s is accessible by other users (mode %04o)", ErrInsecureSocket, dir, perm)
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%w: %s is owned by another user", ErrInsecureSocket, dir)
+	}
+	return nil
+}
+
+// listenUnix - открывает сокет под umask 0177, чтобы он создавался сразу с правами 0600,
+// а не получал их после Listen. Umask общий для процесса, но в это время агент только
+// запускается и других файлов не создает.
+func listenUnix(path string) (net.Listener, error) {
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+
+	return net.Listen("unix", path)
+}
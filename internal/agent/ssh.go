@@ -0,0 +1,321 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/sshkey"
+	"net"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSigningDenied  = errors.New("signing request denied")
+	errReadOnlyKeys   = errors.New("keys are managed in the vault, use the client to change them")
+	errSSHLockNotUsed = errors.New("use `lock` and `unlock` of the client")
+)
+
+// sshRequestTimeout - ограничение на чтение ключей из хранилища для одного запроса ssh.
+const sshRequestTimeout = 10 * time.Second
+
+// SignRequest - запрос подписи, ожидающий подтверждения.
+type SignRequest struct {
+	ID          uint64    `json:"id"`
+	Title       string    `json:"title"`
+	Comment     string    `json:"comment,omitempty"`
+	Fingerprint string    `json:"fingerprint"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// pendingSign - запрос подписи и канал решения.
+type pendingSign struct {
+	req      SignRequest
+	decision chan bool
+}
+
+// sshKey - SSH-ключ из хранилища.
+type sshKey struct {
+	title string
+	key   models.SSHKeyPayload
+	pub   ssh.PublicKey
+}
+
+// ConfirmSigning - каждая подпись ждет подтверждения через OpConfirm не дольше timeout,
+// без ответа запрос отклоняется. timeout == 0 - подписывать без подтверждения.
+func (s *Server) ConfirmSigning(timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.confirmTimeout = timeout
+}
+
+// ServeSSH - обслуживает протокол ssh-agent на ln, пока не отменен ctx.
+func (s *Server) ServeSSH(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := checkPeer(conn); err != nil {
+			s.log.Warn("rejected agent connection", "error", err)
+			conn.Close()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			if err := sshagent.ServeAgent(sshAgent{s: s, ctx: ctx}, conn); err != nil && !errors.Is(err, net.ErrClosed) {
+				s.log.Debug("ssh agent connection closed", "error", err)
+			}
+		}()
+	}
+}
+
+// sshKeys - SSH-ключи пользователя, для зашифрованных записей нужен ключ хранилища.
+func (s *Server) sshKeys(ctx context.Context) ([]sshKey, error) {
+	token, vaultKey, err := s.use()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.backend.ListItems(ctx, token, models.ItemTypeSSHKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]sshKey, 0, len(items))
+	for _, stored := range items {
+		item, err := open(stored, vaultKey)
+		if err != nil {
+			s.log.Warn("failed to open ssh key item", "id", stored.ID, "error", err)
+			continue
+		}
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil || payload.SSHKey == nil {
+			continue
+		}
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(payload.SSHKey.PublicKey))
+		if err != nil {
+			s.log.Warn("invalid ssh public key", "id", item.ID, "error", err)
+			continue
+		}
+		keys = append(keys, sshKey{title: item.Title, key: *payload.SSHKey, pub: pub})
+	}
+	return keys, nil
+}
+
+// confirm - ждет решения по запросу подписи, если подтверждение включено.
+func (s *Server) confirm(ctx context.Context, req SignRequest) bool {
+	s.mu.Lock()
+	timeout := s.confirmTimeout
+	if timeout <= 0 {
+		s.mu.Unlock()
+		return true
+	}
+	if s.pending == nil {
+		s.pending = make(map[uint64]*pendingSign)
+	}
+	s.nextSignID++
+	req.ID = s.nextSignID
+	p := &pendingSign{req: req, decision: make(chan bool, 1)}
+	s.pending[req.ID] = p
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, req.ID)
+		s.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case allow := <-p.decision:
+		return allow
+	case <-timer.C:
+		s.log.Warn("ssh sign request timed out", "request", req.ID, "title", req.Title)
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// pendingRequests - запросы подписи, ожидающие подтверждения, от старых к новым.
+func (s *Server) pendingRequests() []SignRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SignRequest, 0, len(s.pending))
+	for _, p := range s.pending {
+		out = append(out, p.req)
+	}
+	for i := 1; i < len(out); i++ {
+		for j := i; j > 0 && out[j].ID < out[j-1].ID; j-- {
+			out[j], out[j-1] = out[j-1], out[j]
+		}
+	}
+	return out
+}
+
+// decide - решение по запросу подписи.
+func (s *Server) decide(id uint64, allow bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.pending[id]
+	if !ok {
+		return fmt.Errorf("%w: no pending request %d", ErrInvalidRequest, id)
+	}
+	select {
+	case p.decision <- allow:
+	default:
+	}
+	return nil
+}
+
+// sshAgent - реализация протокола ssh-agent поверх ключей хранилища. Ключи только
+// читаются: добавлять и удалять их нужно через клиент.
+type sshAgent struct {
+	s   *Server
+	ctx context.Context
+}
+
+var _ sshagent.ExtendedAgent = sshAgent{}
+
+// List - открытые ключи. У заблокированного агента ключей нет.
+func (a sshAgent) List() ([]*sshagent.Key, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, sshRequestTimeout)
+	defer cancel()
+
+	keys, err := a.s.sshKeys(ctx)
+	if errors.Is(err, ErrLocked) {
+		return nil, nil
+	}
+	if err != nil {
+		a.s.log.Error("failed to list ssh keys", "error", err)
+		return nil, err
+	}
+
+	out := make([]*sshagent.Key, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, &sshagent.Key{Format: k.pub.Type(), Blob: k.pub.Marshal(), Comment: k.key.Comment})
+	}
+	return out, nil
+}
+
+// Sign - подпись алгоритмом ключа по умолчанию.
+func (a sshAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags - подписывает данные ключом из хранилища. Каждый запрос пишется в лог
+// и при включенном подтверждении ждет решения.
+func (a sshAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags sshagent.SignatureFlags) (*ssh.Signature, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, sshRequestTimeout)
+	keys, err := a.s.sshKeys(ctx)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *sshKey
+	for i := range keys {
+		if bytes.Equal(keys[i].pub.Marshal(), key.Marshal()) {
+			found = &keys[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, ErrItemNotFound
+	}
+
+	req := SignRequest{
+		Title:       found.title,
+		Comment:     found.key.Comment,
+		Fingerprint: found.key.Fingerprint,
+		RequestedAt: a.s.now(),
+	}
+	a.s.log.Info("ssh sign request", "title", req.Title, "fingerprint", req.Fingerprint, "comment", req.Comment)
+
+	if !a.s.confirm(a.ctx, req) {
+		a.s.log.Warn("ssh sign request denied", "title", req.Title, "fingerprint", req.Fingerprint)
+		return nil, ErrSigningDenied
+	}
+
+	signer, err := sshkey.Signer(found.key)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig *ssh.Signature
+	algorithmSigner, ok := signer.(ssh.AlgorithmSigner)
+	switch {
+	case ok && flags&sshagent.SignatureFlagRsaSha512 != 0:
+		sig, err = algorithmSigner.SignWithAlgorithm(nil, data, ssh.KeyAlgoRSASHA512)
+	case ok && flags&sshagent.SignatureFlagRsaSha256 != 0:
+		sig, err = algorithmSigner.SignWithAlgorithm(nil, data, ssh.KeyAlgoRSASHA256)
+	default:
+		sig, err = signer.Sign(nil, data)
+	}
+	if err != nil {
+		a.s.log.Error("failed to sign", "title", req.Title, "error", err)
+		return nil, err
+	}
+
+	a.s.log.Info("ssh signature issued", "title", req.Title, "fingerprint", req.Fingerprint)
+	return sig, nil
+}
+
+// Add - не поддерживается.
+func (a sshAgent) Add(sshagent.AddedKey) error {
+	return errReadOnlyKeys
+}
+
+// Remove - не поддерживается.
+func (a sshAgent) Remove(ssh.PublicKey) error {
+	return errReadOnlyKeys
+}
+
+// RemoveAll - не поддерживается.
+func (a sshAgent) RemoveAll() error {
+	return errReadOnlyKeys
+}
+
+// Lock - блокирует агента (ssh-add -x), парольная фраза не используется.
+func (a sshAgent) Lock([]byte) error {
+	a.s.Lock()
+	return nil
+}
+
+// Unlock - не поддерживается: для разблокировки нужен вход на сервер.
+func (a sshAgent) Unlock([]byte) error {
+	return errSSHLockNotUsed
+}
+
+// Signers - не поддерживается, ключи не покидают агента.
+func (a sshAgent) Signers() ([]ssh.Signer, error) {
+	return nil, errReadOnlyKeys
+}
+
+// Extension - расширения протокола не поддерживаются.
+func (a sshAgent) Extension(string, []byte) ([]byte, error) {
+	return nil, sshagent.ErrExtensionUnsupported
+}
@@ -0,0 +1,170 @@
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/sshkey"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func sshKeyItem(t *testing.T, title string) (models.Item, ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := sshkey.FromPrivateKey(pem.EncodeToMemory(block), nil, "alice@laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := models.EncodePayload(models.Payload{SSHKey: &key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.Item{ID: 2, Type: models.ItemTypeSSHKey, Title: title, Payload: payload}, pub
+}
+
+func startSSHAgent(t *testing.T, backend Backend, confirm time.Duration) (*Client, sshagent.ExtendedAgent) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "agent")
+	path := filepath.Join(dir, "agent.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshLn, err := Listen(filepath.Join(dir, "ssh.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), backend, 0)
+	server.ConfirmSigning(confirm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 2)
+	go func() { done <- server.Serve(ctx, ln) }()
+	go func() { done <- server.ServeSSH(ctx, sshLn) }()
+
+	conn, err := net.Dial("unix", filepath.Join(dir, "ssh.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		for range 2 {
+			if err := <-done; err != nil {
+				t.Errorf("serve: %v", err)
+			}
+		}
+	})
+	return NewClient(path), sshagent.NewClient(conn)
+}
+
+func TestSSHAgent(t *testing.T) {
+	ctx := context.Background()
+	item, pub := sshKeyItem(t, "laptop")
+	client, ssh := startSSHAgent(t, &fakeBackend{items: []models.Item{item}}, 0)
+
+	keys, err := ssh.List()
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("locked agent lists keys: %v, %v", keys, err)
+	}
+	if _, err := ssh.Sign(pub, []byte("data")); err == nil {
+		t.Fatal("locked agent signed data")
+	}
+
+	if _, err := client.Unlock(ctx, "alice", "tok", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err = ssh.List()
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("list: %v, %v", keys, err)
+	}
+	if keys[0].Comment != "alice@laptop" || string(keys[0].Blob) != string(pub.Marshal()) {
+		t.Errorf("key = %v", keys[0])
+	}
+
+	sig, err := ssh.Sign(pub, []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.Verify([]byte("data"), sig); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+
+	if err := ssh.Add(sshagent.AddedKey{PrivateKey: ed25519.PrivateKey(make([]byte, ed25519.PrivateKeySize))}); err == nil {
+		t.Error("agent must not accept keys over ssh protocol")
+	}
+
+	if err := ssh.Lock(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Token(ctx); !errors.Is(err, ErrLocked) {
+		t.Errorf("ssh-add -x did not lock the agent: %v", err)
+	}
+}
+
+func TestSSHAgentConfirm(t *testing.T) {
+	ctx := context.Background()
+	item, pub := sshKeyItem(t, "laptop")
+	client, ssh := startSSHAgent(t, &fakeBackend{items: []models.Item{item}}, time.Second)
+
+	if _, err := client.Unlock(ctx, "alice", "tok", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	decide := func(allow bool) {
+		for {
+			pending, err := client.Pending(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(pending) > 0 {
+				if pending[0].Title != "laptop" {
+					t.Errorf("pending = %+v", pending)
+				}
+				if err := client.Confirm(ctx, pending[0].ID, allow); err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	go decide(false)
+	if _, err := ssh.Sign(pub, []byte("data")); err == nil {
+		t.Error("denied request was signed")
+	}
+
+	go decide(true)
+	if _, err := ssh.Sign(pub, []byte("data")); err != nil {
+		t.Errorf("confirmed request: %v", err)
+	}
+
+	if err := client.Confirm(ctx, 100, true); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("unknown request: err = %v, want ErrInvalidRequest", err)
+	}
+}
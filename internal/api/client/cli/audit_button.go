@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/audit"
+	"strings"
+)
+
+// auditReport - экран отчета о слабых, повторяющихся и старых паролях.
+func (c *CLI) auditReport(ctx context.Context, app *tview.Application, pages *tview.Pages) {
+	view := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true)
+	view.SetBorder(true).SetTitle("Security audit").SetTitleAlign(tview.AlignLeft)
+
+	report, err := c.audit.Report(ctx, c.token, audit.DefaultOptions)
+	if err != nil {
+		c.log.Error("failed to build audit report", "error", err)
+		view.SetText("[red]Не удалось построить отчет[-]")
+	} else {
+		view.SetText(formatReport(report))
+	}
+
+	form := tview.NewForm().
+		AddButton("Back", func() {
+			pages.RemovePage("Audit")
+			pages.SwitchToPage("Buttons_data")
+		}).
+		AddButton("Quit", func() {
+			app.Stop()
+		})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, false).
+		AddItem(form, 3, 0, true)
+
+	pages.AddPage("Audit", flex, true, true)
+}
+
+// formatReport - текст отчета аудита для экрана.
+func formatReport(r audit.Report) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Проверено паролей: %d\n\n", r.Total)
+
+	fmt.Fprintf(&sb, "[yellow]Слабые пароли (%d), порог %.0f бит:[-]\n", len(r.Weak), r.MinEntropy)
+	for _, f := range r.Weak {
+		fmt.Fprintf(&sb, "  %s - %.0f бит: %s\n", findingName(f), f.Entropy, strings.Join(f.Reasons, ", "))
+	}
+
+	fmt.Fprintf(&sb, "\n[yellow]Повторяющиеся пароли (%d групп):[-]\n", len(r.Reused))
+	for _, g := range r.Reused {
+		names := make([]string, len(g.Items))
+		for i, f := range g.Items {
+			names[i] = findingName(f)
+		}
+		fmt.Fprintf(&sb, "  %d записей: %s\n", g.Count, strings.Join(names, ", "))
+	}
+
+	if r.BreachChecked {
+		fmt.Fprintf(&sb, "\n[red]Пароли из утечек (%d):[-]\n", len(r.Breached))
+		for _, f := range r.Breached {
+			fmt.Fprintf(&sb, "  %s - найден %d раз\n", findingName(f), f.Breaches)
+		}
+	} else {
+		sb.WriteString("\nПроверка по базе утечек отключена (флаг -hibp)\n")
+	}
+
+	fmt.Fprintf(&sb, "\n[yellow]Старые пароли (%d), старше %d дней:[-]\n", len(r.Old), r.MaxAgeDays)
+	for _, f := range r.Old {
+		fmt.Fprintf(&sb, "  %s - %d дней\n", findingName(f), f.AgeDays)
+	}
+
+	return sb.String()
+}
+
+// findingName - название записи в отчете.
+func findingName(f audit.Finding) string {
+	name := f.Title
+	if f.Login != "" {
+		name += " (" + f.Login + ")"
+	}
+	return tview.Escape(name)
+}
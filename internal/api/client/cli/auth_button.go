@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"fmt"
 	"github.com/rivo/tview"
 )
 
@@ -19,14 +18,12 @@ func (c *CLI) register(ctx context.Context, app *tview.Application, pages *tview
 		AddInputField("Login", "", 20, nil, func(text string) {
 			reg.Login = text
 		}).
-		AddInputField("Password", "", 20, nil, func(text string) {
+		AddFormItem(secretField("Password", "", 20, func(text string) {
 			reg.Password = text
-		}).
+		})).
 		AddButton("Save", func() {
 			ok := c.registerAPI(ctx, reg)
 
-			fmt.Println(ok)
-
 			if ok {
 				pages.AddPage("AuthUser", c.authUser(ctx, app, pages), true, false)
 				pages.SwitchToPage("AuthUser")
@@ -61,15 +58,16 @@ func (c *CLI) authUser(ctx context.Context, app *tview.Application, pages *tview
 		AddInputField("Login", "", 20, nil, func(text string) {
 			reg.Login = text
 		}).
-		AddInputField("Password", "", 20, nil, func(text string) {
+		AddFormItem(secretField("Password", "", 20, func(text string) {
 			reg.Password = text
-		}).
+		})).
 		AddButton("Save", func() {
 			token, err := c.auth.AuthUser(ctx, c.conn, reg.Login, reg.Password)
 			if err != nil {
 				c.errorsAuth(ctx, app, pages)
 			} else {
 				c.token = token
+				c.login = reg.Login
 				pages.AddPage("Buttons_data", c.buttonsData(ctx, app, pages), true, false)
 				pages.SwitchToPage("Buttons_data")
 			}
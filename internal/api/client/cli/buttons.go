@@ -55,6 +55,12 @@ func (c *CLI) buttonsData(ctx context.Context, app *tview.Application, pages *tv
 			pages.AddPage("Card", c.cardButton(ctx, app, pages), true, false)
 			pages.SwitchToPage("Card")
 		}).
+		AddButton("Audit", func() {
+			c.auditReport(ctx, app, pages)
+		}).
+		AddButton("Shared", func() {
+			c.sharedUnlock(ctx, app, pages)
+		}).
 		AddButton("Quit", func() {
 			app.Stop()
 		})
@@ -66,7 +72,9 @@ func (c *CLI) buttonsData(ctx context.Context, app *tview.Application, pages *tv
 		"2. Credentials: Если вы хотите сохранить данные\n" +
 		"3. Text: Если вы хотите сохранить текст\n" +
 		"4. Binary: Если вы хотите сохранить бинарные данные\n" +
-		"5. Card: Если вы хотите сохранить данные карты\n"))
+		"5. Card: Если вы хотите сохранить данные карты\n" +
+		"6. Audit: Если вы хотите проверить пароли на надежность\n" +
+		"7. Shared: Если вы хотите посмотреть записи, которые вам открыли другие пользователи\n"))
 
 	return form
 }
@@ -2,7 +2,11 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/rivo/tview"
+	"goph-keeper/internal/breach"
+	"goph-keeper/internal/redact"
 	"log/slog"
 )
 
@@ -20,6 +24,10 @@ func (c *CLI) credentials(ctx context.Context, app *tview.Application, pages *tv
 	c.log.Info("start credentials")
 	var resource Resource
 
+	passwordField := secretField("Password", "", 20, func(text string) {
+		resource.Password = text
+	})
+
 	form := tview.NewForm()
 	form.
 		AddInputField("Resource", "", 20, nil, func(text string) {
@@ -28,13 +36,17 @@ func (c *CLI) credentials(ctx context.Context, app *tview.Application, pages *tv
 		AddInputField("Login", "", 20, nil, func(text string) {
 			resource.Login = text
 		}).
-		AddInputField("Password", "", 20, nil, func(text string) {
-			resource.Password = text
-		}).
+		AddFormItem(passwordField).
 		AddButton("Save", func() {
 			// Открываем модальное окно подтверждения
 			c.saveResource(ctx, app, pages, form, &resource)
 		}).
+		AddButton("Generate", func() {
+			// Сгенерированный пароль сразу попадает в поле Password
+			c.passwordGenerator(app, pages, "Credentials", func(password string) {
+				passwordField.SetText(password)
+			})
+		}).
 		AddButton("Quit", func() {
 			app.Stop()
 		})
@@ -53,11 +65,13 @@ func (c *CLI) saveResource(
 ) {
 	c.log.Info("cli.saveResource Start")
 
+	text := "Вы хотите сохранить данные?\n" +
+		"Resource: " + resource.Resource + "\n" +
+		"Login: " + resource.Login + "\n" +
+		"Password: " + redact.Mask(resource.Password)
+
 	modal := tview.NewModal().
-		SetText("Вы хотите сохранить данные?\n" +
-			"Resource: " + resource.Resource + "\n" +
-			"Login: " + resource.Login + "\n" +
-			"Password: " + resource.Password).
+		SetText(text).
 		AddButtons([]string{"Save", "Correct", "Cancel"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			pages.RemovePage("SaveConfirmation") // Удаляем страницу с модальным окном
@@ -82,6 +96,21 @@ func (c *CLI) saveResource(
 
 	// Добавляем модальное окно как новую страницу
 	pages.AddPage("SaveConfirmation", modal, true, true)
+
+	// Предупреждаем, если пароль есть в базе утечек. Запрос идет по сети, поэтому
+	// выполняется в фоне, а предупреждение дописывается в окно, когда придет ответ.
+	password := resource.Password
+	go func() {
+		count, err := c.breach.Check(ctx, password)
+		switch {
+		case err == nil && count > 0:
+			app.QueueUpdateDraw(func() {
+				modal.SetText(text + fmt.Sprintf("\n\nВнимание: пароль найден в утечках %d раз", count))
+			})
+		case err != nil && !errors.Is(err, breach.ErrDisabled):
+			c.log.Error("failed to check password in breaches", "error", err)
+		}
+	}()
 }
 
 // Сбрасывает данные в форме и структуре
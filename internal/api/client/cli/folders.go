@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/models"
+	"sort"
+)
+
+// folderNode - папка дерева с путем от корня.
+type folderNode struct {
+	folder models.Folder
+	path   string
+}
+
+// sortedFolders - папки в порядке обхода дерева: родитель перед вложенными,
+// соседние по имени. Папки с потерянным родителем не попадают в результат.
+func sortedFolders(folders []models.Folder) []folderNode {
+	children := make(map[int64][]models.Folder)
+	for _, f := range folders {
+		children[f.ParentID] = append(children[f.ParentID], f)
+	}
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+
+	var (
+		out   []folderNode
+		visit func(parent int64, prefix string)
+		seen  = make(map[int64]bool)
+	)
+	visit = func(parent int64, prefix string) {
+		for _, f := range children[parent] {
+			// защита от цикла в испорченном кэше
+			if seen[f.ID] {
+				continue
+			}
+			seen[f.ID] = true
+			path := prefix + "/" + f.Name
+			out = append(out, folderNode{folder: f, path: path})
+			visit(f.ID, path)
+		}
+	}
+	visit(0, "")
+	return out
+}
+
+// folderTree - дерево папок для фильтра записей. Корень показывает все записи,
+// "No folder" - записи вне папок, выбор папки - только ее записи.
+func (c *CLI) folderTree(ctx context.Context, app *tview.Application, b *itemBrowser) *tview.TreeView {
+	root := tview.NewTreeNode("All folders").SetReference(models.FolderAny).SetColor(tcell.ColorYellow)
+	root.AddChild(tview.NewTreeNode("No folder").SetReference(int64(0)))
+
+	folders, err := c.getAll.Folders(ctx, c.token)
+	if err != nil {
+		c.log.Error("failed to get folders", "error", err)
+	}
+
+	nodes := map[int64]*tview.TreeNode{0: root}
+	for _, n := range sortedFolders(folders) {
+		node := tview.NewTreeNode(n.folder.Name).SetReference(n.folder.ID)
+		nodes[n.folder.ParentID].AddChild(node)
+		nodes[n.folder.ID] = node
+	}
+
+	tree := tview.NewTreeView().SetRoot(root).SetCurrentNode(root)
+	tree.SetBorder(true).SetTitle("Folders")
+	tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		b.folder = node.GetReference().(int64)
+		c.reloadItems(ctx, b)
+		app.SetFocus(b.table)
+	})
+
+	return tree
+}
+
+// moveItem - переносит выбранную запись в папку, выбранную из списка. Перенос
+// сохраняется в кэше и уходит на сервер при синхронизации.
+func (c *CLI) moveItem(ctx context.Context, app *tview.Application, pages *tview.Pages, b *itemBrowser, item models.Item) {
+	folders, err := c.getAll.Folders(ctx, c.token)
+	if err != nil {
+		c.log.Error("failed to get folders", "error", err)
+		c.showMessage(pages, "Не удалось загрузить папки")
+		return
+	}
+
+	back := func() {
+		pages.RemovePage("MoveItem")
+		pages.SwitchToPage("GetAll")
+		app.SetFocus(b.table)
+	}
+	move := func(folderID int64) func() {
+		return func() {
+			back()
+			if _, err := c.items.MoveItem(ctx, c.token, item.ID, folderID); err != nil {
+				c.log.Error("failed to move item", "error", err)
+				c.showMessage(pages, "Не удалось перенести запись")
+				return
+			}
+			c.reloadItems(ctx, b)
+		}
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle("Move \"" + tview.Escape(item.Title) + "\" to")
+	list.AddItem("/", "", 0, move(0))
+	for _, n := range sortedFolders(folders) {
+		list.AddItem(tview.Escape(n.path), "", 0, move(n.folder.ID))
+	}
+	list.SetDoneFunc(back)
+
+	pages.AddPage("MoveItem", list, true, true)
+	app.SetFocus(list)
+}
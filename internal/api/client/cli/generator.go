@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/passgen"
+	"strconv"
+)
+
+// passwordGenerator - форма генератора паролей. use вызывается с выбранным паролем,
+// после чего открывается страница back.
+func (c *CLI) passwordGenerator(app *tview.Application, pages *tview.Pages, back string, use func(password string)) {
+	policy := passgen.DefaultPolicy
+	phrasePolicy := passgen.DefaultPassphrasePolicy
+	passphrase := false
+	var password string
+
+	result := tview.NewTextView().SetDynamicColors(true)
+	result.SetBorder(true).SetTitle("Generated")
+
+	generate := func() {
+		var (
+			err     error
+			entropy float64
+		)
+		if passphrase {
+			password, err = passgen.Passphrase(phrasePolicy)
+			entropy = phrasePolicy.Entropy()
+		} else {
+			password, err = passgen.Generate(policy)
+			entropy = policy.Entropy()
+		}
+		if err != nil {
+			password = ""
+			result.SetText("[red]Недопустимые параметры генерации[-]")
+			return
+		}
+		result.SetText(fmt.Sprintf("%s\n\nEntropy: %.0f bits (%s)",
+			tview.Escape(password), entropy, passgen.Strength(entropy)))
+	}
+
+	number := func(set func(int)) func(text string) {
+		return func(text string) {
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				n = 0
+			}
+			set(n)
+			generate()
+		}
+	}
+	flag := func(set func(bool)) func(checked bool) {
+		return func(checked bool) {
+			set(checked)
+			generate()
+		}
+	}
+
+	closeForm := func() {
+		pages.RemovePage("Generator")
+		pages.SwitchToPage(back)
+	}
+
+	form := tview.NewForm()
+	form.
+		AddDropDown("Mode", []string{"Password", "Passphrase"}, 0, func(option string, index int) {
+			passphrase = index == 1
+			generate()
+		}).
+		AddInputField("Length", strconv.Itoa(policy.Length), 5, tview.InputFieldInteger,
+			number(func(n int) { policy.Length = n })).
+		AddCheckbox("Lowercase", policy.Lower, flag(func(b bool) { policy.Lower = b })).
+		AddCheckbox("Uppercase", policy.Upper, flag(func(b bool) { policy.Upper = b })).
+		AddCheckbox("Digits", policy.Digits, flag(func(b bool) { policy.Digits = b })).
+		AddCheckbox("Symbols", policy.Symbols, flag(func(b bool) { policy.Symbols = b })).
+		AddCheckbox("Exclude ambiguous", policy.ExcludeAmbiguous, flag(func(b bool) { policy.ExcludeAmbiguous = b })).
+		AddInputField("Words", strconv.Itoa(phrasePolicy.Words), 5, tview.InputFieldInteger,
+			number(func(n int) { phrasePolicy.Words = n })).
+		AddInputField("Separator", phrasePolicy.Separator, 5, nil, func(text string) {
+			phrasePolicy.Separator = text
+			generate()
+		}).
+		AddCheckbox("Capitalize", phrasePolicy.Capitalize, flag(func(b bool) { phrasePolicy.Capitalize = b })).
+		AddButton("Regenerate", generate).
+		AddButton("Use", func() {
+			if password == "" {
+				return
+			}
+			use(password)
+			closeForm()
+		}).
+		AddButton("Cancel", closeForm)
+	form.SetBorder(true).SetTitle("Password generator").SetTitleAlign(tview.AlignLeft)
+
+	generate()
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 3, true).
+		AddItem(result, 6, 0, false)
+
+	pages.AddPage("Generator", flex, true, true)
+	app.SetFocus(form)
+}
@@ -2,105 +2,443 @@ package cli
 
 import (
 	"context"
-	"fmt"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"goph-keeper/internal/models"
+	"strings"
+	"time"
 )
 
+// preloadRows - за сколько строк до конца таблицы подгружается следующая страница.
+const preloadRows = 5
+
+// browserHelp - подсказка по клавишам браузера записей.
+const browserHelp = "/ - поиск, r - показать/скрыть, c - копировать, e - изменить, d - удалить, m - в папку, h - история, s - сортировка, f - папки, Esc - назад"
+
+// itemBrowser - состояние постраничного просмотра записей.
+type itemBrowser struct {
+	table    *tview.Table
+	details  *tview.TextView
+	search   *tview.InputField
+	query    string
+	items    []models.Item
+	itemType models.ItemType
+	folder   int64
+	sort     models.Sort
+	next     string
+	loaded   bool
+	reveal   bool
+}
+
 func (c *CLI) getResource(ctx context.Context,
 	app *tview.Application,
 	pages *tview.Pages) {
 
-	columns := tview.NewTable().SetBorders(true)
-	columns.SetBorder(true).SetTitle("Columns")
-	tables := tview.NewList()
-	tables.ShowSecondaryText(false).
+	browser := &itemBrowser{
+		table:   tview.NewTable().SetBorders(false).SetSelectable(true, false).SetFixed(1, 0),
+		details: tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		search:  tview.NewInputField().SetLabel("Search: "),
+		folder:  models.FolderAny,
+		sort:    models.DefaultSort,
+	}
+	browser.search.SetBorder(true)
+	browser.table.SetBorder(true).SetTitle(browser.title())
+	browser.details.SetBorder(true).SetTitle("Details")
+
+	// После очистки буфера обмена снова скрываем секреты на экране
+	c.clip.OnClear(func() {
+		app.QueueUpdateDraw(func() {
+			browser.reveal = false
+			browser.showDetails()
+		})
+	})
+
+	types := tview.NewList()
+	types.ShowSecondaryText(false).
 		SetDoneFunc(func() {
-			columns.Clear()
-			app.SetFocus(tables)
+			browser.table.Clear()
+			browser.details.Clear()
+			app.SetFocus(types)
 		})
-	tables.SetBorder(true).SetTitle("Tables")
+	types.SetBorder(true).SetTitle("Types")
 
-	// Список таблиц
-	tableNames := []string{"credentials", "text_data", "binary_data", "cards"}
-	for _, tableName := range tableNames {
-		tables.AddItem(tableName, "", 0, func(tableName string) func() {
+	// Список типов записей
+	itemTypes := []models.ItemType{
+		models.ItemTypeUnspecified,
+		models.ItemTypeLogin,
+		models.ItemTypeNote,
+		models.ItemTypeBinary,
+		models.ItemTypeCard,
+		models.ItemTypeSSHKey,
+		models.ItemTypeTOTP,
+	}
+	for _, itemType := range itemTypes {
+		name := itemType.String()
+		if itemType == models.ItemTypeUnspecified {
+			name = "all"
+		}
+		types.AddItem(name, "", 0, func(itemType models.ItemType) func() {
 			return func() {
-				columns.Clear()
+				browser.itemType = itemType
+				c.reloadItems(ctx, browser)
+				app.SetFocus(browser.table)
+			}
+		}(itemType))
+	}
 
-				rows, err := c.getAll.GetAllData(ctx, c.token, tableName)
-				if err != nil {
-					c.log.Error("failed to getAll data from database", "error", err)
-					return
-				}
+	types.AddItem("Back", "", 0, func() {
+		pages.SwitchToPage("Buttons_data")
+	})
 
-				defer rows.Close()
+	types.AddItem("Quit", "", 0, func() {
+		app.Stop()
+	})
 
-				// Получение имен столбцов
-				columnNames, err := rows.Columns()
-				if err != nil {
-					panic(err)
-				}
+	folders := c.folderTree(ctx, app, browser)
+	folders.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			app.SetFocus(types)
+			return nil
+		}
+		return event
+	})
 
-				// Добавляем заголовки для таблицы
-				for colIndex, colName := range columnNames {
-					columns.SetCell(0, colIndex, &tview.TableCell{
-						Text:  colName,
-						Align: tview.AlignCenter,
-						Color: tcell.ColorBlue,
-					})
-				}
+	// При смене строки показываем детали записи и подгружаем следующую страницу,
+	// когда курсор подходит к концу таблицы
+	browser.table.SetSelectionChangedFunc(func(row, column int) {
+		browser.reveal = false
+		browser.showDetails()
 
-				// Чтение строк из таблицы
-				rowIndex := 1
-				for rows.Next() {
-					// Создаем массив для хранения значений
-					values := make([]any, len(columnNames))
-					valuePtrs := make([]any, len(columnNames))
-					for i := range values {
-						valuePtrs[i] = &values[i]
-					}
-
-					// Сканируем строку
-					if err := rows.Scan(valuePtrs...); err != nil {
-						panic(err)
-					}
-
-					// Добавляем строку в таблицу для отображения
-					for colIndex, value := range values {
-						var text string
-						if value != nil {
-							text = fmt.Sprintf("%v", value)
-						}
-						columns.SetCell(rowIndex, colIndex, &tview.TableCell{
-							Text:  text,
-							Align: tview.AlignLeft,
-							Color: tcell.ColorWhite,
-						})
-					}
-					rowIndex++
-				}
+		if browser.next != "" && row >= browser.table.GetRowCount()-preloadRows {
+			c.loadItems(ctx, browser)
+		}
+	})
 
-				// Проверка на ошибки после итерации
-				if err := rows.Err(); err != nil {
-					panic(err)
-				}
+	browser.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			app.SetFocus(types)
+			return nil
+		}
+
+		switch event.Rune() {
+		case '/':
+			app.SetFocus(browser.search)
+		case 's':
+			browser.toggleSort()
+			c.reloadItems(ctx, browser)
+		case 'r':
+			browser.reveal = !browser.reveal
+			browser.showDetails()
+		case 'c':
+			c.copyItem(pages, browser)
+		case 'e':
+			if item, ok := browser.selected(); ok {
+				c.editItem(ctx, app, pages, browser, item)
 			}
-		}(tableName))
-	}
+		case 'd':
+			if item, ok := browser.selected(); ok {
+				c.deleteItem(ctx, app, pages, browser, item)
+			}
+		case 'h':
+			if item, ok := browser.selected(); ok {
+				c.showHistory(ctx, app, pages, browser, item)
+			}
+		case 'm':
+			if item, ok := browser.selected(); ok {
+				c.moveItem(ctx, app, pages, browser, item)
+			}
+		case 'f':
+			app.SetFocus(folders)
+		default:
+			return event
+		}
+		return nil
+	})
 
-	tables.AddItem("Back", "", 0, func() {
-		pages.SwitchToPage("Buttons_data")
+	// Поиск перестраивает выдачу на каждое нажатие клавиши
+	browser.search.SetChangedFunc(func(text string) {
+		browser.query = strings.TrimSpace(text)
+		c.reloadItems(ctx, browser)
 	})
 
-	tables.AddItem("Quit", "", 0, func() {
-		app.Stop()
+	// Up/Down двигают выбор в таблице, не уходя из поиска, Enter переводит фокус
+	// в таблицу (например, чтобы сразу скопировать пароль), Esc сбрасывает поиск
+	browser.search.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown:
+			browser.moveSelection(event.Key())
+			return nil
+		case tcell.KeyEnter, tcell.KeyTab:
+			app.SetFocus(browser.table)
+			return nil
+		case tcell.KeyEscape:
+			browser.search.SetText("")
+			app.SetFocus(browser.table)
+			return nil
+		}
+		return event
 	})
 
+	help := tview.NewTextView().SetText(browserHelp)
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(browser.search, 3, 0, false).
+		AddItem(browser.table, 0, 2, false).
+		AddItem(browser.details, 0, 1, false).
+		AddItem(help, 1, 0, false)
+
+	left := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(types, 0, 1, true).
+		AddItem(folders, 0, 1, false)
+
 	flex := tview.NewFlex().
-		AddItem(tables, 0, 1, true).
-		AddItem(columns, 0, 3, false)
+		AddItem(left, 0, 1, true).
+		AddItem(right, 0, 3, false)
 
 	pages.AddPage("GetAll", flex, true, true)
 	app.SetRoot(pages, true)
+
+	c.startCountdown(ctx, app, pages, browser)
+}
+
+// startCountdown - раз в секунду перерисовывает детали записи totp, чтобы код и обратный
+// отсчет были актуальны. Отсчет предыдущего браузера записей останавливается.
+func (c *CLI) startCountdown(ctx context.Context, app *tview.Application, pages *tview.Pages, b *itemBrowser) {
+	if c.stopCountdown != nil {
+		c.stopCountdown()
+	}
+	ctx, c.stopCountdown = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			app.QueueUpdateDraw(func() {
+				if name, _ := pages.GetFrontPage(); name == "GetAll" {
+					b.refreshTOTP()
+				}
+			})
+		}
+	}()
+}
+
+// reloadItems - очищает таблицу и загружает первую страницу либо результаты поиска.
+func (c *CLI) reloadItems(ctx context.Context, b *itemBrowser) {
+	b.table.Clear()
+	b.details.Clear()
+	b.items = nil
+	b.next = ""
+	b.loaded = false
+	b.reveal = false
+	b.table.SetTitle(b.title())
+
+	headers := []string{"Title", "Type", "Tags", "Updated"}
+	for colIndex, colName := range headers {
+		b.table.SetCell(0, colIndex, &tview.TableCell{
+			Text:          colName,
+			Align:         tview.AlignCenter,
+			Color:         tcell.ColorBlue,
+			NotSelectable: true,
+		})
+	}
+
+	if b.query != "" {
+		c.searchItems(ctx, b)
+	} else {
+		c.loadItems(ctx, b)
+	}
+	b.table.Select(1, 0)
+	b.showDetails()
+}
+
+// searchItems - заполняет таблицу записями, найденными по строке поиска.
+func (c *CLI) searchItems(ctx context.Context, b *itemBrowser) {
+	items, err := c.getAll.Search(ctx, c.token, b.itemType, b.query)
+	if err != nil {
+		c.log.Error("failed to search items", "error", err)
+		return
+	}
+	if b.folder != models.FolderAny {
+		filtered := items[:0]
+		for _, item := range items {
+			if item.FolderID == b.folder {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+	b.loaded = true
+	b.appendRows(items)
+}
+
+// loadItems - дописывает в таблицу следующую страницу записей.
+func (c *CLI) loadItems(ctx context.Context, b *itemBrowser) {
+	if b.loaded && b.next == "" {
+		return
+	}
+
+	items, next, err := c.getAll.GetPage(ctx, c.token, b.itemType, b.folder, models.PageRequest{
+		Token: b.next,
+		Sort:  b.sort,
+	})
+	if err != nil {
+		c.log.Error("failed to get items from database", "error", err)
+		return
+	}
+	b.next = next
+	b.loaded = true
+	b.appendRows(items)
+}
+
+// appendRows - дописывает записи в конец таблицы.
+func (b *itemBrowser) appendRows(items []models.Item) {
+	rowIndex := b.table.GetRowCount()
+	for _, item := range items {
+		values := []string{
+			item.Title,
+			item.Type.String(),
+			strings.Join(item.Tags, ", "),
+			item.UpdatedAt.Local().Format("2006-01-02 15:04"),
+		}
+		for colIndex, value := range values {
+			b.table.SetCell(rowIndex, colIndex, &tview.TableCell{
+				Text:  value,
+				Align: tview.AlignLeft,
+				Color: tcell.ColorWhite,
+			})
+		}
+		rowIndex++
+	}
+	b.items = append(b.items, items...)
+}
+
+// copyItem - копирует основной секрет выбранной записи в буфер обмена.
+func (c *CLI) copyItem(pages *tview.Pages, b *itemBrowser) {
+	item, ok := b.selected()
+	if !ok {
+		return
+	}
+
+	secret, ok := itemSecret(item)
+	if !ok {
+		c.showMessage(pages, "У записи нет данных для копирования")
+		return
+	}
+
+	if err := c.clip.Copy(secret); err != nil {
+		c.log.Error("failed to copy to clipboard", "error", err)
+		c.showMessage(pages, "Не удалось скопировать в буфер обмена")
+		return
+	}
+
+	title := "Details (copied)"
+	if timeout := c.clip.Timeout(); timeout > 0 {
+		title = "Details (copied, clears in " + timeout.Round(time.Second).String() + ")"
+	}
+	b.details.SetTitle(title)
+}
+
+// deleteItem - удаляет выбранную запись после подтверждения.
+func (c *CLI) deleteItem(ctx context.Context, app *tview.Application, pages *tview.Pages, b *itemBrowser, item models.Item) {
+	modal := tview.NewModal().
+		SetText("Удалить запись \"" + item.Title + "\"?").
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage("DeleteConfirmation")
+			pages.SwitchToPage("GetAll")
+			app.SetFocus(b.table)
+
+			if buttonLabel != "Delete" {
+				return
+			}
+			if err := c.items.DeleteItem(ctx, c.token, item.ID); err != nil {
+				c.log.Error("failed to delete item", "error", err)
+				c.showMessage(pages, "Не удалось удалить запись")
+				return
+			}
+			c.reloadItems(ctx, b)
+		})
+
+	pages.AddPage("DeleteConfirmation", modal, true, true)
+}
+
+// showMessage - показывает сообщение поверх браузера записей.
+func (c *CLI) showMessage(pages *tview.Pages, text string) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage("Message")
+			pages.SwitchToPage("GetAll")
+		})
+
+	pages.AddPage("Message", modal, true, true)
+}
+
+// moveSelection - сдвигает выбранную строку таблицы на одну вверх или вниз.
+func (b *itemBrowser) moveSelection(key tcell.Key) {
+	row, _ := b.table.GetSelection()
+	if key == tcell.KeyUp && row > 1 {
+		row--
+	}
+	if key == tcell.KeyDown && row < len(b.items) {
+		row++
+	}
+	b.table.Select(row, 0)
+}
+
+// selected - возвращает запись в выбранной строке таблицы.
+func (b *itemBrowser) selected() (models.Item, bool) {
+	row, _ := b.table.GetSelection()
+	// нулевая строка - заголовок
+	if row < 1 || row > len(b.items) {
+		return models.Item{}, false
+	}
+	return b.items[row-1], true
+}
+
+// showDetails - выводит детали выбранной записи.
+func (b *itemBrowser) showDetails() {
+	b.details.SetTitle("Details")
+
+	item, ok := b.selected()
+	if !ok {
+		b.details.Clear()
+		return
+	}
+	b.details.SetText(itemDetails(item, b.reveal)).ScrollToBeginning()
+}
+
+// refreshTOTP - обновляет детали выбранной записи totp, не сбрасывая заголовок панели.
+func (b *itemBrowser) refreshTOTP() {
+	item, ok := b.selected()
+	if !ok || item.Type != models.ItemTypeTOTP {
+		return
+	}
+	b.details.SetText(itemDetails(item, b.reveal))
+}
+
+// toggleSort - переключает сортировку между датой изменения и названием.
+func (b *itemBrowser) toggleSort() {
+	if b.sort.Field == models.SortByTitle {
+		b.sort = models.DefaultSort
+		return
+	}
+	b.sort = models.Sort{Field: models.SortByTitle}
+}
+
+// title - заголовок таблицы с текущей сортировкой.
+func (b *itemBrowser) title() string {
+	if b.query != "" {
+		return "Items (search)"
+	}
+	if b.sort.Field == models.SortByTitle {
+		return "Items (sort: title, s - change)"
+	}
+	return "Items (sort: updated, s - change)"
 }
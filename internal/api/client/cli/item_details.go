@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/redact"
+	"goph-keeper/internal/totp"
+	"strings"
+	"time"
+)
+
+// mask - скрывает секрет, если он не раскрыт.
+func mask(secret string, reveal bool) string {
+	if reveal {
+		return secret
+	}
+	return redact.Mask(secret)
+}
+
+// maskCardNumber - скрывает номер карты, оставляя последние четыре цифры.
+func maskCardNumber(number string, reveal bool) string {
+	if reveal || len(number) <= 4 {
+		return number
+	}
+	return redact.Masked + number[len(number)-4:]
+}
+
+// itemDetails - текст панели деталей записи. Секреты скрыты, пока reveal == false.
+func itemDetails(item models.Item, reveal bool) string {
+	var sb strings.Builder
+
+	field := func(name, value string) {
+		fmt.Fprintf(&sb, "[blue]%s:[-] %s\n", name, tview.Escape(value))
+	}
+
+	field("Title", item.Title)
+	field("Type", item.Type.String())
+	if len(item.Tags) > 0 {
+		field("Tags", strings.Join(item.Tags, ", "))
+	}
+	if item.Favorite {
+		field("Favorite", "yes")
+	}
+	field("Created", item.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	field("Updated", item.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+	sb.WriteString("\n")
+
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		sb.WriteString("[red]не удалось прочитать данные записи[-]\n")
+		return sb.String()
+	}
+
+	switch {
+	case payload.Login != nil:
+		field("Resource", payload.Login.Resource)
+		field("Login", payload.Login.Login)
+		field("Password", mask(payload.Login.Password, reveal))
+	case payload.Note != nil:
+		field("Text", mask(payload.Note.Text, reveal))
+	case payload.Binary != nil:
+		field("Name", payload.Binary.Name)
+		field("Size", fmt.Sprintf("%d bytes", len(payload.Binary.Data)))
+	case payload.Card != nil:
+		field("Number", maskCardNumber(payload.Card.Number, reveal))
+		field("Holder", payload.Card.Holder)
+		field("Expiry", payload.Card.Expiry)
+		field("CVV", mask(payload.Card.CVV, reveal))
+	case payload.SSHKey != nil:
+		field("Comment", payload.SSHKey.Comment)
+		field("Fingerprint", payload.SSHKey.Fingerprint)
+		field("Public key", payload.SSHKey.PublicKey)
+		if reveal {
+			sb.WriteString("[blue]Private key:[-]\n" + tview.Escape(payload.SSHKey.PrivateKey))
+		} else {
+			field("Private key", redact.Masked)
+		}
+	case payload.TOTP != nil:
+		if payload.TOTP.Issuer != "" {
+			field("Issuer", payload.TOTP.Issuer)
+		}
+		field("Account", payload.TOTP.Account)
+		// код с цветным обратным отсчетом, экранировать нечего
+		fmt.Fprintf(&sb, "[blue]Code:[-] %s\n", totpCode(*payload.TOTP, time.Now()))
+		field("Secret", mask(payload.TOTP.Secret, reveal))
+	case payload.Sealed != nil:
+		sb.WriteString("Данные зашифрованы\n")
+	}
+
+	return sb.String()
+}
+
+// totpCode - текущий код и сколько секунд он еще действует.
+func totpCode(p models.TOTPPayload, now time.Time) string {
+	code, err := totp.Code(p, now)
+	if err != nil {
+		return "[red]неверный секрет[-]"
+	}
+	remaining := totp.Remaining(p, now)
+
+	color := "green"
+	if remaining <= 5*time.Second {
+		color = "red"
+	}
+	return fmt.Sprintf("[::b]%s %s[::-] [%s](%ds)[-]", code[:len(code)/2], code[len(code)/2:], color, remaining/time.Second)
+}
+
+// itemSecret - основной секрет записи для копирования.
+func itemSecret(item models.Item) (string, bool) {
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return "", false
+	}
+
+	var secret string
+	switch {
+	case payload.Login != nil:
+		secret = payload.Login.Password
+	case payload.Note != nil:
+		secret = payload.Note.Text
+	case payload.Card != nil:
+		secret = payload.Card.Number
+	case payload.SSHKey != nil:
+		secret = payload.SSHKey.PrivateKey
+	case payload.TOTP != nil:
+		secret, _ = totp.Code(*payload.TOTP, time.Now())
+	}
+	return secret, secret != ""
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/sshkey"
+	"goph-keeper/internal/totp"
+	"strings"
+)
+
+// editItem - форма изменения записи. Поля данных зависят от типа записи.
+func (c *CLI) editItem(ctx context.Context, app *tview.Application, pages *tview.Pages, b *itemBrowser, item models.Item) {
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil || payload.Sealed != nil {
+		c.showMessage(pages, "Эту запись нельзя изменить")
+		return
+	}
+
+	back := func() {
+		pages.RemovePage("EditItem")
+		pages.SwitchToPage("GetAll")
+		app.SetFocus(b.table)
+	}
+
+	form := tview.NewForm()
+	form.
+		AddInputField("Title", item.Title, 30, nil, func(text string) {
+			item.Title = text
+		}).
+		AddInputField("Tags", strings.Join(item.Tags, ", "), 30, nil, func(text string) {
+			item.Tags = splitTags(text)
+		}).
+		AddCheckbox("Favorite", item.Favorite, func(checked bool) {
+			item.Favorite = checked
+		})
+
+	var sshComment string
+	switch {
+	case payload.Login != nil:
+		login := payload.Login
+		form.
+			AddInputField("Resource", login.Resource, 30, nil, func(text string) { login.Resource = text }).
+			AddInputField("Login", login.Login, 30, nil, func(text string) { login.Login = text }).
+			AddFormItem(secretField("Password", login.Password, 30, func(text string) { login.Password = text }))
+	case payload.Note != nil:
+		note := payload.Note
+		form.AddTextArea("Text", note.Text, 30, 5, 0, func(text string) { note.Text = text })
+	case payload.Binary != nil:
+		binary := payload.Binary
+		form.AddInputField("Name", binary.Name, 30, nil, func(text string) { binary.Name = text })
+	case payload.Card != nil:
+		card := payload.Card
+		form.
+			AddFormItem(secretField("Number", card.Number, 30, func(text string) { card.Number = text })).
+			AddInputField("Holder", card.Holder, 30, nil, func(text string) { card.Holder = text }).
+			AddInputField("Expiry", card.Expiry, 30, nil, func(text string) { card.Expiry = text }).
+			AddFormItem(secretField("CVV", card.CVV, 30, func(text string) { card.CVV = text }))
+	case payload.SSHKey != nil:
+		sshComment = payload.SSHKey.Comment
+		form.AddInputField("Comment", sshComment, 30, nil, func(text string) { sshComment = text })
+	case payload.TOTP != nil:
+		otp := payload.TOTP
+		form.
+			AddInputField("Issuer", otp.Issuer, 30, nil, func(text string) { otp.Issuer = text }).
+			AddInputField("Account", otp.Account, 30, nil, func(text string) { otp.Account = text }).
+			AddFormItem(secretField("Secret", otp.Secret, 30, func(text string) { otp.Secret = text }))
+	}
+
+	form.
+		AddButton("Save", func() {
+			if payload.SSHKey != nil && sshComment != payload.SSHKey.Comment {
+				// комментарий хранится и в закрытом, и в открытом ключе
+				key, err := sshkey.FromPrivateKey([]byte(payload.SSHKey.PrivateKey), nil, sshComment)
+				if err != nil {
+					c.log.Error("failed to update ssh key comment", "error", err)
+					back()
+					c.showMessage(pages, "Не удалось сохранить запись")
+					return
+				}
+				*payload.SSHKey = key
+			}
+			if payload.TOTP != nil {
+				// в поле секрета можно вставить и новую ссылку otpauth://
+				parsed, err := totp.Parse(payload.TOTP.Secret)
+				if err != nil {
+					back()
+					c.showMessage(pages, "Неверный секрет TOTP")
+					return
+				}
+				if strings.HasPrefix(strings.ToLower(strings.TrimSpace(payload.TOTP.Secret)), "otpauth://") {
+					*payload.TOTP = parsed
+				} else {
+					payload.TOTP.Secret = parsed.Secret
+				}
+			}
+			if _, err := c.items.UpdateItem(ctx, c.token, item, payload); err != nil {
+				c.log.Error("failed to update item", "error", err)
+				back()
+				c.showMessage(pages, "Не удалось сохранить запись")
+				return
+			}
+			back()
+			c.reloadItems(ctx, b)
+		}).
+		AddButton("Cancel", back)
+	form.SetBorder(true).SetTitle("Edit " + item.Type.String()).SetTitleAlign(tview.AlignLeft)
+
+	pages.AddPage("EditItem", form, true, true)
+}
+
+// splitTags - разбирает теги, введенные через запятую.
+func splitTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/models"
+)
+
+// historyHelp - подсказка по клавишам истории изменений.
+const historyHelp = "Enter - восстановить, r - показать/скрыть, Esc - назад"
+
+// showHistory - история изменений записи на сервере: список ревизий, детали выбранной
+// и восстановление записи к ней.
+func (c *CLI) showHistory(ctx context.Context, app *tview.Application, pages *tview.Pages, b *itemBrowser, item models.Item) {
+	revisions, err := c.history.Revisions(ctx, c.conn, c.token, item.ID)
+	if err != nil {
+		c.log.Error("failed to list revisions", "error", err)
+		if errors.Is(err, vault.ErrNotSynced) {
+			c.showMessage(pages, "Запись еще не отправлена на сервер, истории нет")
+			return
+		}
+		c.showMessage(pages, "Не удалось загрузить историю изменений")
+		return
+	}
+
+	table := tview.NewTable().SetBorders(false).SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle("History: " + tview.Escape(item.Title))
+	details := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	details.SetBorder(true).SetTitle("Revision")
+
+	for colIndex, colName := range []string{"Changed", "By", "Title"} {
+		table.SetCell(0, colIndex, &tview.TableCell{
+			Text:          colName,
+			Align:         tview.AlignCenter,
+			Color:         tcell.ColorBlue,
+			NotSelectable: true,
+		})
+	}
+	for i, rev := range revisions {
+		values := []string{
+			rev.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			rev.ChangedByLogin,
+			rev.Item.Title,
+		}
+		for colIndex, value := range values {
+			table.SetCell(i+1, colIndex, &tview.TableCell{
+				Text:  value,
+				Align: tview.AlignLeft,
+				Color: tcell.ColorWhite,
+			})
+		}
+	}
+
+	reveal := false
+	selected := func() (models.Revision, bool) {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(revisions) {
+			return models.Revision{}, false
+		}
+		return revisions[row-1], true
+	}
+	show := func() {
+		rev, ok := selected()
+		if !ok {
+			details.Clear()
+			return
+		}
+		details.SetText(itemDetails(rev.Item, reveal)).ScrollToBeginning()
+	}
+
+	back := func() {
+		pages.RemovePage("History")
+		pages.SwitchToPage("GetAll")
+		app.SetFocus(b.table)
+	}
+
+	// message - сообщение поверх истории, после него фокус возвращается в список ревизий
+	message := func(text string) {
+		modal := tview.NewModal().
+			SetText(text).
+			AddButtons([]string{"OK"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				pages.RemovePage("HistoryMessage")
+				pages.SwitchToPage("History")
+				app.SetFocus(table)
+			})
+		pages.AddPage("HistoryMessage", modal, true, true)
+	}
+
+	table.SetSelectionChangedFunc(func(row, column int) {
+		reveal = false
+		show()
+	})
+
+	table.SetSelectedFunc(func(row, column int) {
+		rev, ok := selected()
+		if !ok {
+			return
+		}
+		c.restoreRevision(ctx, app, pages, b, table, rev, back, message)
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			back()
+			return nil
+		}
+		if event.Rune() == 'r' {
+			reveal = !reveal
+			show()
+			return nil
+		}
+		return event
+	})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(details, 0, 1, false).
+		AddItem(tview.NewTextView().SetText(historyHelp), 1, 0, false)
+
+	pages.AddPage("History", flex, true, true)
+	table.Select(1, 0)
+	show()
+	app.SetFocus(table)
+}
+
+// restoreRevision - восстанавливает запись к ревизии после подтверждения и обновляет браузер записей.
+func (c *CLI) restoreRevision(ctx context.Context, app *tview.Application, pages *tview.Pages, b *itemBrowser,
+	table *tview.Table, rev models.Revision, back func(), message func(string)) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Восстановить запись \"%s\" к состоянию от %s?",
+			rev.Item.Title, rev.CreatedAt.Local().Format("2006-01-02 15:04:05"))).
+		AddButtons([]string{"Restore", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage("RestoreConfirmation")
+			pages.SwitchToPage("History")
+			app.SetFocus(table)
+
+			if buttonLabel != "Restore" {
+				return
+			}
+			if _, err := c.history.RestoreRevision(ctx, c.conn, c.token, rev.Item.ID, rev.ID); err != nil {
+				c.log.Error("failed to restore revision", "error", err)
+				message("Не удалось восстановить запись")
+				return
+			}
+			back()
+			c.reloadItems(ctx, b)
+		})
+
+	pages.AddPage("RestoreConfirmation", modal, true, true)
+}
@@ -2,34 +2,96 @@ package cli
 
 import (
 	"context"
-	"database/sql"
 	"github.com/rivo/tview"
 	"google.golang.org/grpc"
+	"goph-keeper/internal/agent"
 	"goph-keeper/internal/api/client/handlers/auth"
 	"goph-keeper/internal/api/client/handlers/save"
+	"goph-keeper/internal/audit"
+	"goph-keeper/internal/models"
 	"log/slog"
+	"time"
 )
 
 type getService interface {
-	GetAllData(ctx context.Context, token, tableName string) (*sql.Rows, error)
+	GetPage(ctx context.Context, token string, itemType models.ItemType, folderID int64, req models.PageRequest) ([]models.Item, string, error)
+	Search(ctx context.Context, token string, itemType models.ItemType, query string) ([]models.Item, error)
+	Folders(ctx context.Context, token string) ([]models.Folder, error)
+}
+
+type itemsService interface {
+	GetItem(ctx context.Context, token string, id int64) (models.Item, error)
+	UpdateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error)
+	DeleteItem(ctx context.Context, token string, id int64) error
+	MoveItem(ctx context.Context, token string, id, folderID int64) (models.Item, error)
+}
+
+type clipboardService interface {
+	Copy(text string) error
+	Clear() error
+	OnClear(f func())
+	Timeout() time.Duration
+}
+
+type auditService interface {
+	Report(ctx context.Context, token string, opts audit.Options) (audit.Report, error)
+}
+
+type breachChecker interface {
+	Check(ctx context.Context, password string) (int, error)
+}
+
+type signConfirmer interface {
+	Pending(ctx context.Context) ([]agent.SignRequest, error)
+	Confirm(ctx context.Context, id uint64, allow bool) error
+}
+
+// historyService - история изменений записей на сервере.
+type historyService interface {
+	Revisions(ctx context.Context, conn *grpc.ClientConn, token string, id int64) ([]models.Revision, error)
+	RestoreRevision(ctx context.Context, conn *grpc.ClientConn, token string, id, revisionID int64) (models.SyncResult, error)
+}
+
+// sharesService - общий доступ к записям.
+type sharesService interface {
+	EnsureKeyPair(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) error
+	SharedWithMe(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) ([]models.SharedItem, error)
 }
 
 type CLI struct {
-	log    *slog.Logger
-	auth   *auth.Handlers
-	save   *save.Handler
-	getAll getService
-	conn   *grpc.ClientConn
-	token  string
+	log     *slog.Logger
+	auth    *auth.Handlers
+	save    *save.Handler
+	getAll  getService
+	items   itemsService
+	clip    clipboardService
+	audit   auditService
+	breach  breachChecker
+	signs   signConfirmer
+	history historyService
+	shares  sharesService
+	conn    *grpc.ClientConn
+	token   string
+	login   string
+
+	// stopCountdown - останавливает обновление кодов totp в браузере записей
+	stopCountdown context.CancelFunc
 }
 
-func NewCLI(log *slog.Logger, auth *auth.Handlers, save *save.Handler, get getService, conn *grpc.ClientConn) *CLI {
+func NewCLI(log *slog.Logger, auth *auth.Handlers, save *save.Handler, get getService, items itemsService, clip clipboardService, audit auditService, breach breachChecker, signs signConfirmer, history historyService, shares sharesService, conn *grpc.ClientConn) *CLI {
 	return &CLI{
-		log:    log,
-		auth:   auth,
-		save:   save,
-		getAll: get,
-		conn:   conn,
+		log:     log,
+		auth:    auth,
+		save:    save,
+		getAll:  get,
+		items:   items,
+		clip:    clip,
+		audit:   audit,
+		breach:  breach,
+		signs:   signs,
+		history: history,
+		shares:  shares,
+		conn:    conn,
 	}
 }
 
@@ -41,7 +103,16 @@ func (c *CLI) RunCLI(ctx context.Context) {
 
 	app.SetRoot(pages, true).EnableMouse(true).EnablePaste(true)
 
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	go c.watchSignRequests(watchCtx, app, pages)
+
 	if err := app.Run(); err != nil {
 		panic(err)
 	}
+
+	// не оставляем секрет в буфере обмена после выхода
+	if err := c.clip.Clear(); err != nil {
+		c.log.Error("failed to clear clipboard", "error", err)
+	}
 }
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maskCharacter - символ, которым скрывается ввод секретов.
+const maskCharacter = '*'
+
+// secretField - поле ввода секрета. Ввод скрыт, Ctrl+R показывает/скрывает значение
+// только этого поля.
+func secretField(label, value string, width int, changed func(text string)) *tview.InputField {
+	field := tview.NewInputField().
+		SetLabel(label).
+		SetText(value).
+		SetFieldWidth(width).
+		SetMaskCharacter(maskCharacter).
+		SetChangedFunc(changed)
+
+	revealed := false
+	field.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyCtrlR {
+			return event
+		}
+
+		revealed = !revealed
+		if revealed {
+			field.SetMaskCharacter(0)
+		} else {
+			field.SetMaskCharacter(maskCharacter)
+		}
+		return nil
+	})
+
+	// при потере фокуса значение снова скрывается
+	field.SetBlurFunc(func() {
+		revealed = false
+		field.SetMaskCharacter(maskCharacter)
+	})
+
+	return field
+}
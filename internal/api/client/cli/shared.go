@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/vaultcrypto"
+)
+
+// sharedHelp - подсказка по клавишам списка чужих записей.
+const sharedHelp = "r - показать/скрыть, Esc - назад"
+
+// sharedUnlock - спрашивает мастер-пароль перед списком чужих записей: ключ записи
+// выдается получателю зашифрованным его открытым ключом, а закрытый ключ открывается
+// только ключом хранилища.
+func (c *CLI) sharedUnlock(ctx context.Context, app *tview.Application, pages *tview.Pages) {
+	var master string
+	form := tview.NewForm().
+		AddFormItem(secretField("Master password", "", 30, func(text string) {
+			master = text
+		})).
+		AddButton("OK", func() {
+			pages.RemovePage("MasterPassword")
+
+			key := vaultcrypto.DeriveKey(c.login, master)
+			defer clear(key)
+
+			err := c.shares.EnsureKeyPair(ctx, c.conn, c.token, key)
+			switch {
+			case errors.Is(err, vault.ErrWrongMasterPassword):
+				c.sharedMessage(app, pages, "Неверный мастер-пароль")
+			case err != nil:
+				c.log.Error("failed to check key pair", "error", err)
+				c.sharedMessage(app, pages, "Не удалось проверить мастер-пароль")
+			default:
+				c.showShared(ctx, app, pages, key)
+			}
+		}).
+		AddButton("Back", func() {
+			pages.RemovePage("MasterPassword")
+			pages.SwitchToPage("Buttons_data")
+		})
+	form.SetBorder(true).SetTitle("Мастер-пароль").SetTitleAlign(tview.AlignCenter)
+
+	pages.AddPage("MasterPassword", form, true, true)
+}
+
+// showShared - чужие записи, к которым пользователю открыт доступ, с деталями выбранной.
+// Записи, зашифрованные на клиенте владельца, расшифровываются ключом хранилища vaultKey.
+func (c *CLI) showShared(ctx context.Context, app *tview.Application, pages *tview.Pages, vaultKey []byte) {
+	items, err := c.shares.SharedWithMe(ctx, c.conn, c.token, vaultKey)
+	if err != nil {
+		c.log.Error("failed to list shared items", "error", err)
+		c.sharedMessage(app, pages, "Не удалось загрузить записи, открытые вам")
+		return
+	}
+
+	table := tview.NewTable().SetBorders(false).SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle("Shared with me")
+	details := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	details.SetBorder(true).SetTitle("Item")
+
+	for colIndex, colName := range []string{"Title", "Type", "Owner", "Access"} {
+		table.SetCell(0, colIndex, &tview.TableCell{
+			Text:          colName,
+			Align:         tview.AlignCenter,
+			Color:         tcell.ColorBlue,
+			NotSelectable: true,
+		})
+	}
+	for i, shared := range items {
+		values := []string{
+			shared.Item.Title,
+			shared.Item.Type.String(),
+			shared.OwnerLogin,
+			shared.Permission.String(),
+		}
+		for colIndex, value := range values {
+			table.SetCell(i+1, colIndex, &tview.TableCell{
+				Text:  value,
+				Align: tview.AlignLeft,
+				Color: tcell.ColorWhite,
+			})
+		}
+	}
+
+	reveal := false
+	show := func() {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(items) {
+			details.Clear()
+			return
+		}
+		details.SetText(itemDetails(items[row-1].Item, reveal)).ScrollToBeginning()
+	}
+
+	table.SetSelectionChangedFunc(func(row, column int) {
+		reveal = false
+		show()
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			pages.RemovePage("Shared")
+			pages.SwitchToPage("Buttons_data")
+			return nil
+		}
+		if event.Rune() == 'r' {
+			reveal = !reveal
+			show()
+			return nil
+		}
+		return event
+	})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(details, 0, 1, false).
+		AddItem(tview.NewTextView().SetText(sharedHelp), 1, 0, false)
+
+	pages.AddPage("Shared", flex, true, true)
+	table.Select(1, 0)
+	show()
+	app.SetFocus(table)
+}
+
+// sharedMessage - сообщение поверх меню, после него фокус возвращается в меню.
+func (c *CLI) sharedMessage(app *tview.Application, pages *tview.Pages, text string) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage("SharedMessage")
+			pages.SwitchToPage("Buttons_data")
+		})
+	pages.AddPage("SharedMessage", modal, true, true)
+}
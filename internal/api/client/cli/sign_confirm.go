@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rivo/tview"
+	"goph-keeper/internal/agent"
+	"time"
+)
+
+// signPollInterval - как часто TUI спрашивает агента о запросах подписи SSH.
+const signPollInterval = time.Second
+
+// watchSignRequests - показывает запросы подписи SSH, ожидающие подтверждения в агенте.
+// Если агент не запущен или подтверждение выключено, запросов просто нет.
+func (c *CLI) watchSignRequests(ctx context.Context, app *tview.Application, pages *tview.Pages) {
+	if c.signs == nil {
+		return
+	}
+
+	ticker := time.NewTicker(signPollInterval)
+	defer ticker.Stop()
+
+	shown := make(map[uint64]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pending, err := c.signs.Pending(ctx)
+		if err != nil {
+			if !errors.Is(err, agent.ErrNotRunning) && ctx.Err() == nil {
+				c.log.Debug("failed to get ssh sign requests", "error", err)
+			}
+			pending = nil
+		}
+
+		active := make(map[uint64]bool, len(pending))
+		for _, req := range pending {
+			active[req.ID] = true
+			if !shown[req.ID] {
+				shown[req.ID] = true
+				app.QueueUpdateDraw(func() { c.showSignRequest(ctx, pages, req) })
+			}
+		}
+		// запросы, отклоненные по таймауту или подтвержденные в другом окне
+		for id := range shown {
+			if !active[id] {
+				delete(shown, id)
+				app.QueueUpdateDraw(func() { pages.RemovePage(signPageName(id)) })
+			}
+		}
+	}
+}
+
+// showSignRequest - окно подтверждения подписи SSH.
+func (c *CLI) showSignRequest(ctx context.Context, pages *tview.Pages, req agent.SignRequest) {
+	text := fmt.Sprintf("Подписать запрос SSH ключом %q?\n\n%s", req.Title, req.Fingerprint)
+	if req.Comment != "" {
+		text += "\n" + req.Comment
+	}
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"Разрешить", "Отклонить"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage(signPageName(req.ID))
+			allow := buttonIndex == 0
+			go func() {
+				if err := c.signs.Confirm(ctx, req.ID, allow); err != nil {
+					c.log.Warn("failed to answer ssh sign request", "request", req.ID, "error", err)
+				}
+			}()
+		})
+
+	pages.AddPage(signPageName(req.ID), modal, true, true)
+}
+
+// signPageName - имя страницы окна подтверждения.
+func signPageName(id uint64) string {
+	return fmt.Sprintf("SignRequest%d", id)
+}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/agent"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/vaultcrypto"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// agentClient - клиент локального агента.
+type agentClient interface {
+	Path() string
+	Status(ctx context.Context) (agent.Status, error)
+	Unlock(ctx context.Context, login, token string, key []byte) (agent.Status, error)
+	Lock(ctx context.Context) error
+	Token(ctx context.Context) (string, error)
+	Get(ctx context.Context, ref string) (models.Item, error)
+	List(ctx context.Context, itemType models.ItemType) ([]models.Item, error)
+	Pending(ctx context.Context) ([]agent.SignRequest, error)
+	Confirm(ctx context.Context, id uint64, allow bool) error
+}
+
+// agentRunning - запущен ли агент. Проверяется один раз за команду.
+func (c *Commands) agentRunning(ctx context.Context) bool {
+	if c.agent == nil {
+		return false
+	}
+	if c.agentChecked {
+		return c.agentUp
+	}
+	c.agentChecked = true
+
+	_, err := c.agent.Status(ctx)
+	if err != nil && !errors.Is(err, agent.ErrNotRunning) {
+		c.log.Warn("agent is not available", "socket", c.agent.Path(), "error", err)
+	}
+	c.agentUp = err == nil
+	return c.agentUp
+}
+
+// agentServe - запускает агента и обслуживает запросы, пока команду не прервут.
+func (c *Commands) agentServe(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("agent")
+	socket := fs.String("socket", agent.SocketPath(), "unix socket path")
+	sshSocket := fs.String("ssh-socket", "", "ssh-agent socket path (default: ssh.sock next to -socket), \"off\" - disable")
+	sshConfirm := fs.Duration("ssh-confirm", 0, "ask for confirmation of every ssh signature and wait this long, 0 - sign without asking")
+	idle := fs.Duration("idle-timeout", 15*time.Minute, "lock after this time without requests, 0 - never")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *sshSocket == "" {
+		*sshSocket = filepath.Join(filepath.Dir(*socket), "ssh.sock")
+	}
+
+	ln, err := agent.Listen(*socket)
+	if err != nil {
+		return err
+	}
+
+	var sshLn net.Listener
+	if *sshSocket != "off" {
+		sshLn, err = agent.Listen(*sshSocket)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	// как ssh-agent: вывод можно передать в eval
+	fmt.Fprintf(c.out, "%s=%s; export %s;\n", agent.EnvSocket, shellQuote(*socket), agent.EnvSocket)
+	if sshLn != nil {
+		fmt.Fprintf(c.out, "SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", shellQuote(*sshSocket))
+	}
+	c.log.Info("agent started", "socket", *socket, "ssh_socket", *sshSocket, "idle_timeout", *idle)
+
+	server := agent.NewServer(c.log, agentBackend{c: c}, *idle)
+	server.ConfirmSigning(*sshConfirm)
+	if sshLn == nil {
+		return server.Serve(ctx, ln)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sshDone := make(chan error, 1)
+	go func() {
+		sshDone <- server.ServeSSH(ctx, sshLn)
+		cancel()
+	}()
+	err = server.Serve(ctx, ln)
+	cancel()
+	return errors.Join(err, <-sshDone)
+}
+
+// unlock - входит на сервер и передает агенту токен и ключ хранилища. Ключ выводится из
+// мастер-пароля, который, в отличие от пароля входа, на сервер не отправляется.
+func (c *Commands) unlock(ctx context.Context, args []string) error {
+	login, rest, err := positional(args, "login")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("unlock")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	masterStdin := fs.Bool("master-password-stdin", false,
+		"read the master password from stdin, with -password-stdin - from the second line")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	if !c.agentRunning(ctx) {
+		return agent.ErrNotRunning
+	}
+
+	password, master, err := c.unlockSecrets(*passwordStdin, *masterStdin)
+	if err != nil {
+		return err
+	}
+	if master == password {
+		return usagef("master password must differ from the login password")
+	}
+
+	token, err := c.auth.AuthUser(ctx, c.conn, login, password)
+	if err != nil {
+		return err
+	}
+
+	key := vaultcrypto.DeriveKey(login, master)
+	defer clear(key)
+
+	if err := c.vault.EnsureKeyPair(ctx, c.conn, token, key); err != nil {
+		return err
+	}
+
+	status, err := c.agent.Unlock(ctx, login, token, key)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("agent unlocked for %s", login)
+	if !status.LockAt.IsZero() {
+		message += fmt.Sprintf(" until %s of inactivity", status.IdleTimeout)
+	}
+	return c.render(out, resultView{Status: "unlocked", Login: login, message: message})
+}
+
+// unlockSecrets - пароль входа и мастер-пароль. Если оба читаются из stdin, они
+// передаются двумя строками: сначала пароль, затем мастер-пароль.
+func (c *Commands) unlockSecrets(passwordStdin, masterStdin bool) (string, string, error) {
+	if passwordStdin && masterStdin {
+		data, err := c.readStdin()
+		if err != nil {
+			return "", "", err
+		}
+		password, master, ok := strings.Cut(data, "\n")
+		if !ok {
+			return "", "", usagef("expected the password and the master password on separate lines of stdin")
+		}
+		return strings.TrimSuffix(password, "\r"), master, nil
+	}
+
+	password, err := c.readSecret("password", "", passwordStdin)
+	if err != nil {
+		return "", "", err
+	}
+	master, err := c.readSecret("master-password", "", masterStdin)
+	if err != nil {
+		return "", "", err
+	}
+	return password, master, nil
+}
+
+// lock - стирает токен и ключ в агенте.
+func (c *Commands) lock(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("lock")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	if !c.agentRunning(ctx) {
+		return agent.ErrNotRunning
+	}
+	if err := c.agent.Lock(ctx); err != nil {
+		return err
+	}
+	return c.render(out, resultView{Status: "locked", message: "agent locked"})
+}
+
+// agentBackend - записи для агента из локального кэша.
+type agentBackend struct {
+	c *Commands
+}
+
+// Resolve - запись по id или названию.
+func (b agentBackend) Resolve(ctx context.Context, token, ref string) (models.Item, error) {
+	item, err := b.c.items.Resolve(ctx, token, ref)
+	switch {
+	case errors.Is(err, items_client.ErrItemNotFound):
+		return models.Item{}, agent.ErrItemNotFound
+	case errors.Is(err, items_client.ErrAmbiguousName):
+		return models.Item{}, agent.ErrAmbiguousName
+	}
+	return item, err
+}
+
+// ListItems - все записи типа itemType.
+func (b agentBackend) ListItems(ctx context.Context, token string, itemType models.ItemType) ([]models.Item, error) {
+	var items []models.Item
+	err := b.c.eachLocalItem(ctx, token, itemType, models.DefaultSort, func(item models.Item, _ models.Payload) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+)
+
+// login - вход на сервер, токен сохраняется в локальной сессии.
+func (c *Commands) login(ctx context.Context, args []string) error {
+	login, rest, err := positional(args, "login")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("login")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	password, err := c.readSecret("password", "", *passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.auth.AuthUser(ctx, c.conn, login, password); err != nil {
+		return err
+	}
+
+	return c.render(out, resultView{
+		Status:  "logged_in",
+		Login:   login,
+		message: fmt.Sprintf("logged in as %s", login),
+	})
+}
+
+// logout - завершает локальную сессию.
+func (c *Commands) logout(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("logout")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	if err := c.auth.Logout(ctx); err != nil {
+		return err
+	}
+	return c.render(out, resultView{Status: "logged_out"})
+}
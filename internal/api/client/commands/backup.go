@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"goph-keeper/internal/backup"
+	"goph-keeper/internal/importer"
+	"goph-keeper/internal/models"
+	"strings"
+	"time"
+)
+
+// exportBackup - записывает все записи локального кэша в зашифрованную резервную копию.
+func (c *Commands) exportBackup(ctx context.Context, args []string) error {
+	path, rest, err := positional(args, "backup file")
+	if err != nil && len(args) > 0 && args[0] == "-" {
+		path, rest, err = "-", args[1:], nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("export")
+	passphraseStdin := fs.Bool("passphrase-stdin", false, "read the backup passphrase from stdin")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	b := backup.Backup{CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	err = c.eachLocalItem(ctx, token, models.ItemTypeUnspecified, models.DefaultSort, func(item models.Item, _ models.Payload) error {
+		b.Items = append(b.Items, backup.FromModel(item))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := c.readPassphrase(*passphraseStdin, true)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := backup.Write(&buf, b, []byte(passphrase)); err != nil {
+		return err
+	}
+
+	if path == "-" {
+		_, err = c.out.Write(buf.Bytes())
+		return err
+	}
+	if err := writePrivate(path, buf.Bytes()); err != nil {
+		return err
+	}
+
+	c.log.Info("backup exported", "items", len(b.Items), "path", path)
+	return c.render(out, resultView{
+		Status:  "exported",
+		Count:   len(b.Items),
+		message: fmt.Sprintf("%d items exported to %s", len(b.Items), path),
+	})
+}
+
+// restoreBackup - восстанавливает записи из резервной копии. Записи, которые уже есть в
+// кэше с тем же содержимым, пропускаются, поэтому восстановление можно повторять. Запись,
+// совпадающая с существующей (тот же логин, номер карты и т.п.), но с другими данными,
+// считается конфликтом и заменяется только с -overwrite.
+func (c *Commands) restoreBackup(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("import")
+	path := fs.String("from-backup", "", "backup file written by export, - for stdin")
+	passphraseStdin := fs.Bool("passphrase-stdin", false, "read the backup passphrase from stdin")
+	overwrite := fs.Bool("overwrite", false, "replace existing items that differ from the backup")
+	dryRun := fs.Bool("dry-run", false, "only show what would be restored")
+	noSync := fs.Bool("no-sync", false, "keep restored items in the local cache until the next sync")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+	if *path == "" {
+		return usagef("-from-backup is required")
+	}
+	if *path == "-" && *passphraseStdin {
+		return usagef("-passphrase-stdin cannot be used when the backup is read from stdin")
+	}
+
+	data, err := c.readInput(*path)
+	if err != nil {
+		return err
+	}
+	passphrase, err := c.readPassphrase(*passphraseStdin, false)
+	if err != nil {
+		return err
+	}
+	b, err := backup.Read(strings.NewReader(data), []byte(passphrase))
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	fingerprints := make(map[string]bool)
+	byKey := make(map[string]models.Item)
+	err = c.eachLocalItem(ctx, token, models.ItemTypeUnspecified, models.DefaultSort, func(item models.Item, payload models.Payload) error {
+		fingerprints[backup.Fingerprint(item)] = true
+		if key := importer.Key(item, payload); key != "" {
+			if _, ok := byKey[key]; !ok {
+				byKey[key] = item
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	view := importView{Format: "backup", DryRun: *dryRun, Items: []importItemView{}}
+	var created []models.Item
+	type update struct {
+		item    models.Item
+		payload models.Payload
+	}
+	var updates []update
+
+	for _, bi := range b.Items {
+		item := bi.Model()
+		fp := backup.Fingerprint(item)
+		if fingerprints[fp] {
+			view.Duplicates++
+			view.Items = append(view.Items, newImportItemView(item, importStatusDuplicate))
+			continue
+		}
+		fingerprints[fp] = true
+
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil {
+			view.Skipped++
+			view.Items = append(view.Items, importItemView{Title: item.Title, Type: item.Type.String(),
+				Status: importStatusSkipped, Reason: "invalid item data"})
+			continue
+		}
+
+		if existing, ok := byKey[importer.Key(item, payload)]; ok {
+			if !*overwrite {
+				view.Skipped++
+				view.Items = append(view.Items, importItemView{Title: item.Title, Type: item.Type.String(),
+					Status: importStatusSkipped, Reason: fmt.Sprintf("differs from item %d, use -overwrite", existing.ID)})
+				continue
+			}
+			item.ID = existing.ID
+			updates = append(updates, update{item: item, payload: payload})
+			view.Items = append(view.Items, newImportItemView(item, importStatusUpdated))
+			continue
+		}
+
+		created = append(created, item)
+		view.Items = append(view.Items, newImportItemView(item, importStatusNew))
+	}
+
+	if *dryRun || len(created)+len(updates) == 0 {
+		return c.render(out, view)
+	}
+
+	if len(created) > 0 {
+		if _, err := c.items.CreateItems(ctx, token, created); err != nil {
+			return err
+		}
+		view.Imported = len(created)
+	}
+	for _, u := range updates {
+		if _, err := c.items.UpdateItem(ctx, token, u.item, u.payload); err != nil {
+			return err
+		}
+		view.Updated++
+	}
+	for i := range view.Items {
+		if view.Items[i].Status == importStatusNew {
+			view.Items[i].Status = importStatusImported
+		}
+	}
+	c.log.Info("backup restored", "imported", view.Imported, "updated", view.Updated,
+		"duplicates", view.Duplicates, "skipped", view.Skipped)
+
+	return c.syncImported(ctx, token, out, view, *noSync)
+}
+
+// readPassphrase - парольная фраза копии. При вводе с терминала для новой копии фраза
+// запрашивается дважды.
+func (c *Commands) readPassphrase(fromStdin, confirm bool) (string, error) {
+	passphrase, err := c.readSecret("passphrase", "", fromStdin)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", backup.ErrEmptyPassphrase
+	}
+	if confirm && !fromStdin {
+		again, err := c.readSecret("repeat passphrase", "", false)
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", usagef("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// isFromBackupFlag - аргумент - флаг -from-backup (--from-backup, -from-backup=path).
+func isFromBackupFlag(arg string) bool {
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	return strings.HasPrefix(arg, "-") && (name == "from-backup" || strings.HasPrefix(name, "from-backup="))
+}
@@ -0,0 +1,21 @@
+package commands
+
+import "testing"
+
+func TestIsFromBackupFlag(t *testing.T) {
+	tests := map[string]bool{
+		"-from-backup":           true,
+		"--from-backup":          true,
+		"--from-backup=a.gkb":    true,
+		"-from-backup=a.gkb":     true,
+		"from-backup":            false,
+		"csv":                    false,
+		"---from-backup":         false,
+		"--from-backup-file=a.x": false,
+	}
+	for arg, want := range tests {
+		if got := isFromBackupFlag(arg); got != want {
+			t.Errorf("isFromBackupFlag(%q) = %v, want %v", arg, got, want)
+		}
+	}
+}
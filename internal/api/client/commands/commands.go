@@ -0,0 +1,297 @@
+// Package commands - неинтерактивные команды клиента для скриптов и CI.
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/agent"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/storage/sqlite"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+)
+
+// Коды завершения команд.
+const (
+	ExitOK              = 0
+	ExitError           = 1
+	ExitUsage           = 2
+	ExitNotFound        = 3
+	ExitUnauthenticated = 4
+)
+
+var (
+	ErrNotLoggedIn = errors.New("not logged in, run `login` first")
+)
+
+// usageError - ошибка в аргументах команды.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// exitStatusError - завершение с заданным кодом без сообщения об ошибке: код дочернего
+// процесса run или ответ помощника, который протокол требует выводить в stdout.
+type exitStatusError struct {
+	code int
+}
+
+func (e exitStatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// authHandlers - ручки авторизации.
+type authHandlers interface {
+	AuthUser(ctx context.Context, conn *grpc.ClientConn, login, password string) (string, error)
+	CurrentToken(ctx context.Context) (string, error)
+	CurrentLogin(ctx context.Context) (string, error)
+	Logout(ctx context.Context) error
+}
+
+// listService - постраничный список записей и дерево папок.
+type listService interface {
+	GetPage(ctx context.Context, token string, itemType models.ItemType, folderID int64, req models.PageRequest) ([]models.Item, string, error)
+	Folders(ctx context.Context, token string) ([]models.Folder, error)
+}
+
+// itemsService - операции над отдельной записью.
+type itemsService interface {
+	CreateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error)
+	CreateItems(ctx context.Context, token string, items []models.Item) ([]models.Item, error)
+	Resolve(ctx context.Context, token, ref string) (models.Item, error)
+	UpdateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error)
+	DeleteItem(ctx context.Context, token string, id int64) error
+	MoveItem(ctx context.Context, token string, id, folderID int64) (models.Item, error)
+}
+
+// vaultHandlers - синхронизация с сервером, поиск записей, их история, корзина, папки на сервере
+// и общий доступ к записям.
+type vaultHandlers interface {
+	Sync(ctx context.Context, conn *grpc.ClientConn, token string) (models.SyncResult, error)
+	Find(ctx context.Context, conn *grpc.ClientConn, token, title string) (models.Item, error)
+	SearchSealed(ctx context.Context, conn *grpc.ClientConn, token string, tokens []string) ([]models.Item, error)
+	Revisions(ctx context.Context, conn *grpc.ClientConn, token string, id int64) ([]models.Revision, error)
+	RestoreRevision(ctx context.Context, conn *grpc.ClientConn, token string, id, revisionID int64) (models.SyncResult, error)
+	Trash(ctx context.Context, conn *grpc.ClientConn, token string) ([]models.Item, error)
+	RestoreItem(ctx context.Context, conn *grpc.ClientConn, token string, serverID int64) (models.SyncResult, error)
+	PurgeItem(ctx context.Context, conn *grpc.ClientConn, token string, serverID int64) error
+	CreateFolder(ctx context.Context, conn *grpc.ClientConn, token string, folder models.Folder) (models.Folder, error)
+	UpdateFolder(ctx context.Context, conn *grpc.ClientConn, token string, folder models.Folder) (models.Folder, error)
+	DeleteFolder(ctx context.Context, conn *grpc.ClientConn, token string, id int64) (models.SyncResult, error)
+	EnsureKeyPair(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) error
+	Share(ctx context.Context, conn *grpc.ClientConn, token string, item models.Item, recipient string,
+		permission models.SharePermission, vaultKey []byte) (models.Share, error)
+	Unshare(ctx context.Context, conn *grpc.ClientConn, token string, id int64, recipient string) error
+	Shares(ctx context.Context, conn *grpc.ClientConn, token string, id int64) ([]models.Share, error)
+	SharedWithMe(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) ([]models.SharedItem, error)
+	UpdateSharedItem(ctx context.Context, conn *grpc.ClientConn, token string, item models.Item, vaultKey []byte) (models.SharedItem, error)
+}
+
+// command - подкоманда клиента.
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+// Commands - неинтерактивные команды клиента.
+type Commands struct {
+	log    *slog.Logger
+	auth   authHandlers
+	list   listService
+	items  itemsService
+	vault  vaultHandlers
+	agent  agentClient
+	conn   *grpc.ClientConn
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+
+	// agentChecked, agentUp - результат проверки агента за время команды
+	agentChecked bool
+	agentUp      bool
+}
+
+// New - конструктор команд. Ввод и вывод - стандартные потоки процесса.
+func New(log *slog.Logger, auth authHandlers, list listService, items itemsService, vault vaultHandlers, agent agentClient, conn *grpc.ClientConn) *Commands {
+	return &Commands{
+		log:    log,
+		auth:   auth,
+		list:   list,
+		items:  items,
+		vault:  vault,
+		agent:  agent,
+		conn:   conn,
+		in:     os.Stdin,
+		out:    os.Stdout,
+		errOut: os.Stderr,
+	}
+}
+
+// commands - все подкоманды по имени.
+func (c *Commands) commands() map[string]command {
+	return map[string]command{
+		"login":             {"login <login> [-password-stdin]", c.login},
+		"logout":            {"logout", c.logout},
+		"list":              {"list [-type login|note|binary|card|ssh|totp] [-sort updated|title] [-folder path]", c.listItems},
+		"get":               {"get <id|title> [-field name]", c.get},
+		"otp":               {"otp <id|title>", c.otp},
+		"add":               {"add login|note|card|totp [flags] [-seal [-master-password-stdin]] | add file|ssh <path> [flags] [-seal]", c.add},
+		"edit":              {"edit <id|title> [flags] [-seal] [-master-password-stdin]", c.edit},
+		"search":            {"search <words> [-reveal] [-master-password-stdin]", c.search},
+		"rm":                {"rm <id|title>", c.remove},
+		"move":              {"move <id|title> <folder path|/>", c.moveItem},
+		"folder":            {"folder [list] | folder add|rm <path> | folder rename <path> <name> | folder move <path> <parent path|/>", c.folder},
+		"trash":             {"trash [list] | trash restore|purge <id|title>", c.trash},
+		"share":             {"share <id|title> [<login> [-write] [-master-password-stdin]]", c.share},
+		"unshare":           {"unshare <id|title> <login>", c.unshare},
+		"shared":            {"shared [list] | shared get <id|title> [-field name] [-master-password-stdin] | shared edit <id|title> [flags] [-master-password-stdin]", c.shared},
+		"sync":              {"sync", c.syncItems},
+		"history":           {"history <id|title> [-show revision [-reveal]] [-restore revision]", c.history},
+		"import":            {"import keepass|bitwarden|csv <file|-> [-map field=column,...] [-dry-run] [-no-sync] [-seal] [-master-password-stdin] | import -from-backup <file> [-overwrite]", c.importItems},
+		"export":            {"export <file|-> [-passphrase-stdin]", c.exportBackup},
+		"credential":        {"credential get|store|erase < key=value lines", c.gitCredentialHelper},
+		"docker-credential": {"docker-credential get|store|erase|list", c.dockerCredentialHelper},
+		"render":            {"render <template|-> [-o file]", c.renderTemplate},
+		"agent":             {"agent [-socket path] [-ssh-socket path|off] [-ssh-confirm 30s] [-idle-timeout 15m]", c.agentServe},
+		"unlock":            {"unlock <login> [-password-stdin] [-master-password-stdin]", c.unlock},
+		"lock":              {"lock", c.lock},
+		"run":               {"run -env NAME=item:<id|title>[#field]... -- <command> [args]", c.runWithSecrets},
+	}
+}
+
+// Has - есть ли подкоманда с таким именем.
+func (c *Commands) Has(name string) bool {
+	_, ok := c.commands()[name]
+	return ok
+}
+
+// Run - выполняет подкоманду и возвращает код завершения.
+func (c *Commands) Run(ctx context.Context, args []string) int {
+	commands := c.commands()
+	if len(args) == 0 {
+		c.printUsage(commands)
+		return ExitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(c.errOut, "unknown command %q\n", args[0])
+		c.printUsage(commands)
+		return ExitUsage
+	}
+
+	err := cmd.run(ctx, args[1:])
+
+	// команда уже сообщила о результате сама, передаем только код
+	var exitStatus exitStatusError
+	if errors.As(err, &exitStatus) {
+		return exitStatus.code
+	}
+
+	code := exitCode(err)
+
+	switch {
+	case code == ExitOK:
+	case code == ExitUsage:
+		fmt.Fprintf(c.errOut, "error: %s\nusage: %s\n", err, cmd.usage)
+	default:
+		c.log.Error("command failed", "command", args[0], "error", err)
+		fmt.Fprintf(c.errOut, "error: %s\n", err)
+	}
+	return code
+}
+
+// printUsage - список подкоманд.
+func (c *Commands) printUsage(commands map[string]command) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(c.errOut, "usage: client [global flags] <command> [flags]\n\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(c.errOut, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(c.errOut, "\nevery command accepts -output table|json|yaml|env, list and get accept -reveal")
+}
+
+// exitCode - код завершения по ошибке команды.
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &usage):
+		return ExitUsage
+	case errors.Is(err, items_client.ErrItemNotFound), errors.Is(err, sqlite.ErrItemNotFound),
+		errors.Is(err, errRevisionNotFound), errors.Is(err, errNotInTrash), errors.Is(err, errFolderNotFound),
+		errors.Is(err, errNotShared), status.Code(err) == codes.NotFound:
+		return ExitNotFound
+	case errors.Is(err, agent.ErrItemNotFound):
+		return ExitNotFound
+	case errors.Is(err, ErrNotLoggedIn), errors.Is(err, agent.ErrLocked), errors.Is(err, vault.ErrWrongMasterPassword),
+		status.Code(err) == codes.Unauthenticated:
+		return ExitUnauthenticated
+	default:
+		return ExitError
+	}
+}
+
+// token - токен текущего пользователя: из агента, если он запущен, иначе из локальной сессии.
+func (c *Commands) token(ctx context.Context) (string, error) {
+	if c.agentRunning(ctx) {
+		return c.agent.Token(ctx)
+	}
+
+	token, err := c.auth.CurrentToken(ctx)
+	if errors.Is(err, sqlite.ErrNoSession) {
+		return "", ErrNotLoggedIn
+	}
+	return token, err
+}
+
+// newFlagSet - набор флагов подкоманды. Ошибки разбора возвращаются, а не завершают процесс.
+func (c *Commands) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	return fs
+}
+
+// parseFlags - разбирает флаги, ошибки разбора считаются ошибками использования.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected arguments: %v", fs.Args())
+	}
+	return nil
+}
+
+// positional - отделяет обязательный позиционный аргумент от флагов.
+func positional(args []string, name string) (string, []string, error) {
+	if len(args) == 0 || len(args[0]) > 0 && args[0][0] == '-' {
+		return "", nil, usagef("%s is required", name)
+	}
+	return args[0], args[1:], nil
+}
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/agent"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/storage/sqlite"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "ok", err: nil, want: ExitOK},
+		{name: "help", err: flag.ErrHelp, want: ExitOK},
+		{name: "other", err: errors.New("boom"), want: ExitError},
+		{name: "usage", err: usagef("id is required"), want: ExitUsage},
+		{name: "wrapped usage", err: fmt.Errorf("add: %w", usagef("-title is required")), want: ExitUsage},
+		{name: "cache not found", err: items_client.ErrItemNotFound, want: ExitNotFound},
+		{name: "sqlite not found", err: sqlite.ErrItemNotFound, want: ExitNotFound},
+		{name: "agent not found", err: agent.ErrItemNotFound, want: ExitNotFound},
+		{name: "server not found", err: status.Error(codes.NotFound, "item not found"), want: ExitNotFound},
+		{name: "not in trash", err: fmt.Errorf("%q: %w", "x", errNotInTrash), want: ExitNotFound},
+		{name: "not logged in", err: ErrNotLoggedIn, want: ExitUnauthenticated},
+		{name: "agent locked", err: agent.ErrLocked, want: ExitUnauthenticated},
+		{name: "wrong master password", err: vault.ErrWrongMasterPassword, want: ExitUnauthenticated},
+		{name: "server unauthenticated", err: status.Error(codes.Unauthenticated, "bad token"), want: ExitUnauthenticated},
+		{name: "server unavailable", err: status.Error(codes.Unavailable, "down"), want: ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeAuth - сервер и локальная сессия в памяти.
+type fakeAuth struct {
+	passwords map[string]string
+	login     string
+	token     string
+}
+
+func (a *fakeAuth) AuthUser(ctx context.Context, conn *grpc.ClientConn, login, password string) (string, error) {
+	if a.passwords[login] != password {
+		return "", status.Error(codes.Unauthenticated, "invalid login or password")
+	}
+	a.login, a.token = login, "token-"+login
+	return a.token, nil
+}
+
+func (a *fakeAuth) CurrentToken(ctx context.Context) (string, error) {
+	if a.token == "" {
+		return "", sqlite.ErrNoSession
+	}
+	return a.token, nil
+}
+
+func (a *fakeAuth) CurrentLogin(ctx context.Context) (string, error) {
+	if a.token == "" {
+		return "", sqlite.ErrNoSession
+	}
+	return a.login, nil
+}
+
+func (a *fakeAuth) Logout(ctx context.Context) error {
+	a.login, a.token = "", ""
+	return nil
+}
+
+func newTestCommands(auth authHandlers, stdin string) (*Commands, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &Commands{
+		log:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		auth:   auth,
+		in:     strings.NewReader(stdin),
+		out:    &out,
+		errOut: io.Discard,
+	}, &out
+}
+
+func TestLoginLogout(t *testing.T) {
+	ctx := context.Background()
+	auth := &fakeAuth{passwords: map[string]string{"alice": "pw"}}
+
+	c, _ := newTestCommands(auth, "wrong\n")
+	if code := c.Run(ctx, []string{"login", "alice", "-password-stdin"}); code != ExitUnauthenticated {
+		t.Errorf("login with wrong password: exit %d, want %d", code, ExitUnauthenticated)
+	}
+	if _, err := c.token(ctx); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("token after failed login: %v, want ErrNotLoggedIn", err)
+	}
+
+	c, out := newTestCommands(auth, "pw\n")
+	if code := c.Run(ctx, []string{"login", "alice", "-password-stdin", "-output", "json"}); code != ExitOK {
+		t.Fatalf("login: exit %d", code)
+	}
+	if !strings.Contains(out.String(), `"status": "logged_in"`) {
+		t.Errorf("login output = %s", out.String())
+	}
+	if token, err := c.token(ctx); err != nil || token != "token-alice" {
+		t.Errorf("token after login = %q, %v", token, err)
+	}
+
+	c, _ = newTestCommands(auth, "")
+	if code := c.Run(ctx, []string{"logout"}); code != ExitOK {
+		t.Fatalf("logout: exit %d", code)
+	}
+	if _, err := c.token(ctx); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("token after logout: %v, want ErrNotLoggedIn", err)
+	}
+
+	if code := c.Run(ctx, []string{"login"}); code != ExitUsage {
+		t.Errorf("login without login: exit %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestUnlockSecrets(t *testing.T) {
+	tests := []struct {
+		name         string
+		stdin        string
+		wantPassword string
+		wantMaster   string
+		wantErr      bool
+	}{
+		{name: "two lines", stdin: "pw\nmaster\n", wantPassword: "pw", wantMaster: "master"},
+		{name: "crlf", stdin: "pw\r\nmaster\r\n", wantPassword: "pw", wantMaster: "master"},
+		{name: "one line", stdin: "pw\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestCommands(&fakeAuth{}, tt.stdin)
+			password, master, err := c.unlockSecrets(true, true)
+			if tt.wantErr {
+				if exitCode(err) != ExitUsage {
+					t.Errorf("unlockSecrets() error = %v, want usage error", err)
+				}
+				return
+			}
+			if err != nil || password != tt.wantPassword || master != tt.wantMaster {
+				t.Errorf("unlockSecrets() = %q, %q, %v", password, master, err)
+			}
+		})
+	}
+}
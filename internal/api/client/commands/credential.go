@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"goph-keeper/internal/models"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// gitCredential - описание учетных данных в протоколе git-credential.
+type gitCredential struct {
+	protocol string
+	host     string
+	path     string
+	username string
+	password string
+}
+
+// parseGitCredential - читает строки key=value до пустой строки или конца ввода.
+// Неизвестные ключи (capability[], wwwauth[] и т.п.) пропускаются.
+func parseGitCredential(r io.Reader) (gitCredential, error) {
+	var cred gitCredential
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return cred, fmt.Errorf("invalid credential line %q", line)
+		}
+
+		switch key {
+		case "protocol":
+			cred.protocol = value
+		case "host":
+			cred.host = value
+		case "path":
+			cred.path = value
+		case "username":
+			cred.username = value
+		case "password":
+			cred.password = value
+		case "url":
+			u, err := url.Parse(value)
+			if err != nil {
+				return cred, fmt.Errorf("invalid credential url: %w", err)
+			}
+			cred.protocol, cred.host = u.Scheme, u.Host
+			cred.path = strings.TrimPrefix(u.Path, "/")
+			if u.User != nil {
+				cred.username = u.User.Username()
+			}
+		}
+	}
+	return cred, scanner.Err()
+}
+
+// resource - значение поля resource для записи: protocol://host[/path].
+func (g gitCredential) resource() string {
+	resource := g.protocol + "://" + g.host
+	if g.path != "" {
+		resource += "/" + strings.TrimPrefix(g.path, "/")
+	}
+	return resource
+}
+
+// matchRank - насколько resource записи подходит запросу git: 3 - совпал путь, 2 - протокол
+// и хост, 1 - только хост (resource без протокола), 0 - не подходит.
+func (g gitCredential) matchRank(resource string) int {
+	resource = strings.TrimSuffix(strings.TrimSpace(resource), "/")
+	if resource == "" {
+		return 0
+	}
+
+	var protocol, host, path string
+	if scheme, rest, ok := strings.Cut(resource, "://"); ok {
+		protocol = scheme
+		host, path, _ = strings.Cut(rest, "/")
+	} else {
+		host, path, _ = strings.Cut(resource, "/")
+	}
+
+	if !strings.EqualFold(host, g.host) || protocol != "" && !strings.EqualFold(protocol, g.protocol) {
+		return 0
+	}
+	switch {
+	case path != "":
+		if strings.TrimSuffix(path, "/") != strings.TrimSuffix(strings.TrimPrefix(g.path, "/"), "/") {
+			return 0
+		}
+		return 3
+	case protocol != "":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// credentialMatch - запись login, подходящая запросу git.
+type credentialMatch struct {
+	item    models.Item
+	payload models.Payload
+	rank    int
+}
+
+// gitCredentialHelper - помощник git-credential: get, store или erase.
+// Git не ждет ответа на store и erase, а пустой ответ на get означает «не найдено».
+func (c *Commands) gitCredentialHelper(ctx context.Context, args []string) error {
+	op, rest, err := positional(args, "operation")
+	if err != nil {
+		return err
+	}
+	if err := parseFlags(c.newFlagSet("credential"), rest); err != nil {
+		return err
+	}
+
+	cred, err := parseGitCredential(c.in)
+	if err != nil {
+		return err
+	}
+	if cred.host == "" {
+		// без хоста сопоставлять нечего, git продолжит с другими помощниками
+		return nil
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case "get":
+		return c.credentialGet(ctx, token, cred)
+	case "store":
+		return c.credentialStore(ctx, token, cred)
+	case "erase":
+		return c.credentialErase(ctx, token, cred)
+	default:
+		// git требует молча игнорировать неизвестные операции
+		return nil
+	}
+}
+
+// credentialGet - выводит логин и пароль лучшей подходящей записи.
+func (c *Commands) credentialGet(ctx context.Context, token string, cred gitCredential) error {
+	matches, err := c.credentialMatches(ctx, token, cred, false)
+	if err != nil || len(matches) == 0 {
+		return err
+	}
+
+	best := matches[0]
+	for _, m := range matches[1:] {
+		// записи идут от недавно измененных, поэтому при равенстве остается более свежая
+		if m.rank > best.rank {
+			best = m
+		}
+	}
+
+	fmt.Fprintf(c.out, "username=%s\npassword=%s\n", best.payload.Login.Login, best.payload.Login.Password)
+	return nil
+}
+
+// credentialStore - сохраняет пароль, принятый сервером git: обновляет запись с тем же
+// resource и логином или создает новую с меткой git.
+func (c *Commands) credentialStore(ctx context.Context, token string, cred gitCredential) error {
+	if cred.username == "" || cred.password == "" {
+		return nil
+	}
+
+	matches, err := c.credentialMatches(ctx, token, cred, true)
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if m.payload.Login.Resource != cred.resource() {
+			continue
+		}
+		if m.payload.Login.Password == cred.password {
+			return nil
+		}
+		m.payload.Login.Password = cred.password
+		_, err := c.items.UpdateItem(ctx, token, m.item, m.payload)
+		return err
+	}
+
+	item := models.Item{Title: cred.host, Tags: []string{"git"}}
+	payload := models.Payload{Login: &models.LoginPayload{
+		Resource: cred.resource(),
+		Login:    cred.username,
+		Password: cred.password,
+	}}
+	_, err = c.items.CreateItem(ctx, token, item, payload)
+	return err
+}
+
+// credentialErase - удаляет записи, которые git счел недействительными. Если git передал
+// пароль, удаляются только записи с этим паролем.
+func (c *Commands) credentialErase(ctx context.Context, token string, cred gitCredential) error {
+	matches, err := c.credentialMatches(ctx, token, cred, true)
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if cred.password != "" && m.payload.Login.Password != cred.password {
+			continue
+		}
+		if err := c.items.DeleteItem(ctx, token, m.item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// credentialMatches - записи login, подходящие запросу git, от недавно измененных.
+// Если git передал username, учитываются только записи с этим логином. При forUpdate
+// записи читаются из локального кэша, минуя агента.
+func (c *Commands) credentialMatches(ctx context.Context, token string, cred gitCredential, forUpdate bool) ([]credentialMatch, error) {
+	var matches []credentialMatch
+
+	each := c.eachItem
+	if forUpdate {
+		each = c.eachLocalItem
+	}
+
+	err := each(ctx, token, models.ItemTypeLogin, models.DefaultSort, func(item models.Item, payload models.Payload) error {
+		if payload.Login == nil {
+			return nil
+		}
+		if cred.username != "" && payload.Login.Login != cred.username {
+			return nil
+		}
+		if rank := cred.matchRank(payload.Login.Resource); rank > 0 {
+			matches = append(matches, credentialMatch{item: item, payload: payload, rank: rank})
+		}
+		return nil
+	})
+	return matches, err
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGitCredential(t *testing.T) {
+	in := "capability[]=authtype\nprotocol=https\nhost=example.com\nusername=alice\n\nhost=ignored\n"
+	cred, err := parseGitCredential(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.protocol != "https" || cred.host != "example.com" || cred.username != "alice" {
+		t.Errorf("unexpected credential %+v", cred)
+	}
+	if cred.resource() != "https://example.com" {
+		t.Errorf("resource = %q", cred.resource())
+	}
+
+	cred, err = parseGitCredential(strings.NewReader("url=https://bob@example.com/org/repo.git\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.resource() != "https://example.com/org/repo.git" || cred.username != "bob" {
+		t.Errorf("unexpected credential from url %+v", cred)
+	}
+
+	if _, err := parseGitCredential(strings.NewReader("garbage\n")); err == nil {
+		t.Error("expected an error for a line without =")
+	}
+}
+
+func TestMatchRank(t *testing.T) {
+	cred := gitCredential{protocol: "https", host: "example.com", path: "org/repo.git"}
+
+	tests := []struct {
+		resource string
+		want     int
+	}{
+		{"https://example.com/org/repo.git", 3},
+		{"https://Example.com/", 2},
+		{"example.com", 1},
+		{"http://example.com", 0},
+		{"https://example.com/other.git", 0},
+		{"https://example.org", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := cred.matchRank(tt.resource); got != tt.want {
+			t.Errorf("matchRank(%q) = %d, want %d", tt.resource, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// errDockerCredentialsNotFound - ответ get и erase, по тексту которого docker понимает,
+// что учетных данных нет.
+var errDockerCredentialsNotFound = errors.New("credentials not found in native keychain")
+
+const (
+	// dockerHubHost - хост Docker Hub; docker обращается к нему по dockerHubServerURL.
+	dockerHubHost      = "index.docker.io"
+	dockerHubServerURL = "https://index.docker.io/v1/"
+
+	// dockerTag - метка записей, сохраненных помощником docker.
+	dockerTag = "docker"
+)
+
+// dockerCredentials - сообщение протокола docker-credential-helpers.
+type dockerCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// registryHost - хост реестра из адреса сервера или значения resource: без схемы и пути.
+func registryHost(serverURL string) string {
+	s := strings.TrimSpace(serverURL)
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSpace(serverURL))
+	}
+	return strings.ToLower(u.Host)
+}
+
+// dockerServerURL - адрес сервера для ответа list.
+func dockerServerURL(host string) string {
+	if host == dockerHubHost {
+		return dockerHubServerURL
+	}
+	return host
+}
+
+// dockerCredentialHelper - помощник docker-credential: get, store, erase или list.
+// Ошибки протокол требует выводить в stdout с кодом завершения 1.
+func (c *Commands) dockerCredentialHelper(ctx context.Context, args []string) error {
+	op, rest, err := positional(args, "operation")
+	if err != nil {
+		return err
+	}
+	if err := parseFlags(c.newFlagSet("docker-credential"), rest); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err == nil {
+		switch op {
+		case "get":
+			err = c.dockerGet(ctx, token)
+		case "store":
+			err = c.dockerStore(ctx, token)
+		case "erase":
+			err = c.dockerErase(ctx, token)
+		case "list":
+			err = c.dockerList(ctx, token)
+		default:
+			return usagef("unknown operation %q", op)
+		}
+	}
+	if err != nil {
+		c.log.Error("docker credential helper failed", "operation", op, "error", err)
+		fmt.Fprintln(c.out, err)
+		return exitStatusError{code: ExitError}
+	}
+	return nil
+}
+
+// readServerURL - адрес сервера, который docker передает в stdin для get и erase.
+func (c *Commands) readServerURL() (string, error) {
+	data, err := io.ReadAll(c.in)
+	if err != nil {
+		return "", err
+	}
+	serverURL := strings.TrimSpace(string(data))
+	if serverURL == "" {
+		return "", errors.New("no credentials server URL")
+	}
+	return serverURL, nil
+}
+
+// dockerGet - выводит логин и пароль для реестра.
+func (c *Commands) dockerGet(ctx context.Context, token string) error {
+	serverURL, err := c.readServerURL()
+	if err != nil {
+		return err
+	}
+
+	matches, err := c.dockerMatches(ctx, token, registryHost(serverURL), false)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return errDockerCredentialsNotFound
+	}
+
+	// записи идут от недавно измененных
+	login := matches[0].payload.Login
+	return json.NewEncoder(c.out).Encode(dockerCredentials{
+		ServerURL: serverURL,
+		Username:  login.Login,
+		Secret:    login.Password,
+	})
+}
+
+// dockerStore - сохраняет учетные данные реестра: обновляет запись docker с тем же хостом
+// или создает новую с меткой docker.
+func (c *Commands) dockerStore(ctx context.Context, token string) error {
+	var creds dockerCredentials
+	if err := json.NewDecoder(c.in).Decode(&creds); err != nil {
+		return fmt.Errorf("invalid credentials: %w", err)
+	}
+	if creds.ServerURL == "" {
+		return errors.New("no credentials server URL")
+	}
+	if creds.Username == "" {
+		return errors.New("no credentials username")
+	}
+
+	host := registryHost(creds.ServerURL)
+	matches, err := c.dockerMatches(ctx, token, host, true)
+	if err != nil {
+		return err
+	}
+
+	// у docker на реестр одна пара логин/пароль, поэтому обновляется самая свежая запись
+	if len(matches) > 0 {
+		m := matches[0]
+		m.payload.Login.Login = creds.Username
+		m.payload.Login.Password = creds.Secret
+		_, err := c.items.UpdateItem(ctx, token, m.item, m.payload)
+		return err
+	}
+
+	item := models.Item{Title: host, Tags: []string{dockerTag}}
+	payload := models.Payload{Login: &models.LoginPayload{
+		Resource: host,
+		Login:    creds.Username,
+		Password: creds.Secret,
+	}}
+	_, err = c.items.CreateItem(ctx, token, item, payload)
+	return err
+}
+
+// dockerErase - удаляет записи docker реестра.
+func (c *Commands) dockerErase(ctx context.Context, token string) error {
+	serverURL, err := c.readServerURL()
+	if err != nil {
+		return err
+	}
+
+	matches, err := c.dockerMatches(ctx, token, registryHost(serverURL), true)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return errDockerCredentialsNotFound
+	}
+
+	for _, m := range matches {
+		if err := c.items.DeleteItem(ctx, token, m.item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerList - выводит адреса реестров и логины записей с меткой docker. Остальные
+// записи login в список не попадают, чтобы не раскрывать docker все сайты пользователя.
+func (c *Commands) dockerList(ctx context.Context, token string) error {
+	list := make(map[string]string)
+
+	err := c.eachItem(ctx, token, models.ItemTypeLogin, models.DefaultSort, func(item models.Item, payload models.Payload) error {
+		if payload.Login == nil || !hasTag(item, dockerTag) {
+			return nil
+		}
+		serverURL := dockerServerURL(registryHost(payload.Login.Resource))
+		if _, ok := list[serverURL]; !ok {
+			list[serverURL] = payload.Login.Login
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(c.out).Encode(list)
+}
+
+// dockerMatches - записи login с меткой docker, у которых resource указывает на хост реестра,
+// от недавно измененных. Остальные записи login того же хоста (вход на сайт, учетные данные
+// git) docker не читает и не меняет. При forUpdate записи читаются из локального кэша, минуя агента.
+func (c *Commands) dockerMatches(ctx context.Context, token, host string, forUpdate bool) ([]credentialMatch, error) {
+	var matches []credentialMatch
+
+	each := c.eachItem
+	if forUpdate {
+		each = c.eachLocalItem
+	}
+
+	err := each(ctx, token, models.ItemTypeLogin, models.DefaultSort, func(item models.Item, payload models.Payload) error {
+		if payload.Login == nil || payload.Login.Resource == "" || !hasTag(item, dockerTag) {
+			return nil
+		}
+		if registryHost(payload.Login.Resource) == host {
+			matches = append(matches, credentialMatch{item: item, payload: payload})
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// hasTag - есть ли у записи метка.
+func hasTag(item models.Item, tag string) bool {
+	for _, t := range item.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"goph-keeper/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestRegistryHost(t *testing.T) {
+	tests := map[string]string{
+		"https://index.docker.io/v1/":  "index.docker.io",
+		"registry.example.com:5000":    "registry.example.com:5000",
+		"https://GHCR.io":              "ghcr.io",
+		"ghcr.io/org/image":            "ghcr.io",
+		"  quay.io  ":                  "quay.io",
+		"http://localhost:5000/v2/foo": "localhost:5000",
+	}
+	for in, want := range tests {
+		if got := registryHost(in); got != want {
+			t.Errorf("registryHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if dockerServerURL("index.docker.io") != dockerHubServerURL || dockerServerURL("ghcr.io") != "ghcr.io" {
+		t.Error("unexpected server URL for list")
+	}
+}
+
+// memList - постраничный список записей memItems одной страницей.
+type memList struct {
+	listService
+	items *memItems
+}
+
+func (l memList) GetPage(ctx context.Context, token string, itemType models.ItemType, folderID int64,
+	req models.PageRequest) ([]models.Item, string, error) {
+	var page []models.Item
+	for _, item := range l.items.items {
+		if itemType == models.ItemTypeUnspecified || item.Type == itemType {
+			page = append(page, item)
+		}
+	}
+	return page, "", nil
+}
+
+func TestDockerCredentialHelper_OtherLogins(t *testing.T) {
+	ctx := context.Background()
+	auth := &fakeAuth{login: "alice", token: "tok"}
+	items := &memItems{}
+
+	run := func(stdin string, args ...string) (int, string) {
+		c, out := newTestCommands(auth, stdin)
+		c.items = items
+		c.list = memList{items: items}
+		return c.Run(ctx, append([]string{"docker-credential"}, args...)), out.String()
+	}
+
+	// вход на сайт и учетные данные git с хостом реестра
+	for _, payload := range []*models.LoginPayload{
+		{Resource: "https://gitlab.example.com/users/sign_in", Login: "alice", Password: "web"},
+		{Resource: "gitlab.example.com", Login: "alice", Password: "git"},
+	} {
+		if _, err := items.CreateItem(ctx, "tok", models.Item{Title: "gitlab"}, models.Payload{Login: payload}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if code, out := run("gitlab.example.com", "get"); code != ExitError || !strings.Contains(out, errDockerCredentialsNotFound.Error()) {
+		t.Errorf("get without docker items: exit %d, %q", code, out)
+	}
+
+	if code, out := run(`{"ServerURL":"gitlab.example.com","Username":"ci","Secret":"token"}`, "store"); code != ExitOK {
+		t.Fatalf("store: exit %d, %q", code, out)
+	}
+	if len(items.items) != 3 || !hasTag(items.items[2], dockerTag) {
+		t.Fatalf("expected a new docker item, got %+v", items.items)
+	}
+
+	code, out := run("https://gitlab.example.com", "get")
+	if code != ExitOK {
+		t.Fatalf("get: exit %d, %q", code, out)
+	}
+	var creds dockerCredentials
+	if err := json.Unmarshal([]byte(out), &creds); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.Username != "ci" || creds.Secret != "token" {
+		t.Errorf("get returned %+v, want the docker item", creds)
+	}
+
+	if code, out := run("gitlab.example.com", "erase"); code != ExitOK {
+		t.Fatalf("erase: exit %d, %q", code, out)
+	}
+	if len(items.items) != 2 {
+		t.Fatalf("expected only the docker item erased, got %d items", len(items.items))
+	}
+	for i, want := range []string{"web", "git"} {
+		payload, err := models.DecodePayload(items.items[i].Payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if payload.Login.Password != want {
+			t.Errorf("item %d password = %q, want %q", i, payload.Login.Password, want)
+		}
+	}
+}
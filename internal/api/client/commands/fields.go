@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/totp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field - поле записи для вывода команды get -field.
+type field struct {
+	name   string
+	value  string
+	secret bool
+}
+
+// itemFields - поля записи в порядке вывода.
+func itemFields(item models.Item, payload models.Payload) []field {
+	fields := []field{
+		{name: "id", value: strconv.FormatInt(item.ID, 10)},
+		{name: "type", value: item.Type.String()},
+		{name: "title", value: item.Title},
+		{name: "tags", value: strings.Join(item.Tags, ",")},
+		{name: "favorite", value: strconv.FormatBool(item.Favorite)},
+		{name: "created", value: item.CreatedAt.UTC().Format(time.RFC3339)},
+		{name: "updated", value: item.UpdatedAt.UTC().Format(time.RFC3339)},
+	}
+
+	switch {
+	case payload.Login != nil:
+		fields = append(fields,
+			field{name: "resource", value: payload.Login.Resource},
+			field{name: "login", value: payload.Login.Login},
+			field{name: "password", value: payload.Login.Password, secret: true},
+		)
+	case payload.Note != nil:
+		fields = append(fields, field{name: "text", value: payload.Note.Text, secret: true})
+	case payload.Binary != nil:
+		fields = append(fields,
+			field{name: "name", value: payload.Binary.Name},
+			field{name: "size", value: strconv.Itoa(len(payload.Binary.Data))},
+			field{name: "data", value: string(payload.Binary.Data), secret: true},
+		)
+	case payload.Card != nil:
+		fields = append(fields,
+			field{name: "number", value: payload.Card.Number, secret: true},
+			field{name: "holder", value: payload.Card.Holder},
+			field{name: "expiry", value: payload.Card.Expiry},
+			field{name: "cvv", value: payload.Card.CVV, secret: true},
+		)
+	case payload.SSHKey != nil:
+		fields = append(fields,
+			field{name: "private_key", value: payload.SSHKey.PrivateKey, secret: true},
+			field{name: "public_key", value: payload.SSHKey.PublicKey},
+			field{name: "comment", value: payload.SSHKey.Comment},
+			field{name: "fingerprint", value: payload.SSHKey.Fingerprint},
+		)
+	case payload.TOTP != nil:
+		// код на текущий момент, чтобы его можно было получить через -field и run
+		code, _ := totp.Code(*payload.TOTP, time.Now())
+		fields = append(fields,
+			field{name: "code", value: code},
+			field{name: "issuer", value: payload.TOTP.Issuer},
+			field{name: "account", value: payload.TOTP.Account},
+			field{name: "algorithm", value: payload.TOTP.Algorithm},
+			field{name: "digits", value: strconv.Itoa(payload.TOTP.Digits)},
+			field{name: "period", value: strconv.Itoa(payload.TOTP.Period)},
+			field{name: "secret", value: payload.TOTP.Secret, secret: true},
+		)
+	}
+	return fields
+}
+
+// lookupField - поле записи по имени.
+func lookupField(fields []field, name string) (field, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// parseType - тип записи по названию, пустая строка - все типы.
+func parseType(name string) (models.ItemType, error) {
+	switch name {
+	case "":
+		return models.ItemTypeUnspecified, nil
+	case "login":
+		return models.ItemTypeLogin, nil
+	case "note":
+		return models.ItemTypeNote, nil
+	case "binary", "file":
+		return models.ItemTypeBinary, nil
+	case "card":
+		return models.ItemTypeCard, nil
+	case "ssh":
+		return models.ItemTypeSSHKey, nil
+	case "totp":
+		return models.ItemTypeTOTP, nil
+	default:
+		return models.ItemTypeUnspecified, usagef("unknown item type %q", name)
+	}
+}
+
+// parseSort - сортировка списка по названию поля.
+func parseSort(name string) (models.Sort, error) {
+	switch name {
+	case "", "updated":
+		return models.DefaultSort, nil
+	case "title":
+		return models.Sort{Field: models.SortByTitle}, nil
+	default:
+		return models.Sort{}, usagef("unknown sort %q", name)
+	}
+}
+
+// splitTags - метки из строки через запятую.
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var errFolderNotFound = errors.New("folder not found")
+
+// folderView - папка в структурированном выводе, ID - id папки на сервере.
+type folderView struct {
+	ID       int64  `json:"id" yaml:"id"`
+	ParentID int64  `json:"parent_id" yaml:"parent_id"`
+	Path     string `json:"path" yaml:"path"`
+}
+
+// folderList - папки в порядке путей.
+type folderList []folderView
+
+// table - таблица папок.
+func (l folderList) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPATH")
+	for _, v := range l {
+		fmt.Fprintf(tw, "%d\t%s\n", v.ID, v.Path)
+	}
+	return tw.Flush()
+}
+
+// env - переменные GK_COUNT и GK_<N>_<ПОЛЕ> для каждой папки.
+func (l folderList) env() []pair {
+	pairs := []pair{{"GK_COUNT", strconv.Itoa(len(l))}}
+	for i, v := range l {
+		pairs = append(pairs, prefixed(fmt.Sprintf("GK_%d_", i), []pair{
+			{"id", strconv.FormatInt(v.ID, 10)},
+			{"parent_id", strconv.FormatInt(v.ParentID, 10)},
+			{"path", v.Path},
+		})...)
+	}
+	return pairs
+}
+
+// folder - дерево папок на сервере: список, создание, переименование, перенос и удаление.
+// Папки задаются путем от корня через "/", например work/servers.
+func (c *Commands) folder(ctx context.Context, args []string) error {
+	op := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		op, args = args[0], args[1:]
+	}
+
+	var (
+		path, target string
+		rest         = args
+		err          error
+	)
+	switch op {
+	case "list":
+	case "add", "rm":
+		if path, rest, err = positional(args, "folder path"); err != nil {
+			return err
+		}
+	case "rename", "move":
+		if path, rest, err = positional(args, "folder path"); err != nil {
+			return err
+		}
+		if target, rest, err = positional(rest, "target"); err != nil {
+			return err
+		}
+	default:
+		return usagef("unknown folder operation %q", op)
+	}
+
+	fs := c.newFlagSet("folder")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	folders, err := c.list.Folders(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case "list":
+		paths := folderPaths(folders)
+		list := make(folderList, 0, len(folders))
+		for _, f := range folders {
+			list = append(list, folderView{ID: f.ID, ParentID: f.ParentID, Path: paths[f.ID]})
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+		return c.render(out, list)
+
+	case "add":
+		parts := splitFolderPath(path)
+		if len(parts) == 0 {
+			return usagef("folder name is required")
+		}
+		parent, err := findFolder(folders, strings.Join(parts[:len(parts)-1], "/"))
+		if err != nil {
+			return err
+		}
+		created, err := c.vault.CreateFolder(ctx, c.conn, token, models.Folder{ParentID: parent.ID, Name: parts[len(parts)-1]})
+		if err != nil {
+			return err
+		}
+		return c.render(out, resultView{
+			Status:  "created",
+			ID:      created.ID,
+			message: fmt.Sprintf("folder %q created", strings.Join(parts, "/")),
+		})
+	}
+
+	f, err := findFolder(folders, path)
+	if err != nil {
+		return err
+	}
+	if f.ID == 0 {
+		return usagef("root folder cannot be changed")
+	}
+
+	switch op {
+	case "rename":
+		f.Name = target
+		if _, err := c.vault.UpdateFolder(ctx, c.conn, token, f); err != nil {
+			return err
+		}
+		return c.render(out, resultView{
+			Status:  "renamed",
+			ID:      f.ID,
+			message: fmt.Sprintf("folder %q renamed to %q", path, target),
+		})
+
+	case "move":
+		parent, err := findFolder(folders, target)
+		if err != nil {
+			return err
+		}
+		f.ParentID = parent.ID
+		if _, err := c.vault.UpdateFolder(ctx, c.conn, token, f); err != nil {
+			return err
+		}
+		return c.render(out, resultView{
+			Status:  "moved",
+			ID:      f.ID,
+			message: fmt.Sprintf("folder %q moved to %q", path, target),
+		})
+	}
+
+	if _, err := c.vault.DeleteFolder(ctx, c.conn, token, f.ID); err != nil {
+		return err
+	}
+	return c.render(out, resultView{
+		Status:  "deleted",
+		ID:      f.ID,
+		message: fmt.Sprintf("folder %q deleted, its items moved to the root", path),
+	})
+}
+
+// moveItem - переносит запись в папку локально, на сервер перенос уходит при синхронизации.
+func (c *Commands) moveItem(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+	path, rest, err := positional(rest, "folder path")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("move")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	folders, err := c.list.Folders(ctx, token)
+	if err != nil {
+		return err
+	}
+	f, err := findFolder(folders, path)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.items.Resolve(ctx, token, ref)
+	if err != nil {
+		return err
+	}
+	if _, err := c.items.MoveItem(ctx, token, item.ID, f.ID); err != nil {
+		return err
+	}
+
+	return c.render(out, resultView{
+		Status:  "moved",
+		ID:      item.ID,
+		message: fmt.Sprintf("item %q moved to %q", item.Title, "/"+folderPaths(folders)[f.ID]),
+	})
+}
+
+// splitFolderPath - разбивает путь папки на имена, пустые части пропускаются.
+// Пустой результат означает корень.
+func splitFolderPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// findFolder - находит папку по пути от корня. Для корня ("/" или "")
+// возвращается папка с нулевым ID.
+func findFolder(folders []models.Folder, path string) (models.Folder, error) {
+	var current models.Folder
+	for _, name := range splitFolderPath(path) {
+		found := false
+		for _, f := range folders {
+			if f.ParentID == current.ID && f.Name == name {
+				current, found = f, true
+				break
+			}
+		}
+		if !found {
+			return models.Folder{}, fmt.Errorf("%q: %w", path, errFolderNotFound)
+		}
+	}
+	return current, nil
+}
+
+// folderPaths - пути папок от корня по id папки.
+func folderPaths(folders []models.Folder) map[int64]string {
+	byID := make(map[int64]models.Folder, len(folders))
+	for _, f := range folders {
+		byID[f.ID] = f
+	}
+
+	paths := make(map[int64]string, len(folders))
+	for _, f := range folders {
+		parts := []string{f.Name}
+		// глубина ограничена числом папок на случай испорченного дерева в кэше
+		for parent, depth := f.ParentID, 0; parent != 0 && depth < len(folders); depth++ {
+			p, ok := byID[parent]
+			if !ok {
+				break
+			}
+			parts = append([]string{p.Name}, parts...)
+			parent = p.ParentID
+		}
+		paths[f.ID] = strings.Join(parts, "/")
+	}
+	return paths
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"errors"
+	"goph-keeper/internal/models"
+	"testing"
+)
+
+func TestFindFolder(t *testing.T) {
+	folders := []models.Folder{
+		{ID: 1, Name: "work"},
+		{ID: 2, ParentID: 1, Name: "servers"},
+		{ID: 3, Name: "home"},
+		{ID: 4, ParentID: 3, Name: "servers"},
+	}
+
+	tests := []struct {
+		path   string
+		wantID int64
+		err    error
+	}{
+		{path: "/", wantID: 0},
+		{path: "", wantID: 0},
+		{path: "work", wantID: 1},
+		{path: "/work/servers/", wantID: 2},
+		// одинаковые имена в разных ветках различаются по пути
+		{path: "home/servers", wantID: 4},
+		{path: "servers", err: errFolderNotFound},
+		{path: "work/missing", err: errFolderNotFound},
+	}
+	for _, tt := range tests {
+		f, err := findFolder(folders, tt.path)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("findFolder(%q) error = %v, want %v", tt.path, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || f.ID != tt.wantID {
+			t.Errorf("findFolder(%q) = %d, %v, want %d", tt.path, f.ID, err, tt.wantID)
+		}
+	}
+}
+
+func TestFolderPaths(t *testing.T) {
+	folders := []models.Folder{
+		{ID: 1, Name: "work"},
+		{ID: 2, ParentID: 1, Name: "servers"},
+		{ID: 3, ParentID: 2, Name: "db"},
+		// цикл в испорченном кэше не должен зациклить построение путей
+		{ID: 5, ParentID: 6, Name: "a"},
+		{ID: 6, ParentID: 5, Name: "b"},
+	}
+
+	paths := folderPaths(folders)
+	for id, want := range map[int64]string{1: "work", 2: "work/servers", 3: "work/servers/db"} {
+		if paths[id] != want {
+			t.Errorf("folderPaths()[%d] = %q, want %q", id, paths[id], want)
+		}
+	}
+	if _, ok := paths[5]; !ok {
+		t.Errorf("folderPaths() has no path for folder in cycle")
+	}
+}
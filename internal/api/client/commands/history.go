@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+var errRevisionNotFound = errors.New("revision not found")
+
+// revisionView - ревизия записи в структурированном выводе: кто и когда изменил запись.
+type revisionView struct {
+	ID        int64     `json:"id" yaml:"id"`
+	ChangedBy string    `json:"changed_by" yaml:"changed_by"`
+	ChangedAt time.Time `json:"changed_at" yaml:"changed_at"`
+	Type      string    `json:"type" yaml:"type"`
+	Title     string    `json:"title" yaml:"title"`
+}
+
+// revisionList - история изменений записи от новых ревизий к старым.
+type revisionList []revisionView
+
+// table - таблица ревизий без данных записи.
+func (l revisionList) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REVISION\tCHANGED\tBY\tTYPE\tTITLE")
+	for _, v := range l {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", v.ID, v.ChangedAt.Local().Format(time.DateTime),
+			v.ChangedBy, v.Type, v.Title)
+	}
+	return tw.Flush()
+}
+
+// env - переменные GK_COUNT и GK_<N>_<ПОЛЕ> для каждой ревизии.
+func (l revisionList) env() []pair {
+	pairs := []pair{{"GK_COUNT", strconv.Itoa(len(l))}}
+	for i, v := range l {
+		pairs = append(pairs, prefixed(fmt.Sprintf("GK_%d_", i), []pair{
+			{"id", strconv.FormatInt(v.ID, 10)},
+			{"changed_by", v.ChangedBy},
+			{"changed_at", v.ChangedAt.Format(time.RFC3339)},
+			{"type", v.Type},
+			{"title", v.Title},
+		})...)
+	}
+	return pairs
+}
+
+// history - выводит историю изменений записи на сервере, одну ревизию (-show)
+// или возвращает запись к ревизии (-restore).
+func (c *Commands) history(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("history")
+	out := newOutputFlags(fs, true)
+	show := fs.Int64("show", 0, "print the item as it was in the revision")
+	restore := fs.Int64("restore", 0, "restore the item to the revision")
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+	if *show != 0 && *restore != 0 {
+		return usagef("-show and -restore are mutually exclusive")
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.items.Resolve(ctx, token, ref)
+	if err != nil {
+		return err
+	}
+
+	if *restore != 0 {
+		if _, err := c.vault.RestoreRevision(ctx, c.conn, token, item.ID, *restore); err != nil {
+			return err
+		}
+		return c.render(out, resultView{
+			Status:  "restored",
+			ID:      item.ID,
+			message: fmt.Sprintf("item %d restored to revision %d", item.ID, *restore),
+		})
+	}
+
+	revisions, err := c.vault.Revisions(ctx, c.conn, token, item.ID)
+	if err != nil {
+		return err
+	}
+
+	if *show != 0 {
+		for _, rev := range revisions {
+			if rev.ID != *show {
+				continue
+			}
+			payload, err := models.DecodePayload(rev.Item.Payload)
+			if err != nil {
+				return err
+			}
+			return c.render(out, newItemView(rev.Item, payload, *out.reveal))
+		}
+		return fmt.Errorf("revision %d: %w", *show, errRevisionNotFound)
+	}
+
+	list := make(revisionList, 0, len(revisions))
+	for _, rev := range revisions {
+		list = append(list, revisionView{
+			ID:        rev.ID,
+			ChangedBy: rev.ChangedByLogin,
+			ChangedAt: rev.CreatedAt,
+			Type:      rev.Item.Type.String(),
+			Title:     rev.Item.Title,
+		})
+	}
+	return c.render(out, list)
+}
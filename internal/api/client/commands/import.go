@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"goph-keeper/internal/importer"
+	"goph-keeper/internal/models"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Статусы записей импорта.
+const (
+	importStatusNew       = "new"
+	importStatusImported  = "imported"
+	importStatusDuplicate = "duplicate"
+	importStatusSkipped   = "skipped"
+	importStatusUpdated   = "updated"
+)
+
+// importItems - переносит записи из экспорта другого менеджера паролей. Дубликаты
+// существующих записей и записей того же файла пропускаются, новые записи сохраняются
+// в локальном кэше одной транзакцией и отправляются на сервер синхронизацией.
+// С -seal данные новых записей шифруются на клиенте, как у add -seal.
+func (c *Commands) importItems(ctx context.Context, args []string) error {
+	if len(args) > 0 && isFromBackupFlag(args[0]) {
+		return c.restoreBackup(ctx, args)
+	}
+
+	format, rest, err := positional(args, "format")
+	if err != nil {
+		return err
+	}
+	path, flags, err := positional(rest, "export file")
+	if err != nil && len(rest) > 0 && rest[0] == "-" {
+		path, flags, err = "-", rest[1:], nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("import " + format)
+	mapping := fs.String("map", "", "csv only: field=column pairs, e.g. title=Name,login=Username")
+	tags := fs.String("tags", "", "comma separated tags added to every imported item")
+	dryRun := fs.Bool("dry-run", false, "only show what would be imported")
+	noSync := fs.Bool("no-sync", false, "keep imported items in the local cache until the next sync")
+	seal := newSealFlags(fs)
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, flags); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+	if *mapping != "" && format != "csv" {
+		return usagef("-map is only supported for csv")
+	}
+	if *seal.masterStdin && path == "-" {
+		return usagef("-master-password-stdin cannot be combined with reading the export from stdin")
+	}
+
+	res, err := c.parseExport(format, path, *mapping)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	var vaultKey []byte
+	if *seal.seal && !*dryRun {
+		if vaultKey, err = c.vaultKey(ctx, token, *seal.masterStdin); err != nil {
+			return err
+		}
+		defer clear(vaultKey)
+	}
+
+	seen, err := c.importKeys(ctx, token, vaultKey, *seal.masterStdin)
+	if err != nil {
+		return err
+	}
+
+	view := importView{Format: format, DryRun: *dryRun, Items: []importItemView{}}
+	var items []models.Item
+	for _, r := range res.Records {
+		r.Item.Tags = append(r.Item.Tags, splitTags(*tags)...)
+
+		key := importer.Key(r.Item, r.Payload)
+		if seen[key] {
+			view.Duplicates++
+			view.Items = append(view.Items, newImportItemView(r.Item, importStatusDuplicate))
+			continue
+		}
+		seen[key] = true
+
+		if vaultKey != nil {
+			r.Item.Type = r.Payload.Type()
+			r.Item.BlindIndex = blindIndex(vaultKey, r.Payload)
+			if r.Payload, err = sealPayload(vaultKey, r.Payload, nil); err != nil {
+				return err
+			}
+		}
+		r.Item.Payload, err = models.EncodePayload(r.Payload)
+		if err != nil {
+			return err
+		}
+		items = append(items, r.Item)
+		view.Items = append(view.Items, newImportItemView(r.Item, importStatusNew))
+	}
+	for _, s := range res.Skipped {
+		view.Skipped++
+		view.Items = append(view.Items, importItemView{Title: s.Title, Status: importStatusSkipped, Reason: s.Reason})
+	}
+
+	if *dryRun || len(items) == 0 {
+		return c.render(out, view)
+	}
+
+	created, err := c.items.CreateItems(ctx, token, items)
+	if err != nil {
+		return err
+	}
+	view.Imported = len(created)
+	for i := range view.Items {
+		if view.Items[i].Status == importStatusNew {
+			view.Items[i].Status = importStatusImported
+		}
+	}
+	c.log.Info("items imported", "format", format, "imported", view.Imported,
+		"duplicates", view.Duplicates, "skipped", view.Skipped)
+
+	return c.syncImported(ctx, token, out, view, *noSync)
+}
+
+// importKeys - ключи существующих записей для поиска дубликатов. Зашифрованные на клиенте
+// записи читаются через агента, который их расшифровывает, а без него открываются ключом
+// хранилища vaultKey; если его нет, спрашивается мастер-пароль. Без этого дубликаты
+// зашифрованных записей не нашлись бы и повторный импорт копировал бы их.
+func (c *Commands) importKeys(ctx context.Context, token string, vaultKey []byte, masterStdin bool) (map[string]bool, error) {
+	seen := make(map[string]bool)
+	var sealed []models.Item
+	err := c.eachLocalItem(ctx, token, models.ItemTypeUnspecified, models.DefaultSort, func(item models.Item, payload models.Payload) error {
+		if payload.Sealed != nil {
+			sealed = append(sealed, item)
+			return nil
+		}
+		seen[importer.Key(item, payload)] = true
+		return nil
+	})
+	if err != nil || len(sealed) == 0 {
+		return seen, err
+	}
+
+	if vaultKey == nil && c.agentRunning(ctx) {
+		err := c.eachItem(ctx, token, models.ItemTypeUnspecified, models.DefaultSort, func(item models.Item, payload models.Payload) error {
+			if key := importer.Key(item, payload); key != "" {
+				seen[key] = true
+			}
+			return nil
+		})
+		return seen, err
+	}
+
+	if vaultKey == nil {
+		if vaultKey, err = c.vaultKey(ctx, token, masterStdin); err != nil {
+			return nil, err
+		}
+		defer clear(vaultKey)
+	}
+	for _, item := range sealed {
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil {
+			return nil, err
+		}
+		if payload, err = openPayload(vaultKey, *payload.Sealed); err != nil {
+			return nil, err
+		}
+		seen[importer.Key(item, payload)] = true
+	}
+	return seen, nil
+}
+
+// syncImported - отправляет импортированные записи на сервер и выводит итог. Если сервер
+// недоступен, записи остаются в локальном кэше до следующей синхронизации.
+func (c *Commands) syncImported(ctx context.Context, token string, out *outputFlags, view importView, noSync bool) error {
+	if noSync {
+		return c.render(out, view)
+	}
+
+	sync, syncErr := c.vault.Sync(ctx, c.conn, token)
+	if syncErr == nil {
+		view.Sync = &syncView{
+			Created: sync.Created,
+			Updated: sync.Updated,
+			Deleted: sync.Deleted,
+			Pulled:  sync.Pulled,
+			Removed: sync.Removed,
+		}
+	}
+	if err := c.render(out, view); err != nil {
+		return err
+	}
+	if syncErr != nil {
+		return fmt.Errorf("items are saved locally, run sync later: %w", syncErr)
+	}
+	return nil
+}
+
+// parseExport - разбирает файл экспорта, "-" - stdin.
+func (c *Commands) parseExport(format, path, mapping string) (importer.Result, error) {
+	var r io.Reader = c.in
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return importer.Result{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	switch format {
+	case "keepass":
+		return importer.KeePassXML(r)
+	case "bitwarden":
+		return importer.BitwardenJSON(r)
+	case "csv":
+		m, err := importer.ParseMapping(mapping)
+		if err != nil {
+			return importer.Result{}, usagef("%s", err)
+		}
+		return importer.CSV(r, m)
+	default:
+		return importer.Result{}, usagef("unknown import format %q, want keepass, bitwarden or csv", format)
+	}
+}
+
+// importView - итог импорта. Секреты записей не выводятся.
+type importView struct {
+	Format     string           `json:"format" yaml:"format"`
+	DryRun     bool             `json:"dry_run" yaml:"dry_run"`
+	Imported   int              `json:"imported" yaml:"imported"`
+	Duplicates int              `json:"duplicates" yaml:"duplicates"`
+	Skipped    int              `json:"skipped" yaml:"skipped"`
+	Updated    int              `json:"updated,omitempty" yaml:"updated,omitempty"`
+	Items      []importItemView `json:"items" yaml:"items"`
+	Sync       *syncView        `json:"sync,omitempty" yaml:"sync,omitempty"`
+}
+
+// importItemView - запись экспорта и что с ней сделано.
+type importItemView struct {
+	Title  string `json:"title" yaml:"title"`
+	Type   string `json:"type,omitempty" yaml:"type,omitempty"`
+	Status string `json:"status" yaml:"status"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// newImportItemView - представление записи импорта.
+func newImportItemView(item models.Item, status string) importItemView {
+	return importItemView{Title: item.Title, Type: item.Type.String(), Status: status}
+}
+
+// table - при -dry-run все записи, иначе только пропущенные, и итог.
+func (v importView) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := false
+	for _, item := range v.Items {
+		if !v.DryRun && (item.Status == importStatusImported || item.Status == importStatusUpdated) {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(tw, "STATUS\tTYPE\tTITLE\tREASON")
+			header = true
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", item.Status, item.Type, item.Title, item.Reason)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("imported: %d, duplicates: %d, skipped: %d", v.Imported, v.Duplicates, v.Skipped)
+	if v.Updated > 0 {
+		summary += fmt.Sprintf(", updated: %d", v.Updated)
+	}
+	if v.DryRun {
+		summary = fmt.Sprintf("dry run: %d new, %d duplicates, %d skipped", v.count(importStatusNew), v.Duplicates, v.Skipped)
+	}
+	if _, err := fmt.Fprintln(w, summary); err != nil {
+		return err
+	}
+	if v.Sync != nil {
+		return v.Sync.table(w)
+	}
+	return nil
+}
+
+// count - число записей с указанным статусом.
+func (v importView) count(status string) int {
+	n := 0
+	for _, item := range v.Items {
+		if item.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// env - счетчики импорта как переменные GK_<СЧЕТЧИК>.
+func (v importView) env() []pair {
+	return []pair{
+		{"GK_IMPORTED", strconv.Itoa(v.Imported)},
+		{"GK_DUPLICATES", strconv.Itoa(v.Duplicates)},
+		{"GK_SKIPPED", strconv.Itoa(v.Skipped)},
+		{"GK_UPDATED", strconv.Itoa(v.Updated)},
+		{"GK_DRY_RUN", strconv.FormatBool(v.DryRun)},
+	}
+}
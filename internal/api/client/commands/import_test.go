@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/vaultcrypto"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportSealedVault(t *testing.T) {
+	ctx := context.Background()
+	auth := &fakeAuth{login: "alice", token: "tok"}
+	items := &memItems{}
+
+	run := func(stdin string, args ...string) int {
+		c, _ := newTestCommands(auth, stdin)
+		c.items = items
+		c.list = memList{items: items}
+		c.vault = fakeKeys{key: vaultcrypto.DeriveKey("alice", "master")}
+		return c.Run(ctx, args)
+	}
+
+	if code := run("master\n", "add", "login", "-title", "gh", "-resource", "github.com", "-login", "alice",
+		"-password", "s3cret", "-seal", "-master-password-stdin"); code != ExitOK {
+		t.Fatalf("add -seal: exit %d", code)
+	}
+
+	path := filepath.Join(t.TempDir(), "export.csv")
+	csv := "name,url,username,password\ngh,github.com,alice,s3cret\nmail,mail.example.com,alice,mail-pa55\n"
+	if err := os.WriteFile(path, []byte(csv), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := run("wrong\n", "import", "csv", path, "-master-password-stdin", "-no-sync"); code != ExitUnauthenticated {
+		t.Errorf("import with a wrong master password: exit %d, want %d", code, ExitUnauthenticated)
+	}
+
+	// зашифрованная запись gh - дубликат, mail сохраняется зашифрованной
+	if code := run("master\n", "import", "csv", path, "-seal", "-master-password-stdin", "-no-sync"); code != ExitOK {
+		t.Fatalf("import -seal: exit %d", code)
+	}
+	if len(items.items) != 2 {
+		t.Fatalf("expected the sealed duplicate skipped, got %d items", len(items.items))
+	}
+	mail := items.items[1]
+	payload, err := models.DecodePayload(mail.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Sealed == nil || mail.Type != models.ItemTypeLogin || len(mail.BlindIndex) == 0 ||
+		bytes.Contains(mail.Payload, []byte("mail-pa55")) {
+		t.Fatalf("imported item = %+v, %s", mail, mail.Payload)
+	}
+
+	// повторный импорт находит обе зашифрованные записи
+	if code := run("master\n", "import", "csv", path, "-master-password-stdin", "-no-sync"); code != ExitOK {
+		t.Fatalf("re-import: exit %d", code)
+	}
+	if len(items.items) != 2 {
+		t.Errorf("re-import duplicated sealed items: got %d items", len(items.items))
+	}
+}
@@ -0,0 +1,663 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
+	"goph-keeper/internal/sshkey"
+	"goph-keeper/internal/totp"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// errSealedItem - запись зашифрована на клиенте, ее данные нельзя изменить командой.
+var errSealedItem = errors.New("item is encrypted on the client and cannot be changed here")
+
+// listItems - выводит все записи пользователя таблицей.
+func (c *Commands) listItems(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("list")
+	typeName := fs.String("type", "", "item type: login, note, binary, card, ssh or totp")
+	sortName := fs.String("sort", "updated", "sort order: updated or title")
+	folderPath := fs.String("folder", "", "only items of this folder, / - items outside folders")
+	out := newOutputFlags(fs, true)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	itemType, err := parseType(*typeName)
+	if err != nil {
+		return err
+	}
+	sort, err := parseSort(*sortName)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	folderID := models.FolderAny
+	if *folderPath != "" {
+		folders, err := c.list.Folders(ctx, token)
+		if err != nil {
+			return err
+		}
+		f, err := findFolder(folders, *folderPath)
+		if err != nil {
+			return err
+		}
+		folderID = f.ID
+	}
+
+	list := itemList{}
+	err = c.eachItem(ctx, token, itemType, sort, func(item models.Item, payload models.Payload) error {
+		if folderID != models.FolderAny && item.FolderID != folderID {
+			return nil
+		}
+		list = append(list, newItemView(item, payload, *out.reveal))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.render(out, list)
+}
+
+// eachItem - вызывает fn для всех записей пользователя. Если запущен агент, записи
+// читаются через него (зашифрованные - уже расшифрованными), иначе из локального кэша.
+// Для изменения записей нужен eachLocalItem: расшифрованные данные нельзя сохранять обратно.
+func (c *Commands) eachItem(ctx context.Context, token string, itemType models.ItemType, sort models.Sort,
+	fn func(item models.Item, payload models.Payload) error) error {
+	if !c.agentRunning(ctx) {
+		return c.eachLocalItem(ctx, token, itemType, sort, fn)
+	}
+
+	items, err := c.agent.List(ctx, itemType)
+	if err != nil {
+		return err
+	}
+	sortItems(items, sort)
+	for _, item := range items {
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil {
+			return err
+		}
+		if err := fn(item, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachLocalItem - вызывает fn для всех записей локального кэша, читая список постранично.
+func (c *Commands) eachLocalItem(ctx context.Context, token string, itemType models.ItemType, sort models.Sort,
+	fn func(item models.Item, payload models.Payload) error) error {
+	req := models.PageRequest{Size: pagination.MaxPageSize, Sort: sort}
+	for {
+		items, next, err := c.list.GetPage(ctx, token, itemType, models.FolderAny, req)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			payload, err := models.DecodePayload(item.Payload)
+			if err != nil {
+				return err
+			}
+			if err := fn(item, payload); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		req.Token = next
+	}
+}
+
+// get - выводит запись с замаскированными секретами или одно поле как есть.
+func (c *Commands) get(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("get")
+	fieldName := fs.String("field", "", "print only this field, unmasked")
+	out := newOutputFlags(fs, true)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	item, payload, err := c.resolve(ctx, ref, false)
+	if err != nil {
+		return err
+	}
+
+	if *fieldName != "" {
+		f, ok := lookupField(itemFields(item, payload), *fieldName)
+		if !ok {
+			return usagef("item %q has no field %q", item.Title, *fieldName)
+		}
+		if f.name == "data" {
+			_, err = c.out.Write([]byte(f.value))
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, f.value)
+		return err
+	}
+
+	return c.render(out, newItemView(item, payload, *out.reveal))
+}
+
+// add - создает запись указанного типа и выводит ее id.
+func (c *Commands) add(ctx context.Context, args []string) error {
+	typeName, rest, err := positional(args, "item type")
+	if err != nil {
+		return err
+	}
+	itemType, err := parseType(typeName)
+	if err != nil {
+		return err
+	}
+	if itemType == models.ItemTypeBinary {
+		// путь к файлу идет сразу после типа
+		var path string
+		if path, rest, err = positional(rest, "file path"); err != nil {
+			return err
+		}
+		rest = append([]string{"-file", path}, rest...)
+	}
+	if itemType == models.ItemTypeSSHKey {
+		// путь к закрытому ключу идет сразу после типа
+		var path string
+		if path, rest, err = positional(rest, "private key path"); err != nil {
+			return err
+		}
+		rest = append([]string{"-key-file", path}, rest...)
+	}
+
+	fs := c.newFlagSet("add " + typeName)
+	f := newItemFlags(fs, itemType)
+	seal := newSealFlags(fs)
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+	if *seal.masterStdin && !*seal.seal {
+		return usagef("-master-password-stdin requires -seal")
+	}
+	if err := seal.validate(f); err != nil {
+		return err
+	}
+
+	var item models.Item
+	var payload models.Payload
+	if err := f.apply(c, &item, &payload, nil); err != nil {
+		return err
+	}
+	if item.Title == "" {
+		item.Title = defaultTitle(payload)
+	}
+	if item.Title == "" {
+		return usagef("-title is required")
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *seal.seal {
+		key, err := c.vaultKey(ctx, token, *seal.masterStdin)
+		if err != nil {
+			return err
+		}
+		defer clear(key)
+
+		item.Type = payload.Type()
+		item.BlindIndex = blindIndex(key, payload)
+		if payload, err = sealPayload(key, payload, nil); err != nil {
+			return err
+		}
+	}
+
+	created, err := c.items.CreateItem(ctx, token, item, payload)
+	if err != nil {
+		return err
+	}
+
+	return c.render(out, resultView{
+		Status:  "created",
+		ID:      created.ID,
+		message: strconv.FormatInt(created.ID, 10),
+	})
+}
+
+// edit - меняет только переданные флагами поля записи. Запись, зашифрованная на клиенте,
+// расшифровывается ключом хранилища и после изменения шифруется тем же ключом записи;
+// -seal шифрует данные незашифрованной записи.
+func (c *Commands) edit(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+
+	item, payload, err := c.resolve(ctx, ref, true)
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("edit")
+	f := newItemFlags(fs, item.Type)
+	seal := newSealFlags(fs)
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+	if err := seal.validate(f); err != nil {
+		return err
+	}
+
+	visited := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) {
+		if !isOutputFlag(fl.Name) && fl.Name != "master-password-stdin" {
+			visited[fl.Name] = true
+		}
+	})
+	if len(visited) == 0 {
+		return usagef("nothing to change")
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	sealed := payload.Sealed
+	var key []byte
+	if sealed != nil || *seal.seal {
+		if key, err = c.vaultKey(ctx, token, *seal.masterStdin); err != nil {
+			return err
+		}
+		defer clear(key)
+	}
+	if sealed != nil {
+		if payload, err = openPayload(key, *sealed); err != nil {
+			return err
+		}
+	}
+
+	if err := f.apply(c, &item, &payload, visited); err != nil {
+		return err
+	}
+
+	if key != nil {
+		var wrappedKey []byte
+		if sealed != nil {
+			wrappedKey = sealed.WrappedKey
+		}
+		item.BlindIndex = blindIndex(key, payload)
+		if payload, err = sealPayload(key, payload, wrappedKey); err != nil {
+			return err
+		}
+	}
+
+	if _, err = c.items.UpdateItem(ctx, token, item, payload); err != nil {
+		return err
+	}
+	return c.render(out, resultView{Status: "updated", ID: item.ID})
+}
+
+// remove - удаляет запись.
+func (c *Commands) remove(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+	fs := c.newFlagSet("rm")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	item, _, err := c.resolve(ctx, ref, true)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.items.DeleteItem(ctx, token, item.ID); err != nil {
+		return err
+	}
+	return c.render(out, resultView{Status: "deleted", ID: item.ID})
+}
+
+// read - находит запись для чтения: через агент, если он запущен, иначе в локальном кэше.
+func (c *Commands) read(ctx context.Context, token, ref string) (models.Item, error) {
+	if c.agentRunning(ctx) {
+		return c.agent.Get(ctx, ref)
+	}
+	return c.items.Resolve(ctx, token, ref)
+}
+
+// resolve - находит запись по id или названию и разбирает ее данные. При forUpdate запись
+// всегда берется из локального кэша, данные зашифрованной записи остаются зашифрованными.
+func (c *Commands) resolve(ctx context.Context, ref string, forUpdate bool) (models.Item, models.Payload, error) {
+	token, err := c.token(ctx)
+	if err != nil {
+		return models.Item{}, models.Payload{}, err
+	}
+
+	var item models.Item
+	if forUpdate {
+		item, err = c.items.Resolve(ctx, token, ref)
+	} else {
+		item, err = c.read(ctx, token, ref)
+	}
+	if err != nil {
+		return models.Item{}, models.Payload{}, err
+	}
+
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return models.Item{}, models.Payload{}, err
+	}
+	return item, payload, nil
+}
+
+// sortItems - упорядочивает записи, полученные от агента, как это делает список кэша.
+func sortItems(items []models.Item, sort models.Sort) {
+	less := func(a, b models.Item) int {
+		if sort.Field == models.SortByTitle {
+			return cmp.Or(strings.Compare(a.Title, b.Title), cmp.Compare(a.ID, b.ID))
+		}
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+	}
+	slices.SortStableFunc(items, func(a, b models.Item) int {
+		if sort.Descending {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+// defaultTitle - название записи, если оно не задано флагом.
+func defaultTitle(payload models.Payload) string {
+	switch {
+	case payload.Login != nil:
+		return payload.Login.Resource
+	case payload.Binary != nil:
+		return payload.Binary.Name
+	case payload.SSHKey != nil:
+		return payload.SSHKey.Comment
+	case payload.TOTP != nil:
+		return payload.TOTP.Issuer
+	default:
+		return ""
+	}
+}
+
+// itemFlags - флаги метаданных и данных записи одного типа.
+type itemFlags struct {
+	itemType models.ItemType
+
+	title    *string
+	tags     *string
+	favorite *bool
+
+	resource, login, password *string
+	text                      *string
+	file, name                *string
+	number, holder, expiry    *string
+	cvv                       *string
+	keyFile, comment          *string
+	secret, issuer, account   *string
+
+	secretStdin *bool
+}
+
+// sealFlags - шифрование данных записи на клиенте.
+type sealFlags struct {
+	seal        *bool
+	masterStdin *bool
+}
+
+// newSealFlags - регистрирует флаги шифрования записи на клиенте.
+func newSealFlags(fs *flag.FlagSet) *sealFlags {
+	return &sealFlags{
+		seal:        fs.Bool("seal", false, "encrypt the item data on the client, the server sees only the title and tags"),
+		masterStdin: fs.Bool("master-password-stdin", false, "read the master password from stdin"),
+	}
+}
+
+// validate - из stdin читается только одно значение: мастер-пароль или секрет записи.
+func (s *sealFlags) validate(f *itemFlags) error {
+	if *s.masterStdin && f.secretStdin != nil && *f.secretStdin {
+		return usagef("-master-password-stdin cannot be combined with another -*-stdin flag")
+	}
+	return nil
+}
+
+// newItemFlags - регистрирует флаги записи указанного типа.
+func newItemFlags(fs *flag.FlagSet, itemType models.ItemType) *itemFlags {
+	f := &itemFlags{
+		itemType: itemType,
+		title:    fs.String("title", "", "item title"),
+		tags:     fs.String("tags", "", "comma separated tags"),
+		favorite: fs.Bool("favorite", false, "mark the item as favorite"),
+	}
+
+	switch itemType {
+	case models.ItemTypeLogin:
+		f.resource = fs.String("resource", "", "resource, e.g. site URL")
+		f.login = fs.String("login", "", "login")
+		f.password = fs.String("password", "", "password, prefer -password-stdin")
+		f.secretStdin = fs.Bool("password-stdin", false, "read the password from stdin")
+	case models.ItemTypeNote:
+		f.text = fs.String("text", "", "note text")
+		f.secretStdin = fs.Bool("text-stdin", false, "read the note text from stdin")
+	case models.ItemTypeBinary:
+		f.file = fs.String("file", "", "path to the file")
+		f.name = fs.String("name", "", "file name, defaults to the base name of the path")
+	case models.ItemTypeCard:
+		f.number = fs.String("number", "", "card number")
+		f.holder = fs.String("holder", "", "card holder")
+		f.expiry = fs.String("expiry", "", "expiry date, MM/YY")
+		f.cvv = fs.String("cvv", "", "CVV, prefer -cvv-stdin")
+		f.secretStdin = fs.Bool("cvv-stdin", false, "read the CVV from stdin")
+	case models.ItemTypeSSHKey:
+		f.keyFile = fs.String("key-file", "", "path to the private key")
+		f.comment = fs.String("comment", "", "key comment, defaults to the comment of the .pub file next to the key")
+		f.secretStdin = fs.Bool("passphrase-stdin", false, "read the key passphrase from stdin")
+	case models.ItemTypeTOTP:
+		f.secret = fs.String("secret", "", "otpauth:// URI or base32 secret, prefer -secret-stdin")
+		f.issuer = fs.String("issuer", "", "issuer, defaults to the one from the URI")
+		f.account = fs.String("account", "", "account, defaults to the one from the URI")
+		f.secretStdin = fs.Bool("secret-stdin", false, "read the URI or secret from stdin")
+	}
+	return f
+}
+
+// apply - переносит значения флагов в запись. При visited == nil применяются все флаги
+// (новая запись), иначе только явно переданные.
+func (f *itemFlags) apply(c *Commands, item *models.Item, payload *models.Payload, visited map[string]bool) error {
+	set := func(name string) bool {
+		return visited == nil || visited[name]
+	}
+
+	if set("title") {
+		item.Title = *f.title
+	}
+	if set("tags") {
+		item.Tags = splitTags(*f.tags)
+	}
+	if set("favorite") {
+		item.Favorite = *f.favorite
+	}
+
+	switch f.itemType {
+	case models.ItemTypeLogin:
+		if payload.Login == nil {
+			payload.Login = &models.LoginPayload{}
+		}
+		if set("resource") {
+			payload.Login.Resource = *f.resource
+		}
+		if set("login") {
+			payload.Login.Login = *f.login
+		}
+		if visited == nil || visited["password"] || visited["password-stdin"] {
+			password, err := c.readSecret("password", *f.password, *f.secretStdin)
+			if err != nil {
+				return err
+			}
+			payload.Login.Password = password
+		}
+	case models.ItemTypeNote:
+		if payload.Note == nil {
+			payload.Note = &models.NotePayload{}
+		}
+		if visited == nil || visited["text"] || visited["text-stdin"] {
+			text, err := c.readSecret("text", *f.text, *f.secretStdin)
+			if err != nil {
+				return err
+			}
+			payload.Note.Text = text
+		}
+	case models.ItemTypeBinary:
+		if payload.Binary == nil {
+			payload.Binary = &models.BinaryPayload{}
+		}
+		if set("file") {
+			data, err := os.ReadFile(*f.file)
+			if err != nil {
+				return err
+			}
+			payload.Binary.Data = data
+			payload.Binary.Name = filepath.Base(*f.file)
+		}
+		if set("name") && *f.name != "" {
+			payload.Binary.Name = *f.name
+		}
+	case models.ItemTypeCard:
+		if payload.Card == nil {
+			payload.Card = &models.CardPayload{}
+		}
+		if set("number") {
+			payload.Card.Number = *f.number
+		}
+		if set("holder") {
+			payload.Card.Holder = *f.holder
+		}
+		if set("expiry") {
+			payload.Card.Expiry = *f.expiry
+		}
+		if visited == nil || visited["cvv"] || visited["cvv-stdin"] {
+			cvv, err := c.readSecret("cvv", *f.cvv, *f.secretStdin)
+			if err != nil {
+				return err
+			}
+			payload.Card.CVV = cvv
+		}
+	case models.ItemTypeSSHKey:
+		if payload.SSHKey == nil {
+			payload.SSHKey = &models.SSHKeyPayload{}
+		}
+		if set("key-file") {
+			key, err := c.readSSHKey(*f.keyFile, *f.secretStdin)
+			if err != nil {
+				return err
+			}
+			*payload.SSHKey = key
+		}
+		if set("comment") && (*f.comment != "" || visited != nil) {
+			// комментарий хранится и в закрытом, и в открытом ключе
+			key, err := sshkey.FromPrivateKey([]byte(payload.SSHKey.PrivateKey), nil, *f.comment)
+			if err != nil {
+				return err
+			}
+			*payload.SSHKey = key
+		}
+	case models.ItemTypeTOTP:
+		if payload.TOTP == nil {
+			payload.TOTP = &models.TOTPPayload{}
+		}
+		if visited == nil || visited["secret"] || visited["secret-stdin"] {
+			secret, err := c.readSecret("secret", *f.secret, *f.secretStdin)
+			if err != nil {
+				return err
+			}
+			parsed, err := totp.Parse(secret)
+			if err != nil {
+				return usagef("%s", err)
+			}
+			*payload.TOTP = parsed
+		}
+		if set("issuer") && (*f.issuer != "" || visited != nil) {
+			payload.TOTP.Issuer = *f.issuer
+		}
+		if set("account") && (*f.account != "" || visited != nil) {
+			payload.TOTP.Account = *f.account
+		}
+	}
+	return nil
+}
+
+// readSSHKey - закрытый ключ из файла. Комментарий берется из файла .pub рядом с ключом,
+// парольная фраза запрашивается, только если ключ ею защищен.
+func (c *Commands) readSSHKey(path string, passphraseStdin bool) (models.SSHKeyPayload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.SSHKeyPayload{}, err
+	}
+
+	var comment string
+	if pub, err := os.ReadFile(path + ".pub"); err == nil {
+		comment = sshkey.CommentFromPublicKey(pub)
+	}
+
+	var passphrase string
+	if passphraseStdin {
+		if passphrase, err = c.readStdin(); err != nil {
+			return models.SSHKeyPayload{}, err
+		}
+	}
+
+	key, err := sshkey.FromPrivateKey(data, []byte(passphrase), comment)
+	if errors.Is(err, sshkey.ErrPassphraseRequired) && !passphraseStdin {
+		if passphrase, err = c.readSecret("passphrase", "", false); err != nil {
+			return models.SSHKeyPayload{}, err
+		}
+		key, err = sshkey.FromPrivateKey(data, []byte(passphrase), comment)
+	}
+	return key, err
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"goph-keeper/internal/totp"
+	"time"
+)
+
+// otp - выводит текущий код записи totp.
+func (c *Commands) otp(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("otp")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	item, payload, err := c.resolve(ctx, ref, false)
+	if err != nil {
+		return err
+	}
+	if payload.TOTP == nil {
+		return usagef("item %q has no totp secret", item.Title)
+	}
+
+	now := time.Now()
+	code, err := totp.Code(*payload.TOTP, now)
+	if err != nil {
+		return err
+	}
+	remaining := totp.Remaining(*payload.TOTP, now)
+
+	return c.render(out, otpView{
+		Code:      code,
+		Remaining: int(remaining / time.Second),
+		ExpiresAt: now.Add(remaining).UTC().Truncate(time.Second),
+	})
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+)
+
+// Форматы вывода команд.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputEnv   = "env"
+)
+
+// pair - именованное значение для табличного вывода и переменных окружения.
+type pair struct {
+	key   string
+	value string
+}
+
+// view - результат команды, который умеет выводиться таблицей и переменными окружения.
+// JSON и YAML строятся по тегам структуры.
+type view interface {
+	table(w io.Writer) error
+	env() []pair
+}
+
+// outputFlags - флаги формата вывода, общие для всех команд.
+type outputFlags struct {
+	format *string
+	reveal *bool
+}
+
+// newOutputFlags - регистрирует -output. withReveal добавляет -reveal для команд, выводящих секреты.
+func newOutputFlags(fs *flag.FlagSet, withReveal bool) *outputFlags {
+	o := &outputFlags{
+		format: fs.String("output", OutputTable, "output format: table, json, yaml or env"),
+		reveal: new(bool),
+	}
+	if withReveal {
+		o.reveal = fs.Bool("reveal", false, "print secret fields unmasked")
+	}
+	return o
+}
+
+// validate - проверяет формат вывода.
+func (o *outputFlags) validate() error {
+	switch *o.format {
+	case OutputTable, OutputJSON, OutputYAML, OutputEnv:
+		return nil
+	default:
+		return usagef("unknown output format %q", *o.format)
+	}
+}
+
+// isOutputFlag - флаг формата вывода, а не поля записи.
+func isOutputFlag(name string) bool {
+	return name == "output" || name == "reveal"
+}
+
+// render - выводит результат команды в выбранном формате.
+func (c *Commands) render(o *outputFlags, v view) error {
+	switch *o.format {
+	case OutputJSON:
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case OutputYAML:
+		enc := yaml.NewEncoder(c.out)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case OutputEnv:
+		for _, p := range v.env() {
+			if _, err := fmt.Fprintf(c.out, "%s=%s\n", p.key, shellQuote(p.value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return v.table(c.out)
+	}
+}
+
+// shellQuote - значение в одинарных кавычках, пригодное для eval в POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
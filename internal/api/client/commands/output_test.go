@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"goph-keeper/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testItem() (models.Item, models.Payload) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	item := models.Item{ID: 7, Type: models.ItemTypeLogin, Title: "github", CreatedAt: at, UpdatedAt: at}
+	payload := models.Payload{Login: &models.LoginPayload{Resource: "github.com", Login: "alice", Password: "it's-secret"}}
+	return item, payload
+}
+
+func render(t *testing.T, format string, v view) string {
+	t.Helper()
+	var buf bytes.Buffer
+	c := &Commands{out: &buf}
+	if err := c.render(&outputFlags{format: &format}, v); err != nil {
+		t.Fatalf("render %s: %v", format, err)
+	}
+	return buf.String()
+}
+
+func TestRenderMasksSecrets(t *testing.T) {
+	item, payload := testItem()
+	v := newItemView(item, payload, false)
+
+	for _, format := range []string{OutputTable, OutputJSON, OutputYAML, OutputEnv} {
+		if out := render(t, format, v); strings.Contains(out, "secret") {
+			t.Errorf("%s output contains the password: %s", format, out)
+		}
+	}
+
+	revealed := render(t, OutputJSON, newItemView(item, payload, true))
+	if !strings.Contains(revealed, `"password": "it's-secret"`) {
+		t.Errorf("-reveal must print the password: %s", revealed)
+	}
+}
+
+func TestRenderSchema(t *testing.T) {
+	item, payload := testItem()
+	v := newItemView(item, payload, true)
+
+	json := render(t, OutputJSON, itemList{v})
+	for _, want := range []string{`"id": 7`, `"type": "login"`, `"tags": []`, `"created_at": "2024-05-01T10:00:00Z"`, `"login": {`} {
+		if !strings.Contains(json, want) {
+			t.Errorf("json output has no %s: %s", want, json)
+		}
+	}
+
+	yaml := render(t, OutputYAML, v)
+	if !strings.Contains(yaml, "login:\n  resource: github.com\n") {
+		t.Errorf("unexpected yaml output: %s", yaml)
+	}
+
+	env := render(t, OutputEnv, itemList{v})
+	for _, want := range []string{"GK_COUNT='1'\n", "GK_0_TITLE='github'\n", `GK_0_LOGIN_PASSWORD='it'\''s-secret'`} {
+		if !strings.Contains(env, want) {
+			t.Errorf("env output has no %s: %s", want, env)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"goph-keeper/internal/agent"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+)
+
+// refPrefix - префикс ссылки на запись хранилища в значении -env.
+const refPrefix = "item:"
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretRef - переменная окружения и ссылка на поле записи.
+type secretRef struct {
+	name  string
+	ref   string
+	field string
+}
+
+// envRefs - значения повторяемого флага -env.
+type envRefs []secretRef
+
+func (e *envRefs) String() string {
+	names := make([]string, 0, len(*e))
+	for _, r := range *e {
+		names = append(names, r.name)
+	}
+	return strings.Join(names, ",")
+}
+
+// Set - разбирает NAME=item:<id|title>[#field].
+func (e *envRefs) Set(value string) error {
+	name, ref, ok := strings.Cut(value, "=")
+	if !ok || !envName.MatchString(name) {
+		return fmt.Errorf("expected NAME=item:<id|title>[#field], got %q", value)
+	}
+	if !strings.HasPrefix(ref, refPrefix) {
+		return fmt.Errorf("%s: reference must start with %q", name, refPrefix)
+	}
+	ref = strings.TrimPrefix(ref, refPrefix)
+
+	var field string
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		ref, field = ref[:i], ref[i+1:]
+	}
+	if ref == "" {
+		return fmt.Errorf("%s: empty item reference", name)
+	}
+
+	*e = append(*e, secretRef{name: name, ref: ref, field: field})
+	return nil
+}
+
+// runWithSecrets - запускает команду с секретами в переменных окружения. Значения
+// существуют только в памяти клиента и в окружении дочернего процесса.
+func (c *Commands) runWithSecrets(ctx context.Context, args []string) error {
+	var refs envRefs
+	fs := c.newFlagSet("run")
+	fs.Var(&refs, "env", "NAME=item:<id|title>[#field], can be repeated")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+
+	command := fs.Args()
+	if len(command) == 0 {
+		return usagef("command is required after --")
+	}
+	if len(refs) == 0 {
+		return usagef("at least one -env is required")
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	env := os.Environ()
+	for _, r := range refs {
+		value, err := c.secretValue(ctx, token, r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.name, err)
+		}
+		env = append(env, r.name+"="+value)
+	}
+
+	return c.execChild(command, env)
+}
+
+// secretValue - значение поля записи из агента или локального кэша, а если записи там нет - с сервера.
+func (c *Commands) secretValue(ctx context.Context, token string, r secretRef) (string, error) {
+	item, err := c.read(ctx, token, r.ref)
+	if (errors.Is(err, items_client.ErrItemNotFound) || errors.Is(err, agent.ErrItemNotFound)) && c.conn != nil {
+		item, err = c.vault.Find(ctx, c.conn, token, r.ref)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return "", err
+	}
+	if payload.Sealed != nil {
+		return "", errors.New("item is encrypted on the client and cannot be read here")
+	}
+
+	fields := itemFields(item, payload)
+	name := r.field
+	if name == "" {
+		name = defaultField(payload)
+	}
+	f, ok := lookupField(fields, name)
+	if !ok {
+		return "", usagef("item %q has no field %q", item.Title, name)
+	}
+	return f.value, nil
+}
+
+// defaultField - поле, которое подставляется, если в ссылке не указан #field.
+func defaultField(payload models.Payload) string {
+	switch {
+	case payload.Login != nil:
+		return "password"
+	case payload.Note != nil:
+		return "text"
+	case payload.Binary != nil:
+		return "data"
+	case payload.Card != nil:
+		return "number"
+	case payload.SSHKey != nil:
+		return "private_key"
+	case payload.TOTP != nil:
+		return "code"
+	default:
+		return ""
+	}
+}
+
+// execChild - запускает дочерний процесс, пересылает ему сигналы и ждет завершения.
+func (c *Commands) execChild(command, env []string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Stdin = c.in
+	cmd.Stdout = c.out
+	cmd.Stderr = c.errOut
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Ctrl+C терминал отправляет всей группе процессов, поэтому SIGINT клиент только
+	// переживает, а SIGTERM и SIGHUP, адресованные клиенту, передает дочернему процессу.
+	// SIGINT принимается в свой канал и отбрасывается: signal.Ignore снял бы подписки
+	// остальных получателей до конца работы процесса.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	for {
+		select {
+		case <-interrupts:
+		case sig := <-signals:
+			_ = cmd.Process.Signal(sig)
+		case err := <-done:
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code := exitErr.ExitCode()
+				if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+					// процесс завершен сигналом, код как у shell
+					code = 128 + int(status.Signal())
+				}
+				return exitStatusError{code: code}
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"testing"
+)
+
+func TestEnvRefsSet(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    secretRef
+		wantErr bool
+	}{
+		{value: "DB_PASSWORD=item:db", want: secretRef{name: "DB_PASSWORD", ref: "db"}},
+		{value: "_TOKEN=item:12#token", want: secretRef{name: "_TOKEN", ref: "12", field: "token"}},
+		// в названии записи может быть #, полем считается часть после последнего
+		{value: "PW=item:team #1#password", want: secretRef{name: "PW", ref: "team #1", field: "password"}},
+		{value: "URL=item:a=b", want: secretRef{name: "URL", ref: "a=b"}},
+		{value: "1PW=item:db", wantErr: true},
+		{value: "DB-PASSWORD=item:db", wantErr: true},
+		{value: "=item:db", wantErr: true},
+		{value: "DB_PASSWORD", wantErr: true},
+		{value: "DB_PASSWORD=db", wantErr: true},
+		{value: "DB_PASSWORD=item:", wantErr: true},
+		{value: "DB_PASSWORD=item:#password", wantErr: true},
+	}
+	for _, tt := range tests {
+		var refs envRefs
+		err := refs.Set(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q) = %+v, want error", tt.value, refs)
+			}
+			continue
+		}
+		if err != nil || len(refs) != 1 || refs[0] != tt.want {
+			t.Errorf("Set(%q) = %+v, %v, want %+v", tt.value, refs, err, tt.want)
+		}
+	}
+}
+
+// fakeCache - локальный кэш записей по названию.
+type fakeCache struct {
+	itemsService
+	items map[string]models.Item
+}
+
+func (f fakeCache) Resolve(ctx context.Context, token, ref string) (models.Item, error) {
+	item, ok := f.items[ref]
+	if !ok {
+		return models.Item{}, items_client.ErrItemNotFound
+	}
+	return item, nil
+}
+
+// fakeServer - поиск записей на сервере по названию.
+type fakeServer struct {
+	vaultHandlers
+	items map[string]models.Item
+	found []string
+}
+
+func (f *fakeServer) Find(ctx context.Context, conn *grpc.ClientConn, token, title string) (models.Item, error) {
+	f.found = append(f.found, title)
+	item, ok := f.items[title]
+	if !ok {
+		return models.Item{}, status.Error(codes.NotFound, "item not found")
+	}
+	return item, nil
+}
+
+func loginItem(t *testing.T, title, password string) models.Item {
+	t.Helper()
+	payload, err := models.EncodePayload(models.Payload{Login: &models.LoginPayload{Login: "admin", Password: password}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.Item{Type: models.ItemTypeLogin, Title: title, Payload: payload}
+}
+
+func TestSecretValue(t *testing.T) {
+	conn, err := grpc.NewClient("passthrough:///server", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	cache := fakeCache{items: map[string]models.Item{"db": loginItem(t, "db", "cached")}}
+	sealed, err := models.EncodePayload(models.Payload{Sealed: &models.SealedPayload{Ciphertext: []byte{1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		conn      *grpc.ClientConn
+		ref       secretRef
+		want      string
+		wantFound bool
+		wantErr   func(error) bool
+	}{
+		{name: "from cache", conn: conn, ref: secretRef{ref: "db"}, want: "cached"},
+		{name: "cache field", conn: conn, ref: secretRef{ref: "db", field: "login"}, want: "admin"},
+		{name: "server fallback", conn: conn, ref: secretRef{ref: "api"}, want: "remote", wantFound: true},
+		{name: "missing everywhere", conn: conn, ref: secretRef{ref: "nope"}, wantFound: true,
+			wantErr: func(err error) bool { return status.Code(err) == codes.NotFound }},
+		{name: "offline", ref: secretRef{ref: "api"},
+			wantErr: func(err error) bool { return errors.Is(err, items_client.ErrItemNotFound) }},
+		{name: "unknown field", conn: conn, ref: secretRef{ref: "db", field: "cvv"},
+			wantErr: func(err error) bool { return exitCode(err) == ExitUsage }},
+		{name: "sealed", conn: conn, ref: secretRef{ref: "sealed"}, wantFound: true,
+			wantErr: func(err error) bool { return err != nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeServer{items: map[string]models.Item{
+				"api":    loginItem(t, "api", "remote"),
+				"sealed": {Type: models.ItemTypeLogin, Title: "sealed", Payload: sealed},
+			}}
+			c := &Commands{items: cache, vault: server, conn: tt.conn}
+
+			got, err := c.secretValue(context.Background(), "tok", tt.ref)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("secretValue() error = %v", err)
+				}
+			} else if err != nil || got != tt.want {
+				t.Errorf("secretValue() = %q, %v, want %q", got, err, tt.want)
+			}
+			if found := len(server.found) > 0; found != tt.wantFound {
+				t.Errorf("server searched = %v, want %v", found, tt.wantFound)
+			}
+		})
+	}
+}
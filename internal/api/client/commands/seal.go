@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/vaultcrypto"
+	"strings"
+)
+
+// sealPayload - шифрует данные записи ключом хранилища. Внутри sealed лежит
+// сериализованный models.Payload: так его расшифровывают агент и получатели общего
+// доступа. С wrappedKey данные шифруются прежним ключом записи, и выданные копии
+// ключа остаются верными; без него для записи создается новый ключ.
+func sealPayload(vaultKey []byte, payload models.Payload, wrappedKey []byte) (models.Payload, error) {
+	plain, err := models.EncodePayload(payload)
+	if err != nil {
+		return models.Payload{}, err
+	}
+	defer clear(plain)
+
+	var ciphertext []byte
+	if len(wrappedKey) > 0 {
+		ciphertext, err = vaultcrypto.Reseal(vaultKey, wrappedKey, plain)
+	} else {
+		ciphertext, wrappedKey, err = vaultcrypto.Seal(vaultKey, plain)
+	}
+	if err != nil {
+		return models.Payload{}, err
+	}
+
+	return models.Payload{Sealed: &models.SealedPayload{Ciphertext: ciphertext, WrappedKey: wrappedKey}}, nil
+}
+
+// openPayload - расшифровывает данные записи, зашифрованные sealPayload.
+func openPayload(vaultKey []byte, sealed models.SealedPayload) (models.Payload, error) {
+	plain, err := vaultcrypto.Open(vaultKey, sealed.Ciphertext, sealed.WrappedKey)
+	if err != nil {
+		return models.Payload{}, err
+	}
+	defer clear(plain)
+
+	return models.DecodePayload(plain)
+}
+
+// blindIndex - слепые токены поиска по словам несекретных полей записи: ресурса и логина,
+// имени файла, держателя карты, комментария ключа, издателя и аккаунта TOTP. Пароли,
+// тексты заметок, номера карт и ключи в индекс не попадают.
+func blindIndex(vaultKey []byte, payload models.Payload) []string {
+	var words []string
+	switch {
+	case payload.Login != nil:
+		words = append(words, payload.Login.Resource, payload.Login.Login)
+	case payload.Binary != nil:
+		words = append(words, payload.Binary.Name)
+	case payload.Card != nil:
+		words = append(words, payload.Card.Holder)
+	case payload.SSHKey != nil:
+		words = append(words, payload.SSHKey.Comment)
+	case payload.TOTP != nil:
+		words = append(words, payload.TOTP.Issuer, payload.TOTP.Account)
+	}
+	return vaultcrypto.BlindTokens(vaultKey, strings.Join(words, " "))
+}
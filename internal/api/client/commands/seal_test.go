@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"google.golang.org/grpc"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/vaultcrypto"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSealPayload(t *testing.T) {
+	key := vaultcrypto.DeriveKey("alice", "master")
+	payload := models.Payload{Login: &models.LoginPayload{Resource: "github.com", Login: "alice", Password: "s3cret"}}
+
+	sealed, err := sealPayload(key, payload, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed.Sealed == nil || sealed.Type() != models.ItemTypeUnspecified {
+		t.Fatalf("sealPayload() = %+v, want only sealed data", sealed)
+	}
+	encoded, err := models.EncodePayload(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(encoded, []byte("s3cret")) || bytes.Contains(encoded, []byte("github")) {
+		t.Errorf("sealed payload contains plaintext: %s", encoded)
+	}
+
+	opened, err := openPayload(key, *sealed.Sealed)
+	if err != nil || opened.Login == nil || *opened.Login != *payload.Login {
+		t.Fatalf("openPayload() = %+v, %v", opened, err)
+	}
+
+	// повторное шифрование сохраняет ключ записи, выданный получателям
+	payload.Login.Password = "changed"
+	resealed, err := sealPayload(key, payload, sealed.Sealed.WrappedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(resealed.Sealed.WrappedKey, sealed.Sealed.WrappedKey) {
+		t.Errorf("reseal changed the wrapped item key")
+	}
+	if opened, err = openPayload(key, *resealed.Sealed); err != nil || opened.Login.Password != "changed" {
+		t.Errorf("openPayload() after reseal = %+v, %v", opened, err)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	key := vaultcrypto.DeriveKey("alice", "master")
+	index := blindIndex(key, models.Payload{Login: &models.LoginPayload{
+		Resource: "https://github.com", Login: "alice", Password: "hunter2",
+	}})
+
+	for _, word := range []string{"github", "alice", "https"} {
+		if !slices.Contains(index, vaultcrypto.BlindTokens(key, word)[0]) {
+			t.Errorf("blind index has no token for %q", word)
+		}
+	}
+	if slices.Contains(index, vaultcrypto.BlindTokens(key, "hunter2")[0]) {
+		t.Errorf("blind index contains a token of the password")
+	}
+	if len(blindIndex(key, models.Payload{Note: &models.NotePayload{Text: "private words"}})) != 0 {
+		t.Errorf("note text must not be indexed")
+	}
+}
+
+// memItems - кэш записей в памяти.
+type memItems struct {
+	itemsService
+	items []models.Item
+}
+
+func (m *memItems) CreateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error) {
+	if payload.Sealed == nil {
+		item.Type = payload.Type()
+	}
+	var err error
+	if item.Payload, err = models.EncodePayload(payload); err != nil {
+		return models.Item{}, err
+	}
+	var last int64
+	for _, existing := range m.items {
+		last = max(last, existing.ID)
+	}
+	item.ID = last + 1
+	m.items = append(m.items, item)
+	return item, nil
+}
+
+func (m *memItems) CreateItems(ctx context.Context, token string, items []models.Item) ([]models.Item, error) {
+	created := make([]models.Item, 0, len(items))
+	for _, item := range items {
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil {
+			return nil, err
+		}
+		if item, err = m.CreateItem(ctx, token, item, payload); err != nil {
+			return nil, err
+		}
+		created = append(created, item)
+	}
+	return created, nil
+}
+
+func (m *memItems) Resolve(ctx context.Context, token, ref string) (models.Item, error) {
+	for _, item := range m.items {
+		if strconv.FormatInt(item.ID, 10) == ref || item.Title == ref {
+			return item, nil
+		}
+	}
+	return models.Item{}, items_client.ErrItemNotFound
+}
+
+func (m *memItems) UpdateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error) {
+	var err error
+	if item.Payload, err = models.EncodePayload(payload); err != nil {
+		return models.Item{}, err
+	}
+	for i := range m.items {
+		if m.items[i].ID == item.ID {
+			m.items[i] = item
+			return item, nil
+		}
+	}
+	return models.Item{}, items_client.ErrItemNotFound
+}
+
+func (m *memItems) DeleteItem(ctx context.Context, token string, id int64) error {
+	for i := range m.items {
+		if m.items[i].ID == id {
+			m.items = slices.Delete(m.items, i, i+1)
+			return nil
+		}
+	}
+	return items_client.ErrItemNotFound
+}
+
+// memServer - поиск по слепому индексу записей кэша, как его выполняет сервер.
+type memServer struct {
+	fakeKeys
+	items *memItems
+	sent  []string
+}
+
+func (s *memServer) SearchSealed(ctx context.Context, conn *grpc.ClientConn, token string, tokens []string) ([]models.Item, error) {
+	s.sent = append(s.sent, tokens...)
+	var found []models.Item
+	for _, item := range s.items.items {
+		if !slices.ContainsFunc(tokens, func(t string) bool { return !slices.Contains(item.BlindIndex, t) }) {
+			found = append(found, item)
+		}
+	}
+	return found, nil
+}
+
+func TestSealedAddEditSearch(t *testing.T) {
+	ctx := context.Background()
+	auth := &fakeAuth{login: "alice", token: "tok"}
+	key := vaultcrypto.DeriveKey("alice", "master")
+	items := &memItems{}
+	server := &memServer{fakeKeys: fakeKeys{key: key}, items: items}
+
+	run := func(stdin string, args ...string) (int, string) {
+		c, out := newTestCommands(auth, stdin)
+		c.items = items
+		c.vault = server
+		return c.Run(ctx, args), out.String()
+	}
+
+	if code, _ := run("master\n", "add", "login", "-title", "gh", "-resource", "github.com", "-login", "alice",
+		"-password", "s3cret", "-seal", "-master-password-stdin"); code != ExitOK {
+		t.Fatalf("add -seal: exit %d", code)
+	}
+	stored := items.items[0]
+	if stored.Type != models.ItemTypeLogin || len(stored.BlindIndex) == 0 ||
+		bytes.Contains(stored.Payload, []byte("s3cret")) {
+		t.Fatalf("stored item = %+v, %s", stored, stored.Payload)
+	}
+
+	if code, _ := run("wrong\n", "edit", "gh", "-password", "x", "-master-password-stdin"); code != ExitUnauthenticated {
+		t.Errorf("edit with a wrong master password: exit %d, want %d", code, ExitUnauthenticated)
+	}
+	if code, _ := run("master\n", "edit", "gh", "-login", "bob", "-master-password-stdin"); code != ExitOK {
+		t.Fatalf("edit sealed: exit %d", code)
+	}
+	payload, err := models.DecodePayload(items.items[0].Payload)
+	if err != nil || payload.Sealed == nil {
+		t.Fatalf("edited item is not sealed: %s", items.items[0].Payload)
+	}
+	opened, err := openPayload(key, *payload.Sealed)
+	if err != nil || opened.Login.Login != "bob" || opened.Login.Password != "s3cret" {
+		t.Errorf("edited payload = %+v, %v", opened.Login, err)
+	}
+
+	code, out := run("master\n", "search", "GitHub bob", "-master-password-stdin", "-output", "json")
+	if code != ExitOK {
+		t.Fatalf("search: exit %d", code)
+	}
+	var found []itemView
+	if err := json.Unmarshal([]byte(out), &found); err != nil {
+		t.Fatalf("search output %q: %v", out, err)
+	}
+	if len(found) != 1 || found[0].Login == nil || found[0].Login.Login != "bob" {
+		t.Errorf("search found %+v", found)
+	}
+	for _, token := range server.sent {
+		if strings.Contains(token, "github") || strings.Contains(token, "bob") {
+			t.Errorf("search sent a plaintext word: %q", token)
+		}
+	}
+
+	if code, out = run("master\n", "search", "gitlab", "-master-password-stdin", "-output", "json"); code != ExitOK ||
+		strings.TrimSpace(out) != "[]" {
+		t.Errorf("search gitlab = %d, %s", code, out)
+	}
+
+	if code, _ := run("pw\n", "add", "note", "-title", "n", "-text-stdin", "-seal", "-master-password-stdin"); code != ExitUsage {
+		t.Errorf("two values from stdin: exit %d, want %d", code, ExitUsage)
+	}
+}
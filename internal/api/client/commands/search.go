@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/vaultcrypto"
+)
+
+// search - ищет на сервере записи, зашифрованные на клиенте, по словам их несекретных
+// полей (см. blindIndex). На сервер уходят только слепые токены слов. Найденные записи
+// расшифровываются ключом хранилища, ID - id записи на сервере.
+func (c *Commands) search(ctx context.Context, args []string) error {
+	query, rest, err := positional(args, "words")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("search")
+	masterStdin := fs.Bool("master-password-stdin", false, "read the master password from stdin")
+	out := newOutputFlags(fs, true)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	key, err := c.vaultKey(ctx, token, *masterStdin)
+	if err != nil {
+		return err
+	}
+	defer clear(key)
+
+	tokens := vaultcrypto.BlindTokens(key, query)
+	if len(tokens) == 0 {
+		return usagef("no words to search for in %q", query)
+	}
+
+	items, err := c.vault.SearchSealed(ctx, c.conn, token, tokens)
+	if err != nil {
+		return err
+	}
+
+	list := itemList{}
+	for _, item := range items {
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil {
+			return err
+		}
+		if payload.Sealed != nil {
+			if payload, err = openPayload(key, *payload.Sealed); err != nil {
+				return err
+			}
+		}
+		list = append(list, newItemView(item, payload, *out.reveal))
+	}
+	return c.render(out, list)
+}
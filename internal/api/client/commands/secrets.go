@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+)
+
+// readSecret - значение секрета: из флага, из stdin (fromStdin) или с терминала без эха.
+// Без терминала и без флагов секрет считается не заданным.
+func (c *Commands) readSecret(name, value string, fromStdin bool) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if fromStdin {
+		return c.readStdin()
+	}
+
+	f, ok := c.in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return "", usagef("%s is required: pass it with -%s-stdin", name, name)
+	}
+
+	fmt.Fprintf(c.errOut, "%s: ", name)
+	secret, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(c.errOut)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// readStdin - читает stdin целиком, убирая завершающий перевод строки.
+func (c *Commands) readStdin() (string, error) {
+	data, err := io.ReadAll(c.in)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,474 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/storage/sqlite"
+	"goph-keeper/internal/vaultcrypto"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	errNotShared     = errors.New("item is not shared with you")
+	errReadOnlyShare = errors.New("item is shared with you read-only")
+)
+
+// shareView - получатель записи в структурированном выводе.
+type shareView struct {
+	Login      string    `json:"login" yaml:"login"`
+	Permission string    `json:"permission" yaml:"permission"`
+	SharedAt   time.Time `json:"shared_at" yaml:"shared_at"`
+}
+
+// shareList - получатели записи.
+type shareList []shareView
+
+// table - таблица получателей записи.
+func (l shareList) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOGIN\tACCESS\tSHARED")
+	for _, v := range l {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Login, v.Permission, v.SharedAt.Local().Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// env - переменные GK_COUNT и GK_<N>_<ПОЛЕ> для каждого получателя.
+func (l shareList) env() []pair {
+	pairs := []pair{{"GK_COUNT", strconv.Itoa(len(l))}}
+	for i, v := range l {
+		pairs = append(pairs, prefixed(fmt.Sprintf("GK_%d_", i), []pair{
+			{"login", v.Login},
+			{"permission", v.Permission},
+			{"shared_at", v.SharedAt.Format(time.RFC3339)},
+		})...)
+	}
+	return pairs
+}
+
+// sharedItemView - чужая запись в структурированном выводе, ID - id записи на сервере.
+type sharedItemView struct {
+	ID         int64     `json:"id" yaml:"id"`
+	Type       string    `json:"type" yaml:"type"`
+	Title      string    `json:"title" yaml:"title"`
+	Owner      string    `json:"owner" yaml:"owner"`
+	Permission string    `json:"permission" yaml:"permission"`
+	UpdatedAt  time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// sharedList - чужие записи, к которым открыт доступ.
+type sharedList []sharedItemView
+
+// table - таблица чужих записей.
+func (l sharedList) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tTITLE\tOWNER\tACCESS\tUPDATED")
+	for _, v := range l {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", v.ID, v.Type, v.Title, v.Owner, v.Permission,
+			v.UpdatedAt.Local().Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// env - переменные GK_COUNT и GK_<N>_<ПОЛЕ> для каждой чужой записи.
+func (l sharedList) env() []pair {
+	pairs := []pair{{"GK_COUNT", strconv.Itoa(len(l))}}
+	for i, v := range l {
+		pairs = append(pairs, prefixed(fmt.Sprintf("GK_%d_", i), []pair{
+			{"id", strconv.FormatInt(v.ID, 10)},
+			{"type", v.Type},
+			{"title", v.Title},
+			{"owner", v.Owner},
+			{"permission", v.Permission},
+			{"updated_at", v.UpdatedAt.Format(time.RFC3339)},
+		})...)
+	}
+	return pairs
+}
+
+// share - выводит получателей записи или открывает доступ к ней пользователю.
+func (c *Commands) share(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+
+	var recipient string
+	if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		recipient, rest = rest[0], rest[1:]
+	}
+
+	fs := c.newFlagSet("share")
+	write := fs.Bool("write", false, "allow the recipient to change the item")
+	masterStdin := fs.Bool("master-password-stdin", false, "read the master password from stdin")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.items.Resolve(ctx, token, ref)
+	if err != nil {
+		return err
+	}
+
+	if recipient == "" {
+		shares, err := c.vault.Shares(ctx, c.conn, token, item.ID)
+		if err != nil {
+			return err
+		}
+		list := make(shareList, 0, len(shares))
+		for _, share := range shares {
+			list = append(list, shareView{
+				Login:      share.RecipientLogin,
+				Permission: share.Permission.String(),
+				SharedAt:   share.CreatedAt,
+			})
+		}
+		return c.render(out, list)
+	}
+
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Sealed == nil {
+		return fmt.Errorf("%w, encrypt it first: edit %s -seal", vault.ErrNotSealed, ref)
+	}
+
+	// ключ записи перешифровывается для получателя, сервер данные не читает
+	key, err := c.vaultKey(ctx, token, *masterStdin)
+	if err != nil {
+		return err
+	}
+	defer clear(key)
+
+	permission := models.SharePermissionRead
+	if *write {
+		permission = models.SharePermissionWrite
+	}
+
+	share, err := c.vault.Share(ctx, c.conn, token, item, recipient, permission, key)
+	if err != nil {
+		return err
+	}
+	return c.render(out, resultView{
+		Status:  "shared",
+		ID:      item.ID,
+		Login:   recipient,
+		message: fmt.Sprintf("item %q shared with %s (%s)", item.Title, recipient, share.Permission),
+	})
+}
+
+// unshare - закрывает пользователю доступ к записи.
+func (c *Commands) unshare(ctx context.Context, args []string) error {
+	ref, rest, err := positional(args, "id or title")
+	if err != nil {
+		return err
+	}
+	recipient, rest, err := positional(rest, "login")
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("unshare")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.items.Resolve(ctx, token, ref)
+	if err != nil {
+		return err
+	}
+
+	if err := c.vault.Unshare(ctx, c.conn, token, item.ID, recipient); err != nil {
+		return err
+	}
+	return c.render(out, resultView{
+		Status:  "unshared",
+		ID:      item.ID,
+		Login:   recipient,
+		message: fmt.Sprintf("item %q is no longer shared with %s", item.Title, recipient),
+	})
+}
+
+// shared - чужие записи, к которым открыт доступ: список, просмотр и изменение.
+func (c *Commands) shared(ctx context.Context, args []string) error {
+	op := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		op, args = args[0], args[1:]
+	}
+
+	var (
+		ref  string
+		rest = args
+		err  error
+	)
+	switch op {
+	case "list":
+	case "get", "edit":
+		if ref, rest, err = positional(args, "id or title"); err != nil {
+			return err
+		}
+	default:
+		return usagef("unknown shared operation %q", op)
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	items, err := c.vault.SharedWithMe(ctx, c.conn, token, nil)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case "get":
+		return c.sharedGet(ctx, token, items, ref, rest)
+	case "edit":
+		return c.sharedEdit(ctx, token, items, ref, rest)
+	}
+
+	fs := c.newFlagSet("shared")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	list := make(sharedList, 0, len(items))
+	for _, shared := range items {
+		list = append(list, sharedItemView{
+			ID:         shared.Item.ID,
+			Type:       shared.Item.Type.String(),
+			Title:      shared.Item.Title,
+			Owner:      shared.OwnerLogin,
+			Permission: shared.Permission.String(),
+			UpdatedAt:  shared.Item.UpdatedAt,
+		})
+	}
+	return c.render(out, list)
+}
+
+// sharedGet - выводит чужую запись. Для записи, зашифрованной на клиенте,
+// спрашивается мастер-пароль, чтобы расшифровать ее закрытым ключом пользователя.
+func (c *Commands) sharedGet(ctx context.Context, token string, items []models.SharedItem, ref string, args []string) error {
+	fs := c.newFlagSet("shared get")
+	fieldName := fs.String("field", "", "print only this field, unmasked")
+	masterStdin := fs.Bool("master-password-stdin", false, "read the master password from stdin")
+	out := newOutputFlags(fs, true)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	shared, err := findShared(items, ref)
+	if err != nil {
+		return err
+	}
+
+	if len(shared.WrappedKey) > 0 {
+		key, err := c.vaultKey(ctx, token, *masterStdin)
+		if err != nil {
+			return err
+		}
+		defer clear(key)
+
+		if items, err = c.vault.SharedWithMe(ctx, c.conn, token, key); err != nil {
+			return err
+		}
+		if shared, err = findShared(items, strconv.FormatInt(shared.Item.ID, 10)); err != nil {
+			return err
+		}
+	}
+
+	payload, err := models.DecodePayload(shared.Item.Payload)
+	if err != nil {
+		return err
+	}
+
+	if *fieldName != "" {
+		f, ok := lookupField(itemFields(shared.Item, payload), *fieldName)
+		if !ok {
+			return usagef("item %q has no field %q", shared.Item.Title, *fieldName)
+		}
+		if f.name == "data" {
+			_, err = c.out.Write([]byte(f.value))
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, f.value)
+		return err
+	}
+
+	return c.render(out, newItemView(shared.Item, payload, *out.reveal))
+}
+
+// sharedEdit - меняет только переданные флагами поля чужой записи, открытой на запись.
+// Запись, зашифрованная на клиенте владельца, расшифровывается и шифруется снова тем же
+// ключом записи, поэтому спрашивается мастер-пароль.
+func (c *Commands) sharedEdit(ctx context.Context, token string, items []models.SharedItem, ref string, args []string) error {
+	shared, err := findShared(items, ref)
+	if err != nil {
+		return err
+	}
+	if shared.Permission != models.SharePermissionWrite {
+		return errReadOnlyShare
+	}
+
+	fs := c.newFlagSet("shared edit")
+	f := newItemFlags(fs, shared.Item.Type)
+	masterStdin := fs.Bool("master-password-stdin", false, "read the master password from stdin")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+	if *masterStdin && f.secretStdin != nil && *f.secretStdin {
+		return usagef("-master-password-stdin cannot be combined with another -*-stdin flag")
+	}
+
+	visited := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) {
+		if !isOutputFlag(fl.Name) && fl.Name != "master-password-stdin" {
+			visited[fl.Name] = true
+		}
+	})
+	if len(visited) == 0 {
+		return usagef("nothing to change")
+	}
+
+	var key []byte
+	if len(shared.WrappedKey) > 0 {
+		if key, err = c.vaultKey(ctx, token, *masterStdin); err != nil {
+			return err
+		}
+		defer clear(key)
+
+		if items, err = c.vault.SharedWithMe(ctx, c.conn, token, key); err != nil {
+			return err
+		}
+		if shared, err = findShared(items, strconv.FormatInt(shared.Item.ID, 10)); err != nil {
+			return err
+		}
+	}
+
+	item := shared.Item
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Sealed != nil {
+		return errSealedItem
+	}
+
+	if err := f.apply(c, &item, &payload, visited); err != nil {
+		return err
+	}
+	if item.Payload, err = models.EncodePayload(payload); err != nil {
+		return err
+	}
+
+	if _, err := c.vault.UpdateSharedItem(ctx, c.conn, token, item, key); err != nil {
+		return err
+	}
+	return c.render(out, resultView{Status: "updated", ID: item.ID})
+}
+
+// vaultKey - ключ хранилища текущего пользователя из мастер-пароля. Ключ проверяется
+// по паре ключей на сервере, а при первом использовании пара создается.
+func (c *Commands) vaultKey(ctx context.Context, token string, fromStdin bool) ([]byte, error) {
+	login, err := c.currentLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := c.readSecret("master-password", "", fromStdin)
+	if err != nil {
+		return nil, err
+	}
+
+	key := vaultcrypto.DeriveKey(login, master)
+	if err := c.vault.EnsureKeyPair(ctx, c.conn, token, key); err != nil {
+		clear(key)
+		return nil, err
+	}
+	return key, nil
+}
+
+// currentLogin - логин текущего пользователя: из агента, если он запущен, иначе из локальной сессии.
+func (c *Commands) currentLogin(ctx context.Context) (string, error) {
+	if c.agentRunning(ctx) {
+		status, err := c.agent.Status(ctx)
+		if err != nil {
+			return "", err
+		}
+		if status.Login != "" {
+			return status.Login, nil
+		}
+	}
+
+	login, err := c.auth.CurrentLogin(ctx)
+	if errors.Is(err, sqlite.ErrNoSession) {
+		return "", ErrNotLoggedIn
+	}
+	return login, err
+}
+
+// findShared - находит чужую запись по id на сервере или точному названию.
+func findShared(items []models.SharedItem, ref string) (models.SharedItem, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		for _, shared := range items {
+			if shared.Item.ID == id {
+				return shared, nil
+			}
+		}
+	}
+
+	var found []models.SharedItem
+	for _, shared := range items {
+		if shared.Item.Title == ref {
+			found = append(found, shared)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return models.SharedItem{}, fmt.Errorf("%q: %w", ref, errNotShared)
+	case 1:
+		return found[0], nil
+	default:
+		return models.SharedItem{}, items_client.ErrAmbiguousName
+	}
+}
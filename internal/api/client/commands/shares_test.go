@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/vaultcrypto"
+	"strings"
+	"testing"
+)
+
+func TestFindShared(t *testing.T) {
+	items := []models.SharedItem{
+		{Item: models.Item{ID: 3, Title: "wifi"}, OwnerLogin: "alice"},
+		{Item: models.Item{ID: 5, Title: "vpn"}, OwnerLogin: "alice"},
+		// одинаковые названия у записей разных владельцев
+		{Item: models.Item{ID: 6, Title: "vpn"}, OwnerLogin: "carol"},
+	}
+
+	tests := []struct {
+		ref    string
+		wantID int64
+		err    error
+	}{
+		{ref: "3", wantID: 3},
+		{ref: "wifi", wantID: 3},
+		{ref: "6", wantID: 6},
+		{ref: "vpn", err: items_client.ErrAmbiguousName},
+		{ref: "4", err: errNotShared},
+		{ref: "missing", err: errNotShared},
+	}
+	for _, tt := range tests {
+		shared, err := findShared(items, tt.ref)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("findShared(%q) error = %v, want %v", tt.ref, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || shared.Item.ID != tt.wantID {
+			t.Errorf("findShared(%q) = %d, %v, want %d", tt.ref, shared.Item.ID, err, tt.wantID)
+		}
+	}
+}
+
+// fakeKeys - пара ключей на сервере, открывающаяся ключом хранилища key.
+type fakeKeys struct {
+	vaultHandlers
+	key []byte
+}
+
+func (f fakeKeys) EnsureKeyPair(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) error {
+	if !bytes.Equal(vaultKey, f.key) {
+		return vault.ErrWrongMasterPassword
+	}
+	return nil
+}
+
+func TestVaultKey(t *testing.T) {
+	auth := &fakeAuth{login: "alice", token: "tok"}
+	want := vaultcrypto.DeriveKey("alice", "master")
+
+	tests := []struct {
+		name  string
+		stdin string
+		err   error
+	}{
+		{name: "master password", stdin: "master\n"},
+		{name: "login password", stdin: "pw\n", err: vault.ErrWrongMasterPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestCommands(auth, tt.stdin)
+			c.vault = fakeKeys{key: want}
+
+			key, err := c.vaultKey(context.Background(), "tok", true)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("vaultKey() error = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && !bytes.Equal(key, want) {
+				t.Errorf("vaultKey() returned a key not derived from the master password")
+			}
+		})
+	}
+}
+
+func TestSharePlainItem(t *testing.T) {
+	auth := &fakeAuth{login: "alice", token: "tok"}
+	plain, err := models.EncodePayload(models.Payload{Note: &models.NotePayload{Text: "secret"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, _ := newTestCommands(auth, "master\n")
+	c.items = &memItems{items: []models.Item{{ID: 1, Type: models.ItemTypeNote, Title: "note", Payload: plain}}}
+	c.vault = fakeKeys{key: vaultcrypto.DeriveKey("alice", "master")}
+
+	// открытую запись сервер получил бы читаемой, мастер-пароль даже не спрашивается
+	err = c.share(context.Background(), []string{"note", "bob", "-master-password-stdin"})
+	if !errors.Is(err, vault.ErrNotSealed) {
+		t.Fatalf("share() error = %v, want %v", err, vault.ErrNotSealed)
+	}
+	if c.in.(*strings.Reader).Len() == 0 {
+		t.Errorf("share() read the master password for an item it cannot share")
+	}
+}
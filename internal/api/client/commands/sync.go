@@ -0,0 +1,33 @@
+package commands
+
+import "context"
+
+// syncItems - отправляет локальные изменения на сервер и забирает серверные.
+func (c *Commands) syncItems(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("sync")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.vault.Sync(ctx, c.conn, token)
+	if err != nil {
+		return err
+	}
+
+	return c.render(out, syncView{
+		Created: res.Created,
+		Updated: res.Updated,
+		Deleted: res.Deleted,
+		Pulled:  res.Pulled,
+		Removed: res.Removed,
+	})
+}
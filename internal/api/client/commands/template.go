@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// renderTemplate - заполняет шаблон значениями из хранилища. Результат собирается в памяти
+// и записывается, только если все ссылки найдены.
+func (c *Commands) renderTemplate(ctx context.Context, args []string) error {
+	path, rest, err := positional(args, "template")
+	if err != nil && len(args) > 0 && args[0] == "-" {
+		path, rest, err = "-", args[1:], nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fs := c.newFlagSet("render")
+	outPath := fs.String("o", "", "output file, written with 0600 permissions; stdout by default")
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+
+	text, err := c.readInput(path)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).
+		Option("missingkey=error").
+		Funcs(c.templateFuncs(ctx, token)).
+		Parse(text)
+	if err != nil {
+		return usagef("%s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		_, err = c.out.Write(buf.Bytes())
+		return err
+	}
+	return writePrivate(*outPath, buf.Bytes())
+}
+
+// readInput - содержимое файла или stdin ("-").
+func (c *Commands) readInput(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(c.in)
+		return string(data), err
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+// templateFuncs - функции шаблона:
+//
+//	{{ secret "prod-db" "password" }} - поле записи, как у get -field;
+//	{{ secret "prod-db" }}            - основной секрет записи.
+func (c *Commands) templateFuncs(ctx context.Context, token string) template.FuncMap {
+	cache := make(map[secretRef]string)
+
+	return template.FuncMap{
+		"secret": func(ref string, field ...string) (string, error) {
+			if len(field) > 1 {
+				return "", fmt.Errorf("secret %q: expected at most one field name", ref)
+			}
+
+			r := secretRef{name: ref, ref: ref}
+			if len(field) == 1 {
+				r.field = field[0]
+			}
+			if value, ok := cache[r]; ok {
+				return value, nil
+			}
+
+			value, err := c.secretValue(ctx, token, r)
+			if err != nil {
+				return "", fmt.Errorf("secret %q: %w", ref, err)
+			}
+			cache[r] = value
+			return value, nil
+		},
+	}
+}
+
+// writePrivate - атомарно записывает файл с правами 0600: данные пишутся во временный
+// файл рядом и переименовываются, поэтому читатель не увидит частично записанный результат.
+func writePrivate(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var errNotInTrash = errors.New("item not found in trash")
+
+// trashItemView - запись корзины в структурированном выводе, ID - id записи на сервере.
+type trashItemView struct {
+	ID        int64     `json:"id" yaml:"id"`
+	Type      string    `json:"type" yaml:"type"`
+	Title     string    `json:"title" yaml:"title"`
+	Tags      []string  `json:"tags" yaml:"tags"`
+	DeletedAt time.Time `json:"deleted_at" yaml:"deleted_at"`
+}
+
+// trashList - записи корзины от недавно удаленных к давним.
+type trashList []trashItemView
+
+// table - таблица записей корзины.
+func (l trashList) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tTITLE\tTAGS\tDELETED")
+	for _, v := range l {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", v.ID, v.Type, v.Title,
+			strings.Join(v.Tags, ","), v.DeletedAt.Local().Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// env - переменные GK_COUNT и GK_<N>_<ПОЛЕ> для каждой записи корзины.
+func (l trashList) env() []pair {
+	pairs := []pair{{"GK_COUNT", strconv.Itoa(len(l))}}
+	for i, v := range l {
+		pairs = append(pairs, prefixed(fmt.Sprintf("GK_%d_", i), []pair{
+			{"id", strconv.FormatInt(v.ID, 10)},
+			{"type", v.Type},
+			{"title", v.Title},
+			{"tags", strings.Join(v.Tags, ",")},
+			{"deleted_at", v.DeletedAt.Format(time.RFC3339)},
+		})...)
+	}
+	return pairs
+}
+
+// trash - корзина на сервере: список, восстановление и окончательное удаление записи.
+func (c *Commands) trash(ctx context.Context, args []string) error {
+	op := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		op, args = args[0], args[1:]
+	}
+
+	var (
+		ref  string
+		rest = args
+		err  error
+	)
+	switch op {
+	case "list":
+	case "restore", "purge":
+		if ref, rest, err = positional(args, "id or title"); err != nil {
+			return err
+		}
+	default:
+		return usagef("unknown trash operation %q", op)
+	}
+
+	fs := c.newFlagSet("trash")
+	out := newOutputFlags(fs, false)
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	items, err := c.vault.Trash(ctx, c.conn, token)
+	if err != nil {
+		return err
+	}
+
+	if op == "list" {
+		list := make(trashList, 0, len(items))
+		for _, item := range items {
+			list = append(list, trashItemView{
+				ID:        item.ID,
+				Type:      item.Type.String(),
+				Title:     item.Title,
+				Tags:      item.Tags,
+				DeletedAt: item.DeletedAt,
+			})
+		}
+		return c.render(out, list)
+	}
+
+	item, err := findInTrash(items, ref)
+	if err != nil {
+		return err
+	}
+
+	if op == "restore" {
+		if _, err := c.vault.RestoreItem(ctx, c.conn, token, item.ID); err != nil {
+			return err
+		}
+		return c.render(out, resultView{
+			Status:  "restored",
+			ID:      item.ID,
+			message: fmt.Sprintf("item %q restored from trash", item.Title),
+		})
+	}
+
+	if err := c.vault.PurgeItem(ctx, c.conn, token, item.ID); err != nil {
+		return err
+	}
+	return c.render(out, resultView{
+		Status:  "purged",
+		ID:      item.ID,
+		message: fmt.Sprintf("item %q deleted permanently", item.Title),
+	})
+}
+
+// findInTrash - находит запись корзины по id на сервере или точному названию.
+func findInTrash(items []models.Item, ref string) (models.Item, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		for _, item := range items {
+			if item.ID == id {
+				return item, nil
+			}
+		}
+	}
+
+	var found []models.Item
+	for _, item := range items {
+		if item.Title == ref {
+			found = append(found, item)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return models.Item{}, fmt.Errorf("%q: %w", ref, errNotInTrash)
+	case 1:
+		return found[0], nil
+	default:
+		return models.Item{}, items_client.ErrAmbiguousName
+	}
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"errors"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/services/client/items_client"
+	"testing"
+)
+
+func TestFindInTrash(t *testing.T) {
+	items := []models.Item{
+		{ID: 7, Title: "mail"},
+		{ID: 8, Title: "bank"},
+		{ID: 9, Title: "bank"},
+		{ID: 10, Title: "42"},
+	}
+
+	tests := []struct {
+		ref    string
+		wantID int64
+		err    error
+	}{
+		{ref: "7", wantID: 7},
+		{ref: "mail", wantID: 7},
+		// числовое название, которое не совпадает ни с одним id
+		{ref: "42", wantID: 10},
+		{ref: "bank", err: items_client.ErrAmbiguousName},
+		{ref: "missing", err: errNotInTrash},
+	}
+	for _, tt := range tests {
+		item, err := findInTrash(items, tt.ref)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("findInTrash(%q) error = %v, want %v", tt.ref, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || item.ID != tt.wantID {
+			t.Errorf("findInTrash(%q) = %d, %v, want %d", tt.ref, item.ID, err, tt.wantID)
+		}
+	}
+}
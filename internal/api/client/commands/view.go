@@ -0,0 +1,337 @@
+package commands
+
+import (
+	"encoding/base64"
+	"fmt"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/redact"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// itemView - схема записи в структурированном выводе. Секреты скрыты, пока не передан -reveal.
+type itemView struct {
+	ID        int64       `json:"id" yaml:"id"`
+	Type      string      `json:"type" yaml:"type"`
+	Title     string      `json:"title" yaml:"title"`
+	Tags      []string    `json:"tags" yaml:"tags"`
+	Favorite  bool        `json:"favorite" yaml:"favorite"`
+	CreatedAt time.Time   `json:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" yaml:"updated_at"`
+	Login     *loginView  `json:"login,omitempty" yaml:"login,omitempty"`
+	Note      *noteView   `json:"note,omitempty" yaml:"note,omitempty"`
+	Binary    *binaryView `json:"binary,omitempty" yaml:"binary,omitempty"`
+	Card      *cardView   `json:"card,omitempty" yaml:"card,omitempty"`
+	SSHKey    *sshKeyView `json:"ssh_key,omitempty" yaml:"ssh_key,omitempty"`
+	TOTP      *totpView   `json:"totp,omitempty" yaml:"totp,omitempty"`
+}
+
+// loginView - данные записи login.
+type loginView struct {
+	Resource string `json:"resource" yaml:"resource"`
+	Login    string `json:"login" yaml:"login"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// noteView - данные записи note.
+type noteView struct {
+	Text string `json:"text" yaml:"text"`
+}
+
+// binaryView - данные записи binary. Содержимое файла в base64 выводится только с -reveal.
+type binaryView struct {
+	Name string `json:"name" yaml:"name"`
+	Size int    `json:"size" yaml:"size"`
+	Data string `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// cardView - данные записи card. Без -reveal от номера остаются последние четыре цифры.
+type cardView struct {
+	Number string `json:"number" yaml:"number"`
+	Holder string `json:"holder" yaml:"holder"`
+	Expiry string `json:"expiry" yaml:"expiry"`
+	CVV    string `json:"cvv" yaml:"cvv"`
+}
+
+// sshKeyView - данные записи ssh. Закрытый ключ выводится только с -reveal.
+type sshKeyView struct {
+	PublicKey   string `json:"public_key" yaml:"public_key"`
+	Comment     string `json:"comment" yaml:"comment"`
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+	PrivateKey  string `json:"private_key,omitempty" yaml:"private_key,omitempty"`
+}
+
+// totpView - параметры записи totp. Секрет выводится только с -reveal, код - командой otp.
+type totpView struct {
+	Issuer    string `json:"issuer" yaml:"issuer"`
+	Account   string `json:"account" yaml:"account"`
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Digits    int    `json:"digits" yaml:"digits"`
+	Period    int    `json:"period" yaml:"period"`
+	Secret    string `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// newItemView - представление записи для вывода.
+func newItemView(item models.Item, payload models.Payload, reveal bool) itemView {
+	secret := func(s string) string {
+		if reveal || s == "" {
+			return s
+		}
+		return redact.Mask(s)
+	}
+
+	tags := item.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	v := itemView{
+		ID:        item.ID,
+		Type:      item.Type.String(),
+		Title:     item.Title,
+		Tags:      tags,
+		Favorite:  item.Favorite,
+		CreatedAt: item.CreatedAt.UTC().Truncate(time.Second),
+		UpdatedAt: item.UpdatedAt.UTC().Truncate(time.Second),
+	}
+
+	switch {
+	case payload.Login != nil:
+		v.Login = &loginView{
+			Resource: payload.Login.Resource,
+			Login:    payload.Login.Login,
+			Password: secret(payload.Login.Password),
+		}
+	case payload.Note != nil:
+		v.Note = &noteView{Text: secret(payload.Note.Text)}
+	case payload.Binary != nil:
+		v.Binary = &binaryView{Name: payload.Binary.Name, Size: len(payload.Binary.Data)}
+		if reveal {
+			v.Binary.Data = base64.StdEncoding.EncodeToString(payload.Binary.Data)
+		}
+	case payload.Card != nil:
+		number := payload.Card.Number
+		if !reveal && len(number) > 4 {
+			number = redact.Masked + number[len(number)-4:]
+		}
+		v.Card = &cardView{
+			Number: number,
+			Holder: payload.Card.Holder,
+			Expiry: payload.Card.Expiry,
+			CVV:    secret(payload.Card.CVV),
+		}
+	case payload.SSHKey != nil:
+		v.SSHKey = &sshKeyView{
+			PublicKey:   payload.SSHKey.PublicKey,
+			Comment:     payload.SSHKey.Comment,
+			Fingerprint: payload.SSHKey.Fingerprint,
+		}
+		if reveal {
+			v.SSHKey.PrivateKey = payload.SSHKey.PrivateKey
+		}
+	case payload.TOTP != nil:
+		v.TOTP = &totpView{
+			Issuer:    payload.TOTP.Issuer,
+			Account:   payload.TOTP.Account,
+			Algorithm: payload.TOTP.Algorithm,
+			Digits:    payload.TOTP.Digits,
+			Period:    payload.TOTP.Period,
+		}
+		if reveal {
+			v.TOTP.Secret = payload.TOTP.Secret
+		}
+	}
+	return v
+}
+
+// pairs - поля записи в порядке схемы, вложенные поля с префиксом раздела.
+func (v itemView) pairs() []pair {
+	pairs := []pair{
+		{"id", strconv.FormatInt(v.ID, 10)},
+		{"type", v.Type},
+		{"title", v.Title},
+		{"tags", strings.Join(v.Tags, ",")},
+		{"favorite", strconv.FormatBool(v.Favorite)},
+		{"created_at", v.CreatedAt.Format(time.RFC3339)},
+		{"updated_at", v.UpdatedAt.Format(time.RFC3339)},
+	}
+
+	switch {
+	case v.Login != nil:
+		pairs = append(pairs,
+			pair{"login_resource", v.Login.Resource},
+			pair{"login_login", v.Login.Login},
+			pair{"login_password", v.Login.Password},
+		)
+	case v.Note != nil:
+		pairs = append(pairs, pair{"note_text", v.Note.Text})
+	case v.Binary != nil:
+		pairs = append(pairs,
+			pair{"binary_name", v.Binary.Name},
+			pair{"binary_size", strconv.Itoa(v.Binary.Size)},
+		)
+		if v.Binary.Data != "" {
+			pairs = append(pairs, pair{"binary_data", v.Binary.Data})
+		}
+	case v.Card != nil:
+		pairs = append(pairs,
+			pair{"card_number", v.Card.Number},
+			pair{"card_holder", v.Card.Holder},
+			pair{"card_expiry", v.Card.Expiry},
+			pair{"card_cvv", v.Card.CVV},
+		)
+	case v.SSHKey != nil:
+		pairs = append(pairs,
+			pair{"ssh_key_public_key", v.SSHKey.PublicKey},
+			pair{"ssh_key_comment", v.SSHKey.Comment},
+			pair{"ssh_key_fingerprint", v.SSHKey.Fingerprint},
+		)
+		if v.SSHKey.PrivateKey != "" {
+			pairs = append(pairs, pair{"ssh_key_private_key", v.SSHKey.PrivateKey})
+		}
+	case v.TOTP != nil:
+		pairs = append(pairs,
+			pair{"totp_issuer", v.TOTP.Issuer},
+			pair{"totp_account", v.TOTP.Account},
+			pair{"totp_algorithm", v.TOTP.Algorithm},
+			pair{"totp_digits", strconv.Itoa(v.TOTP.Digits)},
+			pair{"totp_period", strconv.Itoa(v.TOTP.Period)},
+		)
+		if v.TOTP.Secret != "" {
+			pairs = append(pairs, pair{"totp_secret", v.TOTP.Secret})
+		}
+	}
+	return pairs
+}
+
+// table - поля записи по одному в строке.
+func (v itemView) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, p := range v.pairs() {
+		fmt.Fprintf(tw, "%s:\t%s\n", p.key, p.value)
+	}
+	return tw.Flush()
+}
+
+// env - поля записи как переменные GK_<ПОЛЕ>.
+func (v itemView) env() []pair {
+	return prefixed("GK_", v.pairs())
+}
+
+// itemList - список записей.
+type itemList []itemView
+
+// table - таблица записей без данных.
+func (l itemList) table(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tTITLE\tTAGS\tUPDATED")
+	for _, v := range l {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", v.ID, v.Type, v.Title,
+			strings.Join(v.Tags, ","), v.UpdatedAt.Local().Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// env - переменные GK_COUNT и GK_<N>_<ПОЛЕ> для каждой записи.
+func (l itemList) env() []pair {
+	pairs := []pair{{"GK_COUNT", strconv.Itoa(len(l))}}
+	for i, v := range l {
+		pairs = append(pairs, prefixed(fmt.Sprintf("GK_%d_", i), v.pairs())...)
+	}
+	return pairs
+}
+
+// resultView - результат команды, которая не выводит записи.
+type resultView struct {
+	Status string `json:"status" yaml:"status"`
+	ID     int64  `json:"id,omitempty" yaml:"id,omitempty"`
+	Login  string `json:"login,omitempty" yaml:"login,omitempty"`
+	Count  int    `json:"count,omitempty" yaml:"count,omitempty"`
+
+	// message - текст для табличного вывода
+	message string
+}
+
+// table - текст результата.
+func (r resultView) table(w io.Writer) error {
+	if r.message == "" {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, r.message)
+	return err
+}
+
+// env - поля результата как переменные GK_<ПОЛЕ>.
+func (r resultView) env() []pair {
+	pairs := []pair{{"GK_STATUS", r.Status}}
+	if r.ID != 0 {
+		pairs = append(pairs, pair{"GK_ID", strconv.FormatInt(r.ID, 10)})
+	}
+	if r.Login != "" {
+		pairs = append(pairs, pair{"GK_LOGIN", r.Login})
+	}
+	if r.Count != 0 {
+		pairs = append(pairs, pair{"GK_COUNT", strconv.Itoa(r.Count)})
+	}
+	return pairs
+}
+
+// otpView - текущий одноразовый код.
+type otpView struct {
+	Code      string    `json:"code" yaml:"code"`
+	Remaining int       `json:"remaining" yaml:"remaining"`
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// table - только код, чтобы его было удобно подставлять в скрипты.
+func (o otpView) table(w io.Writer) error {
+	_, err := fmt.Fprintln(w, o.Code)
+	return err
+}
+
+// env - код и время его действия как GK_CODE и GK_REMAINING.
+func (o otpView) env() []pair {
+	return []pair{
+		{"GK_CODE", o.Code},
+		{"GK_REMAINING", strconv.Itoa(o.Remaining)},
+	}
+}
+
+// syncView - итог синхронизации.
+type syncView struct {
+	Created int `json:"created" yaml:"created"`
+	Updated int `json:"updated" yaml:"updated"`
+	Deleted int `json:"deleted" yaml:"deleted"`
+	Pulled  int `json:"pulled" yaml:"pulled"`
+	Removed int `json:"removed" yaml:"removed"`
+}
+
+// table - итог одной строкой.
+func (s syncView) table(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "pushed: %d created, %d updated, %d deleted; pulled: %d, removed: %d\n",
+		s.Created, s.Updated, s.Deleted, s.Pulled, s.Removed)
+	return err
+}
+
+// env - счетчики как переменные GK_<СЧЕТЧИК>.
+func (s syncView) env() []pair {
+	return []pair{
+		{"GK_CREATED", strconv.Itoa(s.Created)},
+		{"GK_UPDATED", strconv.Itoa(s.Updated)},
+		{"GK_DELETED", strconv.Itoa(s.Deleted)},
+		{"GK_PULLED", strconv.Itoa(s.Pulled)},
+		{"GK_REMOVED", strconv.Itoa(s.Removed)},
+	}
+}
+
+// prefixed - имена переменных окружения из полей: префикс и имя поля в верхнем регистре.
+func prefixed(prefix string, pairs []pair) []pair {
+	out := make([]pair, 0, len(pairs))
+	for _, p := range pairs {
+		out = append(out, pair{prefix + strings.ToUpper(p.key), p.value})
+	}
+	return out
+}
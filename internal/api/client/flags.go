@@ -0,0 +1,33 @@
+package client
+
+import (
+	"flag"
+	"os"
+	"time"
+)
+
+type Flags struct {
+	Addr             string
+	ClipboardTimeout time.Duration
+	BreachSource     string
+	DataDir          string
+}
+
+func NewFlags() *Flags {
+	return &Flags{}
+}
+
+// Parse - разбирает глобальные флаги клиента и возвращает оставшиеся аргументы -
+// имя подкоманды и ее флаги.
+func (f *Flags) Parse(args []string) []string {
+	flag.StringVar(&f.Addr, "addr", "localhost:8081", "gRPC server address")
+	flag.DurationVar(&f.ClipboardTimeout, "clipboard-timeout", 30*time.Second,
+		"clear the clipboard after this timeout, 0 - never")
+	flag.StringVar(&f.BreachSource, "hibp", "",
+		"HIBP range dataset for the breached-password check: directory, sorted file or http(s) range server URL")
+	flag.StringVar(&f.DataDir, "data-dir", os.Getenv("GOPH_KEEPER_DATA_DIR"),
+		"directory for the local database and log, current directory by default")
+	// флаги CommandLine завершают процесс при ошибке разбора, поэтому ошибку можно не проверять
+	_ = flag.CommandLine.Parse(args)
+	return flag.Args()
+}
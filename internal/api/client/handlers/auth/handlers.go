@@ -9,6 +9,9 @@ import (
 
 type service interface {
 	SaveTokenInBase(ctx context.Context, login, token string) error
+	CurrentToken(ctx context.Context) (string, error)
+	CurrentLogin(ctx context.Context) (string, error)
+	Logout(ctx context.Context) error
 }
 
 type Handlers struct {
@@ -59,3 +62,22 @@ func (h *Handlers) AuthUser(ctx context.Context, conn *grpc.ClientConn, login, p
 	}
 	return token.Token, nil
 }
+
+// CurrentToken - токен пользователя, выполнившего вход последним.
+func (h *Handlers) CurrentToken(ctx context.Context) (string, error) {
+	return h.service.CurrentToken(ctx)
+}
+
+// CurrentLogin - логин пользователя, выполнившего вход последним.
+func (h *Handlers) CurrentLogin(ctx context.Context) (string, error) {
+	return h.service.CurrentLogin(ctx)
+}
+
+// Logout - завершает сессию текущего пользователя на этом клиенте.
+func (h *Handlers) Logout(ctx context.Context) error {
+	if err := h.service.Logout(ctx); err != nil {
+		h.log.Error("failed to logout", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package vault
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+)
+
+// unmarshalPayloadOpt - пропускает неизвестные поля, чтобы записи новых версий сервера читались.
+var unmarshalPayloadOpt = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// itemToProto - преобразует запись кэша в запись gRPC. Формат payload кэша совпадает
+// с protojson варианта данных Item, поэтому он разбирается напрямую.
+func itemToProto(item models.Item, serverID int64) (*pd.Item, error) {
+	var decoded pd.Item
+	if err := unmarshalPayloadOpt.Unmarshal(item.Payload, &decoded); err != nil {
+		return nil, err
+	}
+
+	return &pd.Item{
+		Id:         serverID,
+		Type:       pd.ItemType(item.Type),
+		Title:      item.Title,
+		Tags:       item.Tags,
+		Favorite:   item.Favorite,
+		FolderId:   item.FolderID,
+		BlindIndex: item.BlindIndex,
+		Payload:    decoded.GetPayload(),
+	}, nil
+}
+
+// itemFromProto - преобразует запись gRPC в запись кэша, ID - id записи на сервере.
+// У надгробий и записей корзины заполнен DeletedAt.
+func itemFromProto(in *pd.Item) (models.Item, error) {
+	payload, err := protojson.Marshal(&pd.Item{Payload: in.GetPayload()})
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	item := models.Item{
+		ID:         in.GetId(),
+		Type:       models.ItemType(in.GetType()),
+		Title:      in.GetTitle(),
+		Tags:       in.GetTags(),
+		Favorite:   in.GetFavorite(),
+		FolderID:   in.GetFolderId(),
+		BlindIndex: in.GetBlindIndex(),
+		Payload:    payload,
+		CreatedAt:  in.GetCreatedAt().AsTime(),
+		UpdatedAt:  in.GetUpdatedAt().AsTime(),
+	}
+	if in.GetDeletedAt() != nil {
+		item.DeletedAt = in.GetDeletedAt().AsTime()
+	}
+	return item, nil
+}
+
+// folderFromProto - преобразует папку gRPC в папку кэша, ID - id папки на сервере.
+func folderFromProto(in *pd.Folder) models.Folder {
+	return models.Folder{
+		ID:        in.GetId(),
+		ParentID:  in.GetParentId(),
+		Name:      in.GetName(),
+		CreatedAt: in.GetCreatedAt().AsTime(),
+		UpdatedAt: in.GetUpdatedAt().AsTime(),
+	}
+}
+
+// shareFromProto - преобразует доступ gRPC в доступ к записи.
+func shareFromProto(in *pd.Share) models.Share {
+	return models.Share{
+		ItemID:         in.GetItemId(),
+		RecipientLogin: in.GetRecipient(),
+		WrappedKey:     in.GetWrappedKey(),
+		Permission:     models.SharePermission(in.GetPermission()),
+		CreatedAt:      in.GetCreatedAt().AsTime(),
+		UpdatedAt:      in.GetUpdatedAt().AsTime(),
+	}
+}
+
+// sharedItemFromProto - преобразует чужую запись gRPC, ID записи - id на сервере.
+func sharedItemFromProto(in *pd.SharedItem) (models.SharedItem, error) {
+	item, err := itemFromProto(in.GetItem())
+	if err != nil {
+		return models.SharedItem{}, err
+	}
+
+	return models.SharedItem{
+		Item:       item,
+		OwnerLogin: in.GetOwner(),
+		Permission: models.SharePermission(in.GetPermission()),
+		WrappedKey: in.GetWrappedKey(),
+	}, nil
+}
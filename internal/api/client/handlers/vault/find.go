@@ -0,0 +1,96 @@
+package vault
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
+	pd "goph-keeper/internal/proto/v2"
+	"goph-keeper/internal/services/client/items_client"
+	"strings"
+)
+
+// Find - ищет запись на сервере по точному названию без сохранения в локальный кэш.
+// Нужна, когда запись еще не попала в кэш, а данные требуются только в памяти.
+func (h *Handlers) Find(ctx context.Context, conn *grpc.ClientConn, token, title string) (models.Item, error) {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	var (
+		found     []models.Item
+		pageToken string
+	)
+	for {
+		resp, err := client.Search(ctx, &pd.SearchRequest{
+			Query:     title,
+			PageSize:  pagination.MaxPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			h.log.Error("failed to search server items", "error", err)
+			return models.Item{}, err
+		}
+
+		for _, in := range resp.GetItems() {
+			if !strings.EqualFold(in.GetTitle(), title) {
+				continue
+			}
+			item, err := itemFromProto(in)
+			if err != nil {
+				return models.Item{}, err
+			}
+			found = append(found, item)
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return models.Item{}, items_client.ErrItemNotFound
+	case 1:
+		return found[0], nil
+	default:
+		return models.Item{}, items_client.ErrAmbiguousName
+	}
+}
+
+// SearchSealed - записи на сервере, в слепом индексе которых есть все токены tokens,
+// ID - id записи на сервере. Сервер сравнивает токены, не зная слов, из которых они
+// получены. Найденные записи в локальный кэш не сохраняются.
+func (h *Handlers) SearchSealed(ctx context.Context, conn *grpc.ClientConn, token string, tokens []string) ([]models.Item, error) {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	var (
+		items     []models.Item
+		pageToken string
+	)
+	for {
+		resp, err := client.Search(ctx, &pd.SearchRequest{
+			BlindTokens: tokens,
+			PageSize:    pagination.MaxPageSize,
+			PageToken:   pageToken,
+		})
+		if err != nil {
+			h.log.Error("failed to search server items", "error", err)
+			return nil, err
+		}
+
+		for _, in := range resp.GetItems() {
+			item, err := itemFromProto(in)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			return items, nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+)
+
+// CreateFolder - создает папку на сервере и обновляет копию дерева папок в кэше.
+// ParentID - id родительской папки на сервере, 0 - корень.
+func (h *Handlers) CreateFolder(ctx context.Context, conn *grpc.ClientConn, token string, folder models.Folder) (models.Folder, error) {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	resp, err := client.CreateFolder(ctx, &pd.CreateFolderRequest{
+		Folder: &pd.Folder{ParentId: folder.ParentID, Name: folder.Name},
+	})
+	if err != nil {
+		h.log.Error("failed to create folder", "error", err)
+		return models.Folder{}, err
+	}
+
+	return folderFromProto(resp.GetFolder()), h.pullFolders(ctx, client, token)
+}
+
+// UpdateFolder - переименовывает папку на сервере или переносит ее к другому родителю
+// и обновляет копию дерева папок в кэше.
+func (h *Handlers) UpdateFolder(ctx context.Context, conn *grpc.ClientConn, token string, folder models.Folder) (models.Folder, error) {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	resp, err := client.UpdateFolder(ctx, &pd.UpdateFolderRequest{
+		Folder: &pd.Folder{Id: folder.ID, ParentId: folder.ParentID, Name: folder.Name},
+	})
+	if err != nil {
+		h.log.Error("failed to update folder", "error", err)
+		return models.Folder{}, err
+	}
+
+	return folderFromProto(resp.GetFolder()), h.pullFolders(ctx, client, token)
+}
+
+// DeleteFolder - удаляет папку с вложенными папками на сервере. Записи из них сервер
+// переносит в корень, кэш забирает это синхронизацией.
+func (h *Handlers) DeleteFolder(ctx context.Context, conn *grpc.ClientConn, token string, id int64) (models.SyncResult, error) {
+	client := pd.NewVaultServiceClient(conn)
+	if _, err := client.DeleteFolder(withToken(ctx, token), &pd.DeleteFolderRequest{Id: id}); err != nil {
+		h.log.Error("failed to delete folder", "error", err)
+		return models.SyncResult{}, err
+	}
+
+	return h.Sync(ctx, conn, token)
+}
+
+// pullFolders - заменяет копию дерева папок в кэше папками сервера.
+func (h *Handlers) pullFolders(ctx context.Context, client pd.VaultServiceClient, token string) error {
+	resp, err := client.ListFolders(ctx, &pd.ListFoldersRequest{})
+	if err != nil {
+		return err
+	}
+
+	folders := make([]models.Folder, 0, len(resp.GetFolders()))
+	for _, in := range resp.GetFolders() {
+		folders = append(folders, folderFromProto(in))
+	}
+
+	return h.service.ApplyFolders(ctx, token, folders)
+}
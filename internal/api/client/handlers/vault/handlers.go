@@ -0,0 +1,146 @@
+package vault
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
+	pd "goph-keeper/internal/proto/v2"
+	"log/slog"
+)
+
+// syncService - локальная часть синхронизации.
+type syncService interface {
+	PendingChanges(ctx context.Context, token string) ([]models.LocalItem, error)
+	MarkSynced(ctx context.Context, id, serverID, version int64) error
+	Purge(ctx context.Context, id int64) error
+	ServerID(ctx context.Context, token string, id int64) (int64, error)
+	ApplyRemote(ctx context.Context, token string, items []models.Item) (int, int, error)
+	ApplyFolders(ctx context.Context, token string, folders []models.Folder) error
+}
+
+// Handlers - клиент VaultService сервера.
+type Handlers struct {
+	log     *slog.Logger
+	service syncService
+}
+
+func NewHandlers(log *slog.Logger, service syncService) *Handlers {
+	return &Handlers{
+		log:     log,
+		service: service,
+	}
+}
+
+// withToken - добавляет токен в metadata запроса.
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", token)
+}
+
+// Sync - отправляет локальные изменения на сервер и затем приводит кэш к состоянию сервера.
+// При конфликте побеждает локальное изменение: оно отправляется раньше, чем читается сервер.
+func (h *Handlers) Sync(ctx context.Context, conn *grpc.ClientConn, token string) (models.SyncResult, error) {
+	var result models.SyncResult
+
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	changes, err := h.service.PendingChanges(ctx, token)
+	if err != nil {
+		return result, err
+	}
+
+	for _, change := range changes {
+		if err := h.push(ctx, client, change, &result); err != nil {
+			h.log.Error("failed to push item", "id", change.ID, "error", err)
+			return result, err
+		}
+	}
+
+	// папки раньше записей: записи из удаленных папок кэш переносит в корень
+	if err := h.pullFolders(ctx, client, token); err != nil {
+		h.log.Error("failed to list server folders", "error", err)
+		return result, err
+	}
+
+	items, err := listAll(ctx, client)
+	if err != nil {
+		h.log.Error("failed to list server items", "error", err)
+		return result, err
+	}
+
+	result.Pulled, result.Removed, err = h.service.ApplyRemote(ctx, token, items)
+	return result, err
+}
+
+// push - отправляет одно локальное изменение.
+func (h *Handlers) push(ctx context.Context, client pd.VaultServiceClient, change models.LocalItem, result *models.SyncResult) error {
+	if change.Deleted {
+		_, err := client.DeleteItem(ctx, &pd.DeleteItemRequest{Id: change.ServerID})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		result.Deleted++
+		return h.service.Purge(ctx, change.ID)
+	}
+
+	item, err := itemToProto(change.Item, change.ServerID)
+	if err != nil {
+		return err
+	}
+
+	if change.ServerID != 0 {
+		resp, err := client.UpdateItem(ctx, &pd.UpdateItemRequest{Item: item})
+		switch {
+		case err == nil:
+			result.Updated++
+			return h.service.MarkSynced(ctx, change.ID, resp.GetItem().GetId(), change.Version)
+		case status.Code(err) != codes.NotFound:
+			return err
+		}
+		// запись удалили на сервере, а локально изменили - создаем заново
+		item.Id = 0
+	}
+
+	resp, err := client.CreateItem(ctx, &pd.CreateItemRequest{Item: item})
+	if err != nil {
+		return err
+	}
+	result.Created++
+	return h.service.MarkSynced(ctx, change.ID, resp.GetItem().GetId(), change.Version)
+}
+
+// listAll - читает все записи пользователя с сервера постранично, вместе с надгробиями
+// записей из корзины.
+func listAll(ctx context.Context, client pd.VaultServiceClient) ([]models.Item, error) {
+	var (
+		items []models.Item
+		token string
+	)
+	for {
+		resp, err := client.ListItems(ctx, &pd.ListItemsRequest{
+			PageSize:       pagination.MaxPageSize,
+			PageToken:      token,
+			IncludeDeleted: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, in := range resp.GetItems() {
+			item, err := itemFromProto(in)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		token = resp.GetNextPageToken()
+		if token == "" {
+			return items, nil
+		}
+	}
+}
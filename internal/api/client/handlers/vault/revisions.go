@@ -0,0 +1,78 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+)
+
+var (
+	ErrNotSynced = errors.New("item is not on the server yet, run sync first")
+)
+
+// Revisions - история изменений записи кэша на сервере, от новых ревизий к старым.
+// id - локальный id записи, он же проставляется в Item ревизий.
+func (h *Handlers) Revisions(ctx context.Context, conn *grpc.ClientConn, token string, id int64) ([]models.Revision, error) {
+	serverID, err := h.service.ServerID(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+	if serverID == 0 {
+		return nil, ErrNotSynced
+	}
+
+	client := pd.NewVaultServiceClient(conn)
+	resp, err := client.ListRevisions(withToken(ctx, token), &pd.ListRevisionsRequest{ItemId: serverID})
+	if err != nil {
+		h.log.Error("failed to list revisions", "error", err)
+		return nil, err
+	}
+
+	revisions := make([]models.Revision, 0, len(resp.GetRevisions()))
+	for _, in := range resp.GetRevisions() {
+		item, err := itemFromProto(in.GetItem())
+		if err != nil {
+			return nil, err
+		}
+		item.ID = id
+		revisions = append(revisions, models.Revision{
+			ID:             in.GetId(),
+			Item:           item,
+			ChangedByLogin: in.GetChangedBy(),
+			CreatedAt:      in.GetChangedAt().AsTime(),
+		})
+	}
+
+	return revisions, nil
+}
+
+// RestoreRevision - возвращает запись к состоянию ревизии на сервере и обновляет кэш.
+// Локальные изменения сначала отправляются: иначе следующая синхронизация перезаписала бы
+// восстановленное состояние неотправленным.
+func (h *Handlers) RestoreRevision(ctx context.Context, conn *grpc.ClientConn, token string, id, revisionID int64) (models.SyncResult, error) {
+	if _, err := h.Sync(ctx, conn, token); err != nil {
+		return models.SyncResult{}, err
+	}
+
+	serverID, err := h.service.ServerID(ctx, token, id)
+	if err != nil {
+		return models.SyncResult{}, err
+	}
+	if serverID == 0 {
+		return models.SyncResult{}, ErrNotSynced
+	}
+
+	client := pd.NewVaultServiceClient(conn)
+	_, err = client.RestoreRevision(withToken(ctx, token), &pd.RestoreRevisionRequest{
+		ItemId:     serverID,
+		RevisionId: revisionID,
+	})
+	if err != nil {
+		h.log.Error("failed to restore revision", "error", err)
+		return models.SyncResult{}, err
+	}
+
+	return h.Sync(ctx, conn, token)
+}
@@ -0,0 +1,323 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+	"goph-keeper/internal/vaultcrypto"
+)
+
+var (
+	ErrVaultKeyRequired    = errors.New("item is encrypted on the client, master password is required")
+	ErrWrongMasterPassword = errors.New("wrong master password")
+	ErrNotSealed           = errors.New("only items encrypted on the client can be shared")
+)
+
+// EnsureKeyPair - создает пару ключей пользователя на сервере, если ее еще нет, а если
+// пара уже есть - проверяет, что ключ хранилища ее открывает. Так опечатка в мастер-пароле
+// обнаруживается до того, как им что-то зашифровано. Закрытый ключ шифруется ключом
+// хранилища и на сервер уходит только зашифрованным.
+func (h *Handlers) EnsureKeyPair(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) error {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	resp, err := client.GetKeyPair(ctx, &pd.GetKeyPairRequest{})
+	switch {
+	case err == nil:
+		privateKey, err := vaultcrypto.OpenPrivateKey(vaultKey, resp.GetKeyPair().GetPrivateKey())
+		if errors.Is(err, vaultcrypto.ErrDecryptFailed) {
+			return ErrWrongMasterPassword
+		}
+		clear(privateKey)
+		return err
+	case status.Code(err) != codes.NotFound:
+		h.log.Error("failed to get key pair", "error", err)
+		return err
+	}
+
+	publicKey, privateKey, err := vaultcrypto.GenerateKeyPair(vaultKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CreateKeyPair(ctx, &pd.CreateKeyPairRequest{
+		KeyPair: &pd.KeyPair{PublicKey: publicKey, PrivateKey: privateKey},
+	})
+	if err != nil {
+		h.log.Error("failed to create key pair", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Share - открывает пользователю recipient доступ к записи кэша. Выдать можно только
+// запись, зашифрованную на клиенте: ее ключ перешифровывается открытым ключом получателя,
+// для этого нужен ключ хранилища. Открытую запись пришлось бы отдать серверу читаемой,
+// поэтому она не выдается. Локальные изменения сначала отправляются, чтобы запись была на сервере.
+func (h *Handlers) Share(ctx context.Context, conn *grpc.ClientConn, token string, item models.Item, recipient string,
+	permission models.SharePermission, vaultKey []byte) (models.Share, error) {
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return models.Share{}, err
+	}
+	if payload.Sealed == nil {
+		return models.Share{}, ErrNotSealed
+	}
+	if len(vaultKey) == 0 {
+		return models.Share{}, ErrVaultKeyRequired
+	}
+
+	if _, err := h.Sync(ctx, conn, token); err != nil {
+		return models.Share{}, err
+	}
+
+	serverID, err := h.service.ServerID(ctx, token, item.ID)
+	if err != nil {
+		return models.Share{}, err
+	}
+	if serverID == 0 {
+		return models.Share{}, ErrNotSynced
+	}
+
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	publicKey, err := client.GetPublicKey(ctx, &pd.GetPublicKeyRequest{Login: recipient})
+	if err != nil {
+		h.log.Error("failed to get public key", "error", err)
+		return models.Share{}, err
+	}
+	wrappedKey, err := vaultcrypto.ShareKey(vaultKey, payload.Sealed.WrappedKey, publicKey.GetPublicKey())
+	if err != nil {
+		return models.Share{}, err
+	}
+
+	resp, err := client.Share(ctx, &pd.ShareRequest{
+		ItemId:     serverID,
+		Recipient:  recipient,
+		Permission: pd.SharePermission(permission),
+		WrappedKey: wrappedKey,
+	})
+	if err != nil {
+		h.log.Error("failed to share item", "error", err)
+		return models.Share{}, err
+	}
+
+	share := shareFromProto(resp.GetShare())
+	share.ItemID = item.ID
+	return share, nil
+}
+
+// Unshare - закрывает пользователю recipient доступ к записи кэша.
+func (h *Handlers) Unshare(ctx context.Context, conn *grpc.ClientConn, token string, id int64, recipient string) error {
+	serverID, err := h.service.ServerID(ctx, token, id)
+	if err != nil {
+		return err
+	}
+	if serverID == 0 {
+		return ErrNotSynced
+	}
+
+	client := pd.NewVaultServiceClient(conn)
+	_, err = client.Unshare(withToken(ctx, token), &pd.UnshareRequest{ItemId: serverID, Recipient: recipient})
+	if err != nil {
+		h.log.Error("failed to unshare item", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Shares - получатели записи кэша. Запись, которой еще нет на сервере, никому не выдана.
+func (h *Handlers) Shares(ctx context.Context, conn *grpc.ClientConn, token string, id int64) ([]models.Share, error) {
+	serverID, err := h.service.ServerID(ctx, token, id)
+	if err != nil || serverID == 0 {
+		return nil, err
+	}
+
+	client := pd.NewVaultServiceClient(conn)
+	resp, err := client.ListShares(withToken(ctx, token), &pd.ListSharesRequest{ItemId: serverID})
+	if err != nil {
+		h.log.Error("failed to list shares", "error", err)
+		return nil, err
+	}
+
+	shares := make([]models.Share, 0, len(resp.GetShares()))
+	for _, in := range resp.GetShares() {
+		share := shareFromProto(in)
+		share.ItemID = id
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// SharedWithMe - чужие записи, к которым пользователю открыт доступ, ID - id записи на
+// сервере. В кэш они не попадают. С ключом хранилища записи, зашифрованные на клиенте,
+// расшифровываются закрытым ключом пользователя, без него возвращаются как есть.
+func (h *Handlers) SharedWithMe(ctx context.Context, conn *grpc.ClientConn, token string, vaultKey []byte) ([]models.SharedItem, error) {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	resp, err := client.ListSharedWithMe(ctx, &pd.ListSharedWithMeRequest{})
+	if err != nil {
+		h.log.Error("failed to list shared items", "error", err)
+		return nil, err
+	}
+
+	items := make([]models.SharedItem, 0, len(resp.GetItems()))
+	for _, in := range resp.GetItems() {
+		shared, err := sharedItemFromProto(in)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, shared)
+	}
+
+	if len(vaultKey) == 0 {
+		return items, nil
+	}
+
+	var privateKey []byte
+	for i := range items {
+		if len(items[i].WrappedKey) == 0 {
+			continue
+		}
+		if privateKey == nil {
+			if privateKey, err = h.privateKey(ctx, client, vaultKey); err != nil {
+				return nil, err
+			}
+			defer clear(privateKey)
+		}
+		if items[i].Item, err = openShared(items[i].Item, privateKey, items[i].WrappedKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// UpdateSharedItem - изменяет чужую запись на сервере, ID - id записи на сервере. Данные
+// записи, зашифрованной на клиенте владельца, передаются открытыми и шифруются здесь тем же
+// ключом записи: его копия пользователя открывается закрытым ключом из ключа хранилища.
+func (h *Handlers) UpdateSharedItem(ctx context.Context, conn *grpc.ClientConn, token string, item models.Item,
+	vaultKey []byte) (models.SharedItem, error) {
+	client := pd.NewVaultServiceClient(conn)
+	ctx = withToken(ctx, token)
+
+	item, err := h.sealShared(ctx, client, item, vaultKey)
+	if err != nil {
+		return models.SharedItem{}, err
+	}
+
+	in, err := itemToProto(item, item.ID)
+	if err != nil {
+		return models.SharedItem{}, err
+	}
+
+	resp, err := client.UpdateSharedItem(ctx, &pd.UpdateSharedItemRequest{Item: in})
+	if err != nil {
+		h.log.Error("failed to update shared item", "error", err)
+		return models.SharedItem{}, err
+	}
+
+	return sharedItemFromProto(resp.GetItem())
+}
+
+// sealShared - шифрует данные чужой записи ключом записи, выданным пользователю. Запись
+// на сервере без выданного ключа не зашифрована, ее данные уходят как есть. Открытые данные
+// зашифрованной записи без ключа хранилища не отправляются: ErrVaultKeyRequired.
+func (h *Handlers) sealShared(ctx context.Context, client pd.VaultServiceClient, item models.Item,
+	vaultKey []byte) (models.Item, error) {
+	outgoing, err := models.DecodePayload(item.Payload)
+	if err != nil {
+		return models.Item{}, err
+	}
+	if outgoing.Sealed != nil {
+		return item, nil
+	}
+
+	resp, err := client.ListSharedWithMe(ctx, &pd.ListSharedWithMeRequest{})
+	if err != nil {
+		h.log.Error("failed to list shared items", "error", err)
+		return models.Item{}, err
+	}
+
+	var current models.SharedItem
+	for _, in := range resp.GetItems() {
+		if in.GetItem().GetId() != item.ID {
+			continue
+		}
+		if current, err = sharedItemFromProto(in); err != nil {
+			return models.Item{}, err
+		}
+	}
+	if current.Item.ID == 0 {
+		return models.Item{}, status.Error(codes.NotFound, "shared item not found")
+	}
+
+	payload, err := models.DecodePayload(current.Item.Payload)
+	if err != nil {
+		return models.Item{}, err
+	}
+	if payload.Sealed == nil || len(current.WrappedKey) == 0 {
+		return item, nil
+	}
+	if len(vaultKey) == 0 {
+		return models.Item{}, ErrVaultKeyRequired
+	}
+
+	privateKey, err := h.privateKey(ctx, client, vaultKey)
+	if err != nil {
+		return models.Item{}, err
+	}
+	defer clear(privateKey)
+
+	ciphertext, err := vaultcrypto.ResealShared(privateKey, current.WrappedKey, item.Payload)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	// зашифрованный ключ владельца остается прежним, иначе владелец не прочитает запись
+	item.Payload, err = models.EncodePayload(models.Payload{Sealed: &models.SealedPayload{
+		Ciphertext: ciphertext,
+		WrappedKey: payload.Sealed.WrappedKey,
+	}})
+	if err != nil {
+		return models.Item{}, err
+	}
+	return item, nil
+}
+
+// privateKey - закрытый ключ пользователя, расшифрованный ключом хранилища.
+func (h *Handlers) privateKey(ctx context.Context, client pd.VaultServiceClient, vaultKey []byte) ([]byte, error) {
+	resp, err := client.GetKeyPair(ctx, &pd.GetKeyPairRequest{})
+	if err != nil {
+		h.log.Error("failed to get key pair", "error", err)
+		return nil, err
+	}
+
+	return vaultcrypto.OpenPrivateKey(vaultKey, resp.GetKeyPair().GetPrivateKey())
+}
+
+// openShared - расшифровывает чужую запись: внутри sealed лежит сериализованный
+// models.Payload, ключ записи зашифрован открытым ключом пользователя.
+func openShared(item models.Item, privateKey, wrappedKey []byte) (models.Item, error) {
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil || payload.Sealed == nil {
+		return item, nil
+	}
+
+	plain, err := vaultcrypto.OpenShared(privateKey, payload.Sealed.Ciphertext, wrappedKey)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	item.Payload = plain
+	return item, nil
+}
@@ -0,0 +1,447 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+	"goph-keeper/internal/vaultcrypto"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+)
+
+// memVault - VaultService в памяти, пользователь определяется по токену из metadata.
+type memVault struct {
+	pd.UnimplementedVaultServiceServer
+
+	mu       sync.Mutex
+	logins   map[string]string
+	keyPairs map[string]*pd.KeyPair
+	items    map[int64]*pd.Item
+	owners   map[int64]string
+	shares   []*pd.Share
+}
+
+func newMemVault(logins map[string]string) *memVault {
+	return &memVault{
+		logins:   logins,
+		keyPairs: make(map[string]*pd.KeyPair),
+		items:    make(map[int64]*pd.Item),
+		owners:   make(map[int64]string),
+	}
+}
+
+func (s *memVault) login(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get("authorization") {
+		if login, ok := s.logins[token]; ok {
+			return login, nil
+		}
+	}
+	return "", status.Error(codes.Unauthenticated, "unknown token")
+}
+
+func (s *memVault) CreateItem(ctx context.Context, req *pd.CreateItemRequest) (*pd.CreateItemResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item := proto.Clone(req.GetItem()).(*pd.Item)
+	item.Id = int64(len(s.items) + 1)
+	s.items[item.Id] = item
+	s.owners[item.Id] = login
+	return &pd.CreateItemResponse{Item: item}, nil
+}
+
+func (s *memVault) ListItems(ctx context.Context, req *pd.ListItemsRequest) (*pd.ListItemsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pd.ListItemsResponse{}
+	for id, item := range s.items {
+		if s.owners[id] == login {
+			resp.Items = append(resp.Items, item)
+		}
+	}
+	return resp, nil
+}
+
+func (s *memVault) ListFolders(ctx context.Context, req *pd.ListFoldersRequest) (*pd.ListFoldersResponse, error) {
+	return &pd.ListFoldersResponse{}, nil
+}
+
+func (s *memVault) CreateKeyPair(ctx context.Context, req *pd.CreateKeyPairRequest) (*pd.CreateKeyPairResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.keyPairs[login]; !ok {
+		s.keyPairs[login] = req.GetKeyPair()
+	}
+	return &pd.CreateKeyPairResponse{KeyPair: s.keyPairs[login]}, nil
+}
+
+func (s *memVault) GetKeyPair(ctx context.Context, req *pd.GetKeyPairRequest) (*pd.GetKeyPairResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPair, ok := s.keyPairs[login]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "key pair not found")
+	}
+	return &pd.GetKeyPairResponse{KeyPair: keyPair}, nil
+}
+
+func (s *memVault) GetPublicKey(ctx context.Context, req *pd.GetPublicKeyRequest) (*pd.GetPublicKeyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keyPair, ok := s.keyPairs[req.GetLogin()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "key pair not found")
+	}
+	return &pd.GetPublicKeyResponse{PublicKey: keyPair.GetPublicKey()}, nil
+}
+
+func (s *memVault) Share(ctx context.Context, req *pd.ShareRequest) (*pd.ShareResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.owners[req.GetItemId()] != login {
+		return nil, status.Error(codes.NotFound, "item not found")
+	}
+	if len(req.GetWrappedKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "wrapped key is required")
+	}
+
+	share := &pd.Share{
+		ItemId:     req.GetItemId(),
+		Recipient:  req.GetRecipient(),
+		Permission: req.GetPermission(),
+		WrappedKey: req.GetWrappedKey(),
+	}
+	s.shares = append(s.shares, share)
+	return &pd.ShareResponse{Share: share}, nil
+}
+
+func (s *memVault) ListSharedWithMe(ctx context.Context, req *pd.ListSharedWithMeRequest) (*pd.ListSharedWithMeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pd.ListSharedWithMeResponse{}
+	for _, share := range s.shares {
+		if share.GetRecipient() != login {
+			continue
+		}
+		resp.Items = append(resp.Items, &pd.SharedItem{
+			Item:       s.items[share.GetItemId()],
+			Owner:      s.owners[share.GetItemId()],
+			Permission: share.GetPermission(),
+			WrappedKey: share.GetWrappedKey(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *memVault) UpdateSharedItem(ctx context.Context, req *pd.UpdateSharedItemRequest) (*pd.UpdateSharedItemResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := req.GetItem().GetId()
+	for _, share := range s.shares {
+		if share.GetItemId() != id || share.GetRecipient() != login {
+			continue
+		}
+		if share.GetPermission() != pd.SharePermission_SHARE_PERMISSION_WRITE {
+			return nil, status.Error(codes.PermissionDenied, "read-only share")
+		}
+		s.items[id] = proto.Clone(req.GetItem()).(*pd.Item)
+		return &pd.UpdateSharedItemResponse{Item: &pd.SharedItem{
+			Item:       s.items[id],
+			Owner:      s.owners[id],
+			Permission: share.GetPermission(),
+			WrappedKey: share.GetWrappedKey(),
+		}}, nil
+	}
+	return nil, status.Error(codes.NotFound, "item not found")
+}
+
+// memSync - локальная часть синхронизации: неотправленные записи и их id на сервере.
+type memSync struct {
+	pending   []models.LocalItem
+	serverIDs map[int64]int64
+}
+
+func (s *memSync) PendingChanges(ctx context.Context, token string) ([]models.LocalItem, error) {
+	return s.pending, nil
+}
+
+func (s *memSync) MarkSynced(ctx context.Context, id, serverID, version int64) error {
+	s.serverIDs[id] = serverID
+	s.pending = nil
+	return nil
+}
+
+func (s *memSync) Purge(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (s *memSync) ServerID(ctx context.Context, token string, id int64) (int64, error) {
+	return s.serverIDs[id], nil
+}
+
+func (s *memSync) ApplyRemote(ctx context.Context, token string, items []models.Item) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (s *memSync) ApplyFolders(ctx context.Context, token string, folders []models.Folder) error {
+	return nil
+}
+
+// newTestConn - соединение с memVault через bufconn.
+func newTestConn(t *testing.T, server *memVault) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pd.RegisterVaultServiceServer(s, server)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// sealedItem - запись кэша владельца, зашифрованная ключом хранилища.
+func sealedItem(t *testing.T, vaultKey []byte, payload models.Payload) (models.Item, []byte) {
+	t.Helper()
+
+	plain, err := models.EncodePayload(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ciphertext, wrappedKey, err := vaultcrypto.Seal(vaultKey, plain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sealed, err := models.EncodePayload(models.Payload{Sealed: &models.SealedPayload{
+		Ciphertext: ciphertext,
+		WrappedKey: wrappedKey,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return models.Item{ID: 1, Type: models.ItemTypeLogin, Title: "mail", Payload: sealed}, wrappedKey
+}
+
+func TestShareRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	server := newMemVault(map[string]string{"owner-token": "owner", "recipient-token": "recipient"})
+	conn := newTestConn(t, server)
+
+	ownerKey := vaultcrypto.DeriveKey("owner", "owner-master")
+	recipientKey := vaultcrypto.DeriveKey("recipient", "recipient-master")
+
+	item, wrappedKey := sealedItem(t, ownerKey, models.Payload{Login: &models.LoginPayload{
+		Resource: "mail.example.com",
+		Login:    "owner",
+		Password: "hunter2",
+	}})
+	ownerSync := &memSync{
+		pending:   []models.LocalItem{{Item: item, Version: 1}},
+		serverIDs: make(map[int64]int64),
+	}
+	owner := NewHandlers(log, ownerSync)
+	recipient := NewHandlers(log, &memSync{serverIDs: make(map[int64]int64)})
+
+	if err := owner.EnsureKeyPair(ctx, conn, "owner-token", ownerKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := recipient.EnsureKeyPair(ctx, conn, "recipient-token", recipientKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// владелец: запись уходит на сервер при синхронизации, ключ записи - получателю
+	if _, err := owner.Share(ctx, conn, "owner-token", item, "recipient", models.SharePermissionWrite, ownerKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	serverID := ownerSync.serverIDs[item.ID]
+	stored, err := protojson.Marshal(server.items[serverID])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(stored, []byte("hunter2")) || bytes.Contains(stored, []byte("mail.example.com")) {
+		t.Errorf("server holds a readable copy: %s", stored)
+	}
+
+	// получатель: ключ записи открывается его закрытым ключом
+	shared, err := recipient.SharedWithMe(ctx, conn, "recipient-token", recipientKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(shared) != 1 {
+		t.Fatalf("expected 1 shared item, got %d", len(shared))
+	}
+	payload, err := models.DecodePayload(shared[0].Item.Payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.Login == nil || payload.Login.Password != "hunter2" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	// чужой мастер-пароль закрытый ключ не открывает
+	if _, err := recipient.SharedWithMe(ctx, conn, "recipient-token", ownerKey); !errors.Is(err, vaultcrypto.ErrDecryptFailed) {
+		t.Errorf("expected ErrDecryptFailed, got %v", err)
+	}
+
+	// получатель меняет запись, владелец читает изменение своим ключом
+	payload.Login.Password = "changed"
+	edited := shared[0].Item
+	if edited.Payload, err = models.EncodePayload(payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// без ключа хранилища открытые данные на сервер не уходят
+	before := server.items[serverID]
+	if _, err := recipient.UpdateSharedItem(ctx, conn, "recipient-token", edited, nil); !errors.Is(err, ErrVaultKeyRequired) {
+		t.Fatalf("expected ErrVaultKeyRequired, got %v", err)
+	}
+	if server.items[serverID] != before {
+		t.Fatal("the server received an update without the vault key")
+	}
+
+	if _, err := recipient.UpdateSharedItem(ctx, conn, "recipient-token", edited, recipientKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := itemFromProto(server.items[serverID])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sealed, err := models.DecodePayload(updated.Payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sealed.Sealed == nil || !bytes.Equal(sealed.Sealed.WrappedKey, wrappedKey) {
+		t.Fatalf("expected item sealed with the owner's wrapped key, got %+v", sealed)
+	}
+	plain, err := vaultcrypto.Open(ownerKey, sealed.Sealed.Ciphertext, sealed.Sealed.WrappedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, err = models.DecodePayload(plain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.Login == nil || payload.Login.Password != "changed" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestShare_Rejected(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	server := newMemVault(map[string]string{"owner-token": "owner"})
+	conn := newTestConn(t, server)
+
+	ownerKey := vaultcrypto.DeriveKey("owner", "owner-master")
+	sealed, _ := sealedItem(t, ownerKey, models.Payload{Note: &models.NotePayload{Text: "secret"}})
+	plain, err := models.EncodePayload(models.Payload{Note: &models.NotePayload{Text: "secret"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		item     models.Item
+		vaultKey []byte
+		err      error
+	}{
+		{
+			name: "plain_item",
+			item: models.Item{ID: 1, Type: models.ItemTypeNote, Title: "note", Payload: plain},
+			err:  ErrNotSealed,
+		},
+		{
+			name:     "plain_item_with_key",
+			item:     models.Item{ID: 1, Type: models.ItemTypeNote, Title: "note", Payload: plain},
+			vaultKey: ownerKey,
+			err:      ErrNotSealed,
+		},
+		{
+			name: "without_vault_key",
+			item: sealed,
+			err:  ErrVaultKeyRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandlers(log, &memSync{
+				pending:   []models.LocalItem{{Item: tt.item, Version: 1}},
+				serverIDs: make(map[int64]int64),
+			})
+
+			_, err := h.Share(ctx, conn, "owner-token", tt.item, "recipient", models.SharePermissionRead, tt.vaultKey)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if len(server.items) != 0 {
+				t.Errorf("expected nothing sent to the server, got %d items", len(server.items))
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package vault
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+)
+
+// Trash - записи корзины на сервере, ID - id записи на сервере. В кэше их нет:
+// синхронизация удаляет их по надгробиям.
+func (h *Handlers) Trash(ctx context.Context, conn *grpc.ClientConn, token string) ([]models.Item, error) {
+	client := pd.NewVaultServiceClient(conn)
+	resp, err := client.ListTrash(withToken(ctx, token), &pd.ListTrashRequest{})
+	if err != nil {
+		h.log.Error("failed to list trash", "error", err)
+		return nil, err
+	}
+
+	items := make([]models.Item, 0, len(resp.GetItems()))
+	for _, in := range resp.GetItems() {
+		item, err := itemFromProto(in)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// RestoreItem - возвращает запись из корзины на сервере и забирает ее в кэш синхронизацией.
+func (h *Handlers) RestoreItem(ctx context.Context, conn *grpc.ClientConn, token string, serverID int64) (models.SyncResult, error) {
+	client := pd.NewVaultServiceClient(conn)
+	if _, err := client.RestoreItem(withToken(ctx, token), &pd.RestoreItemRequest{Id: serverID}); err != nil {
+		h.log.Error("failed to restore item", "error", err)
+		return models.SyncResult{}, err
+	}
+
+	return h.Sync(ctx, conn, token)
+}
+
+// PurgeItem - окончательно удаляет запись из корзины на сервере.
+func (h *Handlers) PurgeItem(ctx context.Context, conn *grpc.ClientConn, token string, serverID int64) error {
+	client := pd.NewVaultServiceClient(conn)
+	if _, err := client.PurgeItem(withToken(ctx, token), &pd.PurgeItemRequest{Id: serverID}); err != nil {
+		h.log.Error("failed to purge item", "error", err)
+		return err
+	}
+
+	return nil
+}
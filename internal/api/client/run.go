@@ -4,27 +4,42 @@ import (
 	"context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"goph-keeper/internal/agent"
 	"goph-keeper/internal/api/client/cli"
+	"goph-keeper/internal/api/client/commands"
 	auth2 "goph-keeper/internal/api/client/handlers/auth"
 	"goph-keeper/internal/api/client/handlers/save"
+	"goph-keeper/internal/api/client/handlers/vault"
+	"goph-keeper/internal/breach"
+	"goph-keeper/internal/clipboard"
+	"goph-keeper/internal/redact"
+	"goph-keeper/internal/services/client/audit_client"
 	"goph-keeper/internal/services/client/auth_client"
 	"goph-keeper/internal/services/client/binary_data_client"
 	"goph-keeper/internal/services/client/cards_client"
 	"goph-keeper/internal/services/client/credentials_client"
 	"goph-keeper/internal/services/client/get_all_data"
+	"goph-keeper/internal/services/client/items_client"
+	"goph-keeper/internal/services/client/sync_client"
 	"goph-keeper/internal/services/client/text_data_client"
 	"goph-keeper/internal/storage/sqlite"
 	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 )
 
-const host = "localhost:8081"
+// RunClient - запускает клиент. Без подкоманды открывается TUI, иначе выполняется
+// неинтерактивная команда. Возвращает код завершения процесса.
+func RunClient(args []string) (int, error) {
+	flags := NewFlags()
+	args = flags.Parse(args)
 
-func RunClient() error {
-	// Создаем или открываем файл
-	file, err := os.Create("logfile.log")
+	// Создаем или открываем файл, команды дописывают в него
+	file, err := os.OpenFile(filepath.Join(flags.DataDir, "logfile.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 	if err != nil {
-		panic(err)
+		return commands.ExitError, err
 	}
 	defer func(file *os.File) {
 		err := file.Close()
@@ -34,12 +49,15 @@ func RunClient() error {
 	}(file)
 
 	// Настраиваем slog на запись в файл
-	log := slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	log := slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{
+		Level:       slog.LevelInfo,
+		ReplaceAttr: redact.ReplaceAttr,
+	}))
 
 	// Подключение к базе
-	db, err := sqlite.NewSqlStorage(log)
+	db, err := sqlite.NewSqlStorage(log, flags.DataDir)
 	if err != nil {
-		return err
+		return commands.ExitError, err
 	}
 
 	// Инициализируем сервисы
@@ -49,13 +67,27 @@ func RunClient() error {
 	newServiceBinaryData := binary_data_client.NewService(log, db)
 	newServiceCard := cards_client.NewService(log, db)
 	newServiceGet := get_all_data.NewService(log, db)
+	newServiceItems := items_client.NewService(log, db)
+	newServiceSync := sync_client.NewService(log, db)
+
+	// Проверка паролей по базе утечек включается флагом -hibp
+	var newBreachChecker *breach.Checker
+	if flags.BreachSource != "" {
+		source, err := breach.NewSource(flags.BreachSource)
+		if err != nil {
+			log.Error("failed to open breach source", "error", err)
+			return commands.ExitError, err
+		}
+		newBreachChecker = breach.NewChecker(source)
+	}
+	newServiceAudit := audit_client.NewService(log, db, newBreachChecker)
 
 	conn, err := grpc.Dial(
-		host,
+		flags.Addr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Error("failed to connect client", "error", err)
-		return err
+		return commands.ExitError, err
 	}
 
 	defer func(conn *grpc.ClientConn) {
@@ -65,23 +97,39 @@ func RunClient() error {
 		}
 	}(conn)
 
+	newAuthHandler := auth2.NewHandlers(log, newServiceAuth)
+	newVaultHandler := vault.NewHandlers(log, newServiceSync)
+
+	// Неинтерактивные команды
+	if len(args) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		newCommands := commands.New(log, newAuthHandler, newServiceGet, newServiceItems, newVaultHandler, agent.NewClient(agent.SocketPath()), conn)
+		return newCommands.Run(ctx, args), nil
+	}
+
 	//Инициализация воркеров пока заглушен
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	newAuthHandler := auth2.NewHandlers(log, newServiceAuth)
 	newSaveHandler := save.NewHandlers(log, newServiceCredentials, newServiceTextData, newServiceBinaryData, newServiceCard)
 
+	// Буфер обмена пишет OSC 52 напрямую в терминал
+	tty := clipboard.OpenTerminal()
+	defer tty.Close()
+	newClipboard := clipboard.New(tty, flags.ClipboardTimeout)
+
 	// Инициализация интерфейса CLI
-	newCLI := cli.NewCLI(log, newAuthHandler, newSaveHandler, newServiceGet, conn)
+	newCLI := cli.NewCLI(log, newAuthHandler, newSaveHandler, newServiceGet, newServiceItems, newClipboard, newServiceAudit, newBreachChecker, agent.NewClient(agent.SocketPath()), newVaultHandler, newVaultHandler, conn)
 
 	// Запуск интерфейса CLI
 
 	newCLI.RunCLI(ctx)
 
 	// Инициализация воркера
-	//newWorker := workers.NewWorker(nil)
+	//newWorker := workers.NewWorker(nil, time.Second)
 
 	//go newWorker.Run(ctx)
-	return nil
+	return commands.ExitOK, nil
 }
@@ -1,18 +1,26 @@
 package service
 
 import (
+	"errors"
 	"flag"
 	"github.com/joho/godotenv"
 	"log/slog"
 	"os"
+	"time"
+)
+
+var (
+	ErrInvalidTrashRetention = errors.New("-trash-retention must be positive")
 )
 
 type Flags struct {
-	log          *slog.Logger
-	Repo         string
-	AddrGRPC     string
-	TokenSalt    []byte
-	PasswordSalt []byte
+	log            *slog.Logger
+	Repo           string
+	AddrGRPC       string
+	Revisions      int
+	TrashRetention time.Duration
+	TokenSalt      []byte
+	PasswordSalt   []byte
 }
 
 func NewFlags(log *slog.Logger) *Flags {
@@ -21,14 +29,26 @@ func NewFlags(log *slog.Logger) *Flags {
 	}
 }
 
-func (f *Flags) Parse() {
+func (f *Flags) Parse() error {
 	f.parsFlags()
 	f.initSaltFromEnv()
+	return f.validate()
+}
+
+// validate - проверяет значения флагов. Срок хранения в корзине 0 или меньше означал бы,
+// что очистка удаляет каждую запись корзины в течение часа, и вернуть ее было бы нельзя.
+func (f *Flags) validate() error {
+	if f.TrashRetention <= 0 {
+		return ErrInvalidTrashRetention
+	}
+	return nil
 }
 
 func (f *Flags) parsFlags() {
 	flag.StringVar(&f.Repo, "repo", "2", "1 - memory, 2 - postgres")
 	flag.StringVar(&f.AddrGRPC, "addr", ":8081", "gRPC address")
+	flag.IntVar(&f.Revisions, "revisions", 20, "how many latest revisions to keep per item, 0 - unlimited")
+	flag.DurationVar(&f.TrashRetention, "trash-retention", 30*24*time.Hour, "how long deleted items stay in the trash, must be positive")
 }
 
 func (f *Flags) initSaltFromEnv() {
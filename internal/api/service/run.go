@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"google.golang.org/grpc"
 	_ "google.golang.org/grpc/encoding/gzip"
 	handlerAuth "goph-keeper/internal/grpc/auth"
@@ -9,13 +10,14 @@ import (
 	handlerCredentials "goph-keeper/internal/grpc/credentials"
 	handlerRegister "goph-keeper/internal/grpc/register"
 	handlerTextData "goph-keeper/internal/grpc/text_data"
+	handlerVault "goph-keeper/internal/grpc/vault"
+	"goph-keeper/internal/middleware"
 	pd "goph-keeper/internal/proto/v1"
+	pd2 "goph-keeper/internal/proto/v2"
 	serviceAuth "goph-keeper/internal/services/server/auth"
-	binaryData "goph-keeper/internal/services/server/binary_data"
-	"goph-keeper/internal/services/server/cards"
-	"goph-keeper/internal/services/server/credentials"
-	textData "goph-keeper/internal/services/server/text_data"
+	serviceVault "goph-keeper/internal/services/server/vault"
 	"goph-keeper/internal/storage/postgresql"
+	"goph-keeper/internal/workers"
 	"log/slog"
 	"net"
 	"os"
@@ -24,6 +26,9 @@ import (
 	"time"
 )
 
+// trashPurgeInterval - как часто сервер удаляет записи с истекшим сроком хранения в корзине.
+const trashPurgeInterval = time.Hour
+
 func Run(log *slog.Logger) error {
 	const op = "run.service.app"
 
@@ -31,7 +36,10 @@ func Run(log *slog.Logger) error {
 
 	// Парсим флаги
 	flags := NewFlags(log)
-	flags.Parse()
+	if err := flags.Parse(); err != nil {
+		log.Error("invalid flags", "error", err)
+		return err
+	}
 
 	// Инициализация подключения к базе данных
 	db, err := postgresql.NewPostgresql(log)
@@ -55,21 +63,29 @@ func Run(log *slog.Logger) error {
 		log,
 		db,
 	)
-	newServiceCredentials := credentials.NewService(log, db)
-	newServiceTextData := textData.NewService(log, db)
-	newServiceBinaryData := binaryData.NewService(log, db)
-	newServiceCards := cards.NewServiceCards(log, db)
+	newServiceVault := serviceVault.NewService(log, db, flags.Revisions)
 
 	// Создаем grpc
 	registerUser := handlerRegister.NewHandlers(log, newServiceAuth)
 	authUser := handlerAuth.NewHandlers(log, newServiceAuth)
-	postCredentials := handlerCredentials.NewHandlers(log, newServiceCredentials)
-	postTextData := handlerTextData.NewHandlers(log, newServiceTextData)
-	postBinaryData := handlerBinaryData.NewHandlers(log, newServiceBinaryData)
-	postCards := handlerCards.NewHandlers(log, newServiceCards)
+	vault := handlerVault.NewHandlers(log, newServiceVault)
+
+	// Ручки v1 оставлены для совместимости и сохраняют данные через vault
+	postCredentials := handlerCredentials.NewHandlers(log, vault)
+	postTextData := handlerTextData.NewHandlers(log, vault)
+	postBinaryData := handlerBinaryData.NewHandlers(log, vault)
+	postCards := handlerCards.NewHandlers(log, vault)
+
+	// Проверяем токен у всех методов, кроме регистрации и авторизации
+	authMiddleware := middleware.NewMiddleware(log, newServiceAuth)
 
 	// Создаем GRPC-сервер
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(authMiddleware.UnaryAuthInterceptor(
+			pd.Register_Register_FullMethodName,
+			pd.Auth_Auth_FullMethodName,
+		)),
+	)
 
 	// Регистрируем goph-keeper в GRPC-сервере
 	pd.RegisterRegisterServer(grpcServer, registerUser)
@@ -78,6 +94,7 @@ func Run(log *slog.Logger) error {
 	pd.RegisterPostTextDataServer(grpcServer, postTextData)
 	pd.RegisterPostBinaryDataServer(grpcServer, postBinaryData)
 	pd.RegisterPostCardsServer(grpcServer, postCards)
+	pd2.RegisterVaultServiceServer(grpcServer, vault)
 
 	go func() {
 		listener, err := net.Listen("tcp", flags.AddrGRPC)
@@ -95,6 +112,15 @@ func Run(log *slog.Logger) error {
 		return
 	}()
 
+	// Фоновая очистка корзины
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeWorker := workers.NewWorker(serviceVault.NewTrashPurger(log, db, flags.TrashRetention), trashPurgeInterval)
+	purgeDone := make(chan struct{})
+	go func() {
+		purgeWorker.Run(purgeCtx)
+		close(purgeDone)
+	}()
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 
@@ -102,6 +128,9 @@ func Run(log *slog.Logger) error {
 
 	grpcServer.GracefulStop()
 
+	stopPurge()
+	<-purgeDone
+
 	log.Info("application stop")
 
 	return nil
@@ -0,0 +1,155 @@
+// Package audit - анализ надежности сохраненных паролей: слабые, повторяющиеся и старые пароли.
+package audit
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Значения по умолчанию для Options.
+const (
+	DefaultMinEntropy = 50
+	DefaultMaxAgeDays = 180
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsData string
+
+// commonPasswords - самые распространенные пароли и их ранг в списке.
+var commonPasswords = func() map[string]int {
+	m := make(map[string]int)
+	for i, p := range strings.Fields(commonPasswordsData) {
+		m[p] = i + 1
+	}
+	return m
+}()
+
+// Entry - учетные данные, которые проверяет анализатор.
+type Entry struct {
+	ItemID    int64
+	Title     string
+	Resource  string
+	Login     string
+	Password  string
+	UpdatedAt time.Time
+	// BreachCount - сколько раз пароль встречался в утечках, 0 - не найден или не проверялся.
+	BreachCount int
+}
+
+// Options - пороги анализа.
+type Options struct {
+	MinEntropy float64
+	MaxAgeDays int
+}
+
+// DefaultOptions - пороги анализа по умолчанию.
+var DefaultOptions = Options{
+	MinEntropy: DefaultMinEntropy,
+	MaxAgeDays: DefaultMaxAgeDays,
+}
+
+// Finding - проблемная запись. Пароль в отчет не попадает.
+type Finding struct {
+	ItemID   int64    `json:"item_id"`
+	Title    string   `json:"title"`
+	Resource string   `json:"resource,omitempty"`
+	Login    string   `json:"login,omitempty"`
+	Entropy  float64  `json:"entropy"`
+	AgeDays  int      `json:"age_days"`
+	Breaches int      `json:"breaches,omitempty"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+// ReuseGroup - записи с одинаковым паролем.
+type ReuseGroup struct {
+	Count int       `json:"count"`
+	Items []Finding `json:"items"`
+}
+
+// Report - результат анализа.
+type Report struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Total       int          `json:"total"`
+	MinEntropy  float64      `json:"min_entropy"`
+	MaxAgeDays  int          `json:"max_age_days"`
+	Weak        []Finding    `json:"weak"`
+	Reused      []ReuseGroup `json:"reused"`
+	Old         []Finding    `json:"old"`
+	// BreachChecked - пароли проверены по базе утечек, иначе список Breached пуст.
+	BreachChecked bool      `json:"breach_checked"`
+	Breached      []Finding `json:"breached"`
+}
+
+// Analyze - проверяет учетные данные и собирает отчет.
+func Analyze(entries []Entry, now time.Time, opts Options) Report {
+	report := Report{
+		GeneratedAt: now,
+		Total:       len(entries),
+		MinEntropy:  opts.MinEntropy,
+		MaxAgeDays:  opts.MaxAgeDays,
+		Weak:        []Finding{},
+		Reused:      []ReuseGroup{},
+		Old:         []Finding{},
+		Breached:    []Finding{},
+	}
+
+	// группы считаем по хешу, чтобы не держать пароли ключами map
+	groups := make(map[[sha256.Size]byte][]Finding)
+	var order [][sha256.Size]byte
+
+	for _, e := range entries {
+		if e.Password == "" {
+			continue
+		}
+
+		score := Estimate(e.Password)
+		f := Finding{
+			ItemID:   e.ItemID,
+			Title:    e.Title,
+			Resource: e.Resource,
+			Login:    e.Login,
+			Entropy:  score.Entropy,
+			AgeDays:  int(now.Sub(e.UpdatedAt).Hours() / 24),
+			Breaches: e.BreachCount,
+		}
+
+		if score.Entropy < opts.MinEntropy || score.Common {
+			weak := f
+			weak.Reasons = score.Reasons
+			if len(weak.Reasons) == 0 {
+				weak.Reasons = []string{"low entropy"}
+			}
+			report.Weak = append(report.Weak, weak)
+		}
+
+		if e.BreachCount > 0 {
+			report.Breached = append(report.Breached, f)
+		}
+
+		if opts.MaxAgeDays > 0 && f.AgeDays > opts.MaxAgeDays {
+			report.Old = append(report.Old, f)
+		}
+
+		key := sha256.Sum256([]byte(e.Password))
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], f)
+	}
+
+	for _, key := range order {
+		if items := groups[key]; len(items) > 1 {
+			report.Reused = append(report.Reused, ReuseGroup{Count: len(items), Items: items})
+		}
+	}
+
+	sort.SliceStable(report.Weak, func(i, j int) bool { return report.Weak[i].Entropy < report.Weak[j].Entropy })
+	sort.SliceStable(report.Reused, func(i, j int) bool { return report.Reused[i].Count > report.Reused[j].Count })
+	sort.SliceStable(report.Breached, func(i, j int) bool { return report.Breached[i].Breaches > report.Breached[j].Breaches })
+	sort.SliceStable(report.Old, func(i, j int) bool { return report.Old[i].AgeDays > report.Old[j].AgeDays })
+
+	return report
+}
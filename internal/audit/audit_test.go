@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		password string
+		weak     bool
+		reason   string
+	}{
+		{"password", true, "common password"},
+		{"P@ssw0rd", true, "common password"},
+		{"qwerty123", true, "common password"},
+		{"abcdefgh1234", true, "sequence of characters"},
+		{"aaaaaaaaaaaa", true, "repeated characters"},
+		{"Kitten2023", true, "contains a year"},
+		{"Summer2023", true, "common password"},
+		{"k7#Vq9!mZp2$Lx4w", false, ""},
+	}
+	for _, tt := range tests {
+		score := Estimate(tt.password)
+		weak := score.Common || score.Entropy < DefaultMinEntropy
+		if weak != tt.weak {
+			t.Errorf("%q: weak = %v (entropy %.1f), want %v", tt.password, weak, score.Entropy, tt.weak)
+		}
+		if tt.reason != "" && !contains(score.Reasons, tt.reason) {
+			t.Errorf("%q: reasons %v do not contain %q", tt.password, score.Reasons, tt.reason)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	now := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	strong := "k7#Vq9!mZp2$Lx4w"
+
+	entries := []Entry{
+		{ItemID: 1, Title: "mail", Password: strong, UpdatedAt: now.AddDate(0, 0, -10)},
+		{ItemID: 2, Title: "bank", Password: strong, UpdatedAt: now.AddDate(0, 0, -400)},
+		{ItemID: 3, Title: "forum", Password: "123456", UpdatedAt: now, BreachCount: 37359195},
+		{ItemID: 4, Title: "empty"},
+	}
+
+	report := Analyze(entries, now, DefaultOptions)
+
+	if report.Total != 4 {
+		t.Errorf("unexpected total %d", report.Total)
+	}
+	if len(report.Weak) != 1 || report.Weak[0].ItemID != 3 {
+		t.Errorf("unexpected weak: %+v", report.Weak)
+	}
+	if len(report.Reused) != 1 || report.Reused[0].Count != 2 {
+		t.Errorf("unexpected reused: %+v", report.Reused)
+	}
+	if len(report.Breached) != 1 || report.Breached[0].ItemID != 3 || report.Breached[0].Breaches != 37359195 {
+		t.Errorf("unexpected breached: %+v", report.Breached)
+	}
+	if len(report.Old) != 1 || report.Old[0].ItemID != 2 || report.Old[0].AgeDays != 400 {
+		t.Errorf("unexpected old: %+v", report.Old)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
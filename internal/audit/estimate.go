@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Score - оценка надежности пароля.
+type Score struct {
+	Entropy float64
+	Common  bool
+	Reasons []string
+}
+
+// keyboardRows - ряды клавиатуры и алфавит для поиска последовательностей.
+var keyboardRows = []string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"01234567890",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik9ol0p",
+}
+
+// yearPattern - годы 1900-2099 внутри пароля.
+var yearPattern = regexp.MustCompile(`(19|20)\d\d`)
+
+// leet - обратная замена частых подстановок символов.
+var leet = strings.NewReplacer("@", "a", "4", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t")
+
+// Estimate - оценивает энтропию пароля с учетом типичных шаблонов: распространенные
+// пароли, повторы, последовательности символов и клавиатуры, годы.
+func Estimate(password string) Score {
+	var score Score
+
+	lower := strings.ToLower(password)
+	base := strings.TrimRightFunc(lower, unicode.IsDigit)
+	for _, candidate := range []string{lower, leet.Replace(lower), base, leet.Replace(base)} {
+		if rank, ok := commonPasswords[candidate]; ok && candidate != "" {
+			score.Common = true
+			score.Entropy = math.Log2(float64(rank + 1))
+			score.Reasons = append(score.Reasons, "common password")
+			return score
+		}
+	}
+
+	pool := poolSize(password)
+	runes := []rune(lower)
+
+	var entropy float64
+	repeats, sequences := 0, 0
+	for i, r := range runes {
+		switch {
+		case i > 0 && r == runes[i-1]:
+			repeats++
+			entropy += 1
+		case i > 0 && isSequence(runes[i-1], r):
+			sequences++
+			entropy += 1
+		default:
+			entropy += math.Log2(float64(pool))
+		}
+	}
+
+	if repeats >= 2 {
+		score.Reasons = append(score.Reasons, "repeated characters")
+	}
+	if sequences >= 2 {
+		score.Reasons = append(score.Reasons, "sequence of characters")
+	}
+	if yearPattern.MatchString(password) {
+		// год угадывается примерно из двухсот вариантов вместо 10^4
+		entropy -= 4*math.Log2(float64(pool)) - math.Log2(200)
+		score.Reasons = append(score.Reasons, "contains a year")
+	}
+	if len(runes) < 8 {
+		score.Reasons = append(score.Reasons, "too short")
+	}
+
+	score.Entropy = math.Max(entropy, 0)
+	return score
+}
+
+// poolSize - размер алфавита по классам символов, встречающимся в пароле.
+func poolSize(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	if lower {
+		pool += 26
+	}
+	if upper {
+		pool += 26
+	}
+	if digit {
+		pool += 10
+	}
+	if other {
+		pool += 33
+	}
+	return max(pool, 1)
+}
+
+// isSequence - символы b следует за a (или предшествует ему) в алфавите или ряду клавиатуры.
+func isSequence(a, b rune) bool {
+	for _, row := range keyboardRows {
+		i := strings.IndexRune(row, a)
+		if i < 0 {
+			continue
+		}
+		if (i+1 < len(row) && rune(row[i+1]) == b) || (i > 0 && rune(row[i-1]) == b) {
+			return true
+		}
+	}
+	return false
+}
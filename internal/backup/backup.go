@@ -0,0 +1,232 @@
+// Package backup - зашифрованная резервная копия всех записей хранилища.
+//
+// Файл - JSON-конверт с версией формата, параметрами Argon2id и шифротекстом. Ключ
+// получается из парольной фразы со случайной солью, содержимое (gzip JSON со списком
+// записей) шифруется vaultcrypto.Seal.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/vaultcrypto"
+	"io"
+	"slices"
+	"time"
+)
+
+const (
+	// Format - значение поля format конверта.
+	Format = "goph-keeper-backup"
+	// Version - текущая версия формата.
+	Version = 1
+
+	saltSize = 16
+
+	// пределы параметров Argon2id из файла: копия приходит извне, и без них
+	// подобранный memory или time исчерпает память или время до проверки фразы
+	maxKDFTime    = 10
+	maxKDFMemory  = 256 * 1024
+	minKDFMemory  = 8 * 1024
+	maxKDFThreads = 16
+	maxSaltSize   = 64
+)
+
+var (
+	ErrInvalidBackup      = errors.New("not a goph-keeper backup")
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	ErrWrongPassphrase    = errors.New("wrong passphrase or damaged backup")
+	ErrEmptyPassphrase    = errors.New("passphrase is empty")
+)
+
+// kdfParams - параметры Argon2id. Хранятся в файле, чтобы их можно было менять
+// без потери совместимости со старыми копиями.
+type kdfParams struct {
+	Algorithm string `json:"algorithm"`
+	Salt      []byte `json:"salt"`
+	Time      uint32 `json:"time"`
+	Memory    uint32 `json:"memory"`
+	Threads   uint8  `json:"threads"`
+}
+
+// defaultKDF - параметры для новых копий.
+var defaultKDF = kdfParams{Algorithm: "argon2id", Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// envelope - содержимое файла копии.
+type envelope struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	KDF        kdfParams `json:"kdf"`
+	Ciphertext []byte    `json:"ciphertext"`
+	WrappedKey []byte    `json:"wrapped_key"`
+}
+
+// Backup - расшифрованное содержимое копии.
+type Backup struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Items     []Item    `json:"items"`
+}
+
+// Item - запись в копии. Данные записи (в том числе файлы binary) хранятся как есть,
+// зашифрованные на клиенте записи остаются зашифрованными ключом хранилища.
+type Item struct {
+	Type      models.ItemType `json:"type"`
+	Title     string          `json:"title"`
+	Tags      []string        `json:"tags,omitempty"`
+	Favorite  bool            `json:"favorite,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// FromModel - запись копии из записи хранилища.
+func FromModel(item models.Item) Item {
+	return Item{
+		Type:      item.Type,
+		Title:     item.Title,
+		Tags:      item.Tags,
+		Favorite:  item.Favorite,
+		Payload:   json.RawMessage(item.Payload),
+		CreatedAt: item.CreatedAt.UTC(),
+		UpdatedAt: item.UpdatedAt.UTC(),
+	}
+}
+
+// Model - запись хранилища из записи копии.
+func (i Item) Model() models.Item {
+	return models.Item{
+		Type:      i.Type,
+		Title:     i.Title,
+		Tags:      i.Tags,
+		Favorite:  i.Favorite,
+		Payload:   []byte(i.Payload),
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
+
+// Write - шифрует и записывает копию.
+func Write(w io.Writer, b Backup, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return ErrEmptyPassphrase
+	}
+	b.Version = Version
+
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	kdf := defaultKDF
+	kdf.Salt = make([]byte, saltSize)
+	if _, err := rand.Read(kdf.Salt); err != nil {
+		return err
+	}
+	key := kdf.key(passphrase)
+	defer clear(key)
+
+	ciphertext, wrapped, err := vaultcrypto.Seal(key, plain.Bytes())
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(envelope{
+		Format:     Format,
+		Version:    Version,
+		CreatedAt:  b.CreatedAt.UTC(),
+		KDF:        kdf,
+		Ciphertext: ciphertext,
+		WrappedKey: wrapped,
+	})
+}
+
+// Read - читает и расшифровывает копию.
+func Read(r io.Reader, passphrase []byte) (Backup, error) {
+	var env envelope
+	if err := json.NewDecoder(r).Decode(&env); err != nil || env.Format != Format {
+		return Backup{}, ErrInvalidBackup
+	}
+	if env.Version < 1 || env.Version > Version {
+		return Backup{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, env.Version)
+	}
+	if env.KDF.Algorithm != defaultKDF.Algorithm {
+		return Backup{}, fmt.Errorf("%w: unknown key derivation %q", ErrInvalidBackup, env.KDF.Algorithm)
+	}
+	if err := env.KDF.validate(); err != nil {
+		return Backup{}, err
+	}
+
+	key := env.KDF.key(passphrase)
+	defer clear(key)
+
+	plain, err := vaultcrypto.Open(key, env.Ciphertext, env.WrappedKey)
+	if err != nil {
+		return Backup{}, ErrWrongPassphrase
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return Backup{}, fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+	}
+	var b Backup
+	if err := json.NewDecoder(zr).Decode(&b); err != nil {
+		return Backup{}, fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+	}
+	return b, nil
+}
+
+// validate - проверяет параметры из файла до вычисления ключа.
+func (k kdfParams) validate() error {
+	switch {
+	case len(k.Salt) < saltSize || len(k.Salt) > maxSaltSize:
+		return fmt.Errorf("%w: salt size %d", ErrInvalidBackup, len(k.Salt))
+	case k.Time < 1 || k.Time > maxKDFTime:
+		return fmt.Errorf("%w: argon2id time %d out of range", ErrInvalidBackup, k.Time)
+	case k.Memory < minKDFMemory || k.Memory > maxKDFMemory:
+		return fmt.Errorf("%w: argon2id memory %d KiB out of range", ErrInvalidBackup, k.Memory)
+	case k.Threads < 1 || k.Threads > maxKDFThreads:
+		return fmt.Errorf("%w: argon2id threads %d out of range", ErrInvalidBackup, k.Threads)
+	}
+	return nil
+}
+
+// key - ключ шифрования копии.
+func (k kdfParams) key(passphrase []byte) []byte {
+	return argon2.IDKey(passphrase, k.Salt, k.Time, k.Memory, k.Threads, vaultcrypto.KeySize)
+}
+
+// Fingerprint - отпечаток содержимого записи: тип, название, метки, избранное и данные.
+// У одинаковых записей отпечатки совпадают независимо от id, дат и форматирования JSON
+// данных, поэтому повторное восстановление копии не создает дубликатов.
+func Fingerprint(item models.Item) string {
+	payload := item.Payload
+	if p, err := models.DecodePayload(item.Payload); err == nil {
+		if canonical, err := models.EncodePayload(p); err == nil {
+			payload = canonical
+		}
+	}
+
+	// nil и пустой список меток - одно и то же
+	tags := append([]string{}, item.Tags...)
+	slices.Sort(tags)
+
+	h := sha256.New()
+	_ = json.NewEncoder(h).Encode([]any{item.Type, item.Title, tags, item.Favorite})
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
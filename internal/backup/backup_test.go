@@ -0,0 +1,136 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"goph-keeper/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testItem(t *testing.T, title string, payload models.Payload) models.Item {
+	t.Helper()
+	data, err := models.EncodePayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return models.Item{ID: 7, Type: payload.Type(), Title: title, Tags: []string{"b", "a"}, Payload: data, CreatedAt: now, UpdatedAt: now}
+}
+
+func TestWriteRead(t *testing.T) {
+	items := []models.Item{
+		testItem(t, "github", models.Payload{Login: &models.LoginPayload{Login: "alice", Password: "pw"}}),
+		testItem(t, "photo", models.Payload{Binary: &models.BinaryPayload{Name: "a.png", Data: []byte{0, 1, 2, 255}}}),
+	}
+	in := Backup{CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	for _, item := range items {
+		in.Items = append(in.Items, FromModel(item))
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, in, []byte("correct horse")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "alice") || strings.Contains(buf.String(), "github") {
+		t.Fatal("backup contains plaintext")
+	}
+
+	out, err := Read(bytes.NewReader(buf.Bytes()), []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Version != Version || len(out.Items) != 2 {
+		t.Fatalf("backup = %+v", out)
+	}
+	for i, item := range out.Items {
+		got := item.Model()
+		want := items[i]
+		want.ID = 0
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("item %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if _, err := Read(bytes.NewReader(buf.Bytes()), []byte("wrong")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: err = %v", err)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	if _, err := Read(strings.NewReader(`{"format": "other"}`), []byte("x")); !errors.Is(err, ErrInvalidBackup) {
+		t.Errorf("err = %v, want ErrInvalidBackup", err)
+	}
+	if _, err := Read(strings.NewReader(`{"format": "goph-keeper-backup", "version": 99}`), []byte("x")); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("err = %v, want ErrUnsupportedVersion", err)
+	}
+	if err := Write(&bytes.Buffer{}, Backup{}, nil); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("err = %v, want ErrEmptyPassphrase", err)
+	}
+}
+
+func TestReadKDFLimits(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Backup{}, []byte("correct horse")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(k *kdfParams)
+	}{
+		{name: "huge memory", mutate: func(k *kdfParams) { k.Memory = 1 << 31 }},
+		{name: "tiny memory", mutate: func(k *kdfParams) { k.Memory = 1 }},
+		{name: "huge time", mutate: func(k *kdfParams) { k.Time = 1 << 20 }},
+		{name: "zero time", mutate: func(k *kdfParams) { k.Time = 0 }},
+		{name: "zero threads", mutate: func(k *kdfParams) { k.Threads = 0 }},
+		{name: "many threads", mutate: func(k *kdfParams) { k.Threads = 255 }},
+		{name: "short salt", mutate: func(k *kdfParams) { k.Salt = []byte{1} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var env envelope
+			if err := json.Unmarshal(buf.Bytes(), &env); err != nil {
+				t.Fatal(err)
+			}
+			tt.mutate(&env.KDF)
+			data, err := json.Marshal(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := Read(bytes.NewReader(data), []byte("correct horse")); !errors.Is(err, ErrInvalidBackup) {
+				t.Errorf("err = %v, want ErrInvalidBackup", err)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := testItem(t, "github", models.Payload{Login: &models.LoginPayload{Login: "alice", Password: "pw"}})
+
+	b := a
+	b.ID = 100
+	b.Tags = []string{"a", "b"}
+	b.UpdatedAt = time.Now()
+	// данные с сервера приходят в protojson с другим форматированием
+	b.Payload = []byte(`{"login": {"password": "pw", "login": "alice"}}`)
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("same content must have the same fingerprint")
+	}
+
+	noTags, emptyTags := a, a
+	noTags.Tags, emptyTags.Tags = nil, []string{}
+	if Fingerprint(noTags) != Fingerprint(emptyTags) {
+		t.Error("nil and empty tags must have the same fingerprint")
+	}
+
+	c := a
+	c.Title = "gitlab"
+	if Fingerprint(a) == Fingerprint(c) {
+		t.Error("different titles must have different fingerprints")
+	}
+}
@@ -0,0 +1,100 @@
+// Package breach - проверка паролей по базе утечек в формате Have I Been Pwned
+// (k-anonymity range): наружу уходит только первые 5 символов SHA-1, а по умолчанию
+// данные читаются с локального диска.
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// prefixLen - длина префикса хеша, по которому запрашивается диапазон.
+const prefixLen = 5
+
+var (
+	ErrInvalidSource = errors.New("invalid breach source")
+	ErrDisabled      = errors.New("breach check is disabled")
+)
+
+// RangeSource - источник диапазонов HIBP. Range возвращает строки "SUFFIX:COUNT"
+// для всех хешей с данным префиксом (35 символов суффикса в верхнем регистре).
+type RangeSource interface {
+	Range(ctx context.Context, prefix string) (io.ReadCloser, error)
+}
+
+// Checker - проверка паролей по источнику диапазонов.
+type Checker struct {
+	source RangeSource
+}
+
+// NewChecker - конструктор проверки.
+func NewChecker(source RangeSource) *Checker {
+	return &Checker{source: source}
+}
+
+// NewSource - создает источник по строке: URL http(s) - сервер диапазонов,
+// каталог - файлы по префиксам (00000.txt ... FFFFF.txt), файл - единый
+// отсортированный файл "HASH:COUNT".
+func NewSource(spec string) (RangeSource, error) {
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		return NewHTTPSource(spec, nil), nil
+	}
+
+	info, err := os.Stat(spec)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSource, err)
+	}
+	if info.IsDir() {
+		return NewDirSource(spec), nil
+	}
+	return NewFileSource(spec), nil
+}
+
+// Check - возвращает, сколько раз пароль встречался в утечках (0 - не найден).
+// Для nil *Checker возвращает ErrDisabled, так проверка отключается без отдельного флага.
+func (c *Checker) Check(ctx context.Context, password string) (int, error) {
+	if c == nil {
+		return 0, ErrDisabled
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLen], hash[prefixLen:]
+
+	rc, err := c.source.Range(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		lineSuffix, count, ok := parseLine(scanner.Text())
+		if ok && strings.EqualFold(lineSuffix, suffix) {
+			return count, nil
+		}
+	}
+
+	return 0, scanner.Err()
+}
+
+// parseLine - разбирает строку "SUFFIX:COUNT".
+func parseLine(line string) (string, int, bool) {
+	suffix, count, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return "", 0, false
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return "", 0, false
+	}
+	return suffix, n, true
+}
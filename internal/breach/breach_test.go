@@ -0,0 +1,119 @@
+package breach
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// hashOf - SHA-1 пароля в верхнем регистре.
+func hashOf(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// breached - пароли тестовой базы и число их появлений в утечках.
+var breached = map[string]int{
+	"password": 9659365,
+	"123456":   37359195,
+	"qwerty":   10556095,
+	"letmein":  561289,
+	"dragon":   1139,
+}
+
+// fullLines - строки "HASH:COUNT", отсортированные по хешу.
+func fullLines() []string {
+	var lines []string
+	for p, n := range breached {
+		lines = append(lines, fmt.Sprintf("%s:%d", hashOf(p), n))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func checkSource(t *testing.T, source RangeSource) {
+	t.Helper()
+	checker := NewChecker(source)
+
+	for p, want := range breached {
+		got, err := checker.Check(context.Background(), p)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", p, err)
+		}
+		if got != want {
+			t.Errorf("%q: count = %d, want %d", p, got, want)
+		}
+	}
+
+	got, err := checker.Check(context.Background(), "k7#Vq9!mZp2$Lx4w")
+	if err != nil || got != 0 {
+		t.Errorf("strong password: count = %d, err = %v", got, err)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	// дополняем базу посторонними хешами, чтобы бинарный поиск шел по большому файлу
+	lines := fullLines()
+	for i := 0; i < 5000; i++ {
+		lines = append(lines, fmt.Sprintf("%s:%d", hashOf(fmt.Sprintf("filler-%d", i)), i+1))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewSource(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSource(t, source)
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	for _, line := range fullLines() {
+		name := filepath.Join(dir, line[:prefixLen]+".txt")
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintln(f, line[prefixLen:])
+		f.Close()
+	}
+
+	source, err := NewSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSource(t, source)
+}
+
+func TestHTTPSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		for _, line := range fullLines() {
+			if strings.HasPrefix(line, prefix) {
+				fmt.Fprintf(w, "%s\r\n", line[prefixLen:])
+			}
+		}
+		// строка дополнения
+		fmt.Fprintf(w, "%s:0\r\n", strings.Repeat("0", 35))
+	}))
+	defer srv.Close()
+
+	source, err := NewSource(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSource(t, source)
+}
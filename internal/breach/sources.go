@@ -0,0 +1,157 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DirSource - каталог с файлами диапазонов по префиксам, как их сохраняет
+// PwnedPasswordsDownloader: <prefix>.txt со строками "SUFFIX:COUNT".
+type DirSource struct {
+	dir string
+}
+
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{dir: dir}
+}
+
+// Range - открывает файл диапазона. Отсутствие файла означает пустой диапазон.
+func (s *DirSource) Range(_ context.Context, prefix string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, prefix+".txt"))
+	if os.IsNotExist(err) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	return f, err
+}
+
+// FileSource - единый файл "HASH:COUNT", отсортированный по хешу. Диапазон ищется
+// бинарным поиском, поэтому файл не загружается в память.
+type FileSource struct {
+	path string
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// Range - возвращает строки "SUFFIX:COUNT" для хешей с данным префиксом.
+func (s *FileSource) Range(_ context.Context, prefix string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	// ищем наименьшее смещение, после которого начинается строка с хешем >= prefix
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		_, line, err := lineAfter(f, mid, size)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" || strings.ToUpper(line[:min(len(line), prefixLen)]) >= prefix {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	start, _, err := lineAfter(f, lo, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(io.NewSectionReader(f, start, size-start))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < prefixLen || !strings.EqualFold(line[:prefixLen], prefix) {
+			break
+		}
+		out.WriteString(line[prefixLen:])
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(&out), nil
+}
+
+// lineAfter - первая строка, начинающаяся не раньше off (для off == 0 - первая строка файла).
+// Возвращает смещение начала строки и ее текст, пустой текст - конец файла.
+func lineAfter(f *os.File, off, size int64) (int64, string, error) {
+	start := off
+	r := bufio.NewReader(io.NewSectionReader(f, max(off-1, 0), size-max(off-1, 0)))
+
+	if off > 0 {
+		// пропускаем остаток строки, в которую попало смещение
+		skipped, err := r.ReadString('\n')
+		if err == io.EOF {
+			return size, "", nil
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		start = off - 1 + int64(len(skipped))
+	}
+
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+	return start, strings.TrimSpace(line), nil
+}
+
+// HTTPSource - сервер диапазонов по протоколу HIBP: GET <base>/range/<prefix>.
+type HTTPSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSource - конструктор источника. Если client == nil, используется клиент с таймаутом.
+func NewHTTPSource(baseURL string, client *http.Client) *HTTPSource {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Range - запрашивает диапазон у сервера. Заголовок Add-Padding скрывает размер
+// ответа; строки дополнения имеют COUNT = 0 и не совпадают ни с одним паролем.
+func (s *HTTPSource) Range(ctx context.Context, prefix string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("breach range server: unexpected status %s", resp.Status)
+	}
+
+	return resp.Body, nil
+}
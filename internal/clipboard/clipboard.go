@@ -0,0 +1,138 @@
+// Package clipboard - копирование текста в буфер обмена терминала escape-последовательностью
+// OSC 52. Работает поверх SSH и не требует X-сервера: буфер меняет сам эмулятор терминала.
+package clipboard
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clipboard - буфер обмена с автоматической очисткой.
+type Clipboard struct {
+	mu      sync.Mutex
+	out     io.Writer
+	timeout time.Duration
+	tmux    bool
+	timer   *time.Timer
+	pending bool
+	onClear func()
+
+	// generation - номер копирования: таймер очищает буфер, только если после
+	// его запуска ничего не копировалось
+	generation uint64
+}
+
+// New - конструктор буфера обмена. Последовательности пишутся в out.
+// Если timeout > 0, буфер очищается через timeout после каждого копирования.
+func New(out io.Writer, timeout time.Duration) *Clipboard {
+	return &Clipboard{
+		out:     out,
+		timeout: timeout,
+		tmux:    os.Getenv("TMUX") != "",
+	}
+}
+
+// OpenTerminal - открывает управляющий терминал, при его отсутствии возвращает stdout.
+func OpenTerminal() io.WriteCloser {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return os.Stdout
+	}
+	return tty
+}
+
+// Timeout - время, через которое буфер очищается.
+func (c *Clipboard) Timeout() time.Duration {
+	return c.timeout
+}
+
+// OnClear - задает функцию, которая вызывается после очистки буфера.
+func (c *Clipboard) OnClear(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onClear = f
+}
+
+// Copy - копирует текст в буфер и перезапускает таймер очистки.
+func (c *Clipboard) Copy(text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.write(text); err != nil {
+		return err
+	}
+	c.pending = true
+	c.generation++
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	if c.timeout > 0 {
+		generation := c.generation
+		c.timer = time.AfterFunc(c.timeout, func() {
+			c.expire(generation)
+		})
+	}
+
+	return nil
+}
+
+// Clear - очищает буфер, если в него что-то копировалось, и вызывает OnClear.
+func (c *Clipboard) Clear() error {
+	c.mu.Lock()
+	return c.clear()
+}
+
+// expire - очистка по таймеру. Stop не отменяет уже сработавший таймер, поэтому
+// устаревший вызов, дождавшийся c.mu после нового копирования, ничего не стирает.
+func (c *Clipboard) expire(generation uint64) {
+	c.mu.Lock()
+	if generation != c.generation {
+		c.mu.Unlock()
+		return
+	}
+	_ = c.clear()
+}
+
+// clear - очищает буфер. Вызывается с захваченным c.mu и освобождает его.
+func (c *Clipboard) clear() error {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if !c.pending {
+		c.mu.Unlock()
+		return nil
+	}
+
+	err := c.write("")
+	c.pending = false
+	onClear := c.onClear
+	c.mu.Unlock()
+
+	if onClear != nil {
+		onClear()
+	}
+	return err
+}
+
+// write - пишет в терминал последовательность установки буфера.
+func (c *Clipboard) write(text string) error {
+	_, err := io.WriteString(c.out, Sequence(text, c.tmux))
+	return err
+}
+
+// Sequence - escape-последовательность OSC 52, записывающая text в буфер обмена.
+// Внутри tmux последовательность оборачивается в DCS passthrough.
+func Sequence(text string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	if !tmux {
+		return seq
+	}
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
@@ -0,0 +1,100 @@
+package clipboard
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer - потокобезопасный буфер: очистка пишет из горутины таймера.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSequence(t *testing.T) {
+	if got := Sequence("secret", false); got != "\x1b]52;c;c2VjcmV0\x07" {
+		t.Errorf("unexpected sequence %q", got)
+	}
+	if got := Sequence("", true); got != "\x1bPtmux;\x1b\x1b]52;c;\x07\x1b\\" {
+		t.Errorf("unexpected tmux sequence %q", got)
+	}
+}
+
+func TestClipboard_AutoClear(t *testing.T) {
+	var out syncBuffer
+	cleared := make(chan struct{})
+
+	c := New(&out, 10*time.Millisecond)
+	c.tmux = false
+	c.OnClear(func() { close(cleared) })
+
+	if err := c.Copy("secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-cleared:
+	case <-time.After(time.Second):
+		t.Fatal("clipboard was not cleared")
+	}
+
+	want := Sequence("secret", false) + Sequence("", false)
+	if got := out.String(); got != want {
+		t.Errorf("unexpected output %q, want %q", got, want)
+	}
+}
+
+func TestClipboard_ClearWithoutCopy(t *testing.T) {
+	var out syncBuffer
+	c := New(&out, 0)
+	c.OnClear(func() { t.Error("OnClear must not be called without copy") })
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "" {
+		t.Errorf("nothing must be written, got %q", out.String())
+	}
+}
+
+func TestClipboard_StaleTimer(t *testing.T) {
+	var out syncBuffer
+	c := New(&out, time.Hour)
+	c.tmux = false
+	defer c.Clear()
+
+	if err := c.Copy("old"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stale := c.generation
+	if err := c.Copy("new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// таймер первого копирования сработал и ждал c.mu, пока шло второе
+	c.expire(stale)
+
+	want := Sequence("old", false) + Sequence("new", false)
+	if got := out.String(); got != want {
+		t.Errorf("stale timer cleared the clipboard: %q, want %q", got, want)
+	}
+
+	c.expire(c.generation)
+	if got := out.String(); got != want+Sequence("", false) {
+		t.Errorf("current timer did not clear the clipboard: %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package fuzzy - нечеткий поиск подпоследовательностью с ранжированием.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Веса составляющих оценки совпадения.
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 8
+	bonusWordStart   = 10
+	bonusPrefix      = 6
+	penaltyGap       = 1
+)
+
+// Candidate - объект поиска: набор полей с весами.
+type Candidate struct {
+	Fields  []string
+	Weights []int
+}
+
+// Match - результат ранжирования: индекс кандидата и его оценка.
+type Match struct {
+	Index int
+	Score int
+}
+
+// Score - оценивает совпадение pattern с text. Символы pattern должны встречаться
+// в text в том же порядке, регистр не учитывается. Пустой pattern совпадает с любым text.
+func Score(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return 0, true
+	}
+
+	runes := []rune(strings.ToLower(text))
+	score := 0
+	last := -1
+	pos := 0
+
+	for len(pattern) > 0 {
+		r, size := utf8.DecodeRuneInString(pattern)
+		pattern = pattern[size:]
+
+		found := -1
+		for i := pos; i < len(runes); i++ {
+			if runes[i] == r {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+
+		score += scoreMatch
+		switch {
+		case found == 0:
+			score += bonusPrefix + bonusWordStart
+		case isSeparator(runes[found-1]):
+			score += bonusWordStart
+		}
+		if last >= 0 {
+			if found == last+1 {
+				score += bonusConsecutive
+			} else {
+				score -= (found - last - 1) * penaltyGap
+			}
+		}
+
+		last = found
+		pos = found + 1
+	}
+
+	return score, true
+}
+
+// Rank - возвращает совпавших кандидатов по убыванию оценки. Оценка кандидата -
+// лучшая оценка среди его полей с учетом веса поля. При равной оценке сохраняется
+// исходный порядок.
+func Rank(pattern string, candidates []Candidate) []Match {
+	var matches []Match
+	for i, c := range candidates {
+		best, ok := 0, false
+		for j, field := range c.Fields {
+			s, matched := Score(pattern, field)
+			if !matched {
+				continue
+			}
+			if j < len(c.Weights) {
+				s *= c.Weights[j]
+			}
+			if !ok || s > best {
+				best, ok = s, true
+			}
+		}
+		if ok {
+			matches = append(matches, Match{Index: i, Score: best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// isSeparator - символ, после которого начинается новое слово.
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
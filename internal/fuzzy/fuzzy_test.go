@@ -0,0 +1,55 @@
+package fuzzy
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		match   bool
+	}{
+		{"", "anything", true},
+		{"gh", "GitHub", true},
+		{"ghb", "github.com", true},
+		{"hg", "github", false},
+		{"mail", "gmail.com", true},
+		{"xyz", "github", false},
+	}
+	for _, tt := range tests {
+		if _, ok := Score(tt.pattern, tt.text); ok != tt.match {
+			t.Errorf("Score(%q, %q) match = %v, want %v", tt.pattern, tt.text, ok, tt.match)
+		}
+	}
+}
+
+func TestScore_Ordering(t *testing.T) {
+	prefix, _ := Score("git", "github")
+	inner, _ := Score("git", "legit")
+	scattered, _ := Score("git", "wagonist")
+
+	if prefix <= inner || inner <= scattered {
+		t.Errorf("unexpected scores: prefix %d, inner %d, scattered %d", prefix, inner, scattered)
+	}
+}
+
+func TestRank(t *testing.T) {
+	candidates := []Candidate{
+		{Fields: []string{"Bank", "bank.example.com"}, Weights: []int{2, 1}},
+		{Fields: []string{"GitLab", "gitlab.com"}, Weights: []int{2, 1}},
+		{Fields: []string{"Work mail", "gmail.com"}, Weights: []int{2, 1}},
+	}
+
+	matches := Rank("lab", candidates)
+	if len(matches) != 1 || matches[0].Index != 1 {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+
+	matches = Rank("ma", candidates)
+	if len(matches) != 1 || matches[0].Index != 2 {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+
+	if matches = Rank("", candidates); len(matches) != len(candidates) {
+		t.Errorf("empty pattern must match all candidates, got %d", len(matches))
+	}
+}
@@ -4,45 +4,46 @@ import (
 	"context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"goph-keeper/internal/middleware"
 	pd "goph-keeper/internal/proto/v1"
+	pd2 "goph-keeper/internal/proto/v2"
 	"log/slog"
 )
 
-// service - интерфейс сервисного слоя.
-type service interface {
-	SaveBinaryData(ctx context.Context, userID int, data string) error
+// vaultItems - единое API записей, в которое транслируется запрос v1.
+type vaultItems interface {
+	CreateItem(ctx context.Context, in *pd2.CreateItemRequest) (*pd2.CreateItemResponse, error)
 }
 
-// Handlers - структура ручки сохранения бинарных данных.
+// Handlers - совместимая с v1 ручка сохранения бинарных данных.
 type Handlers struct {
 	pd.UnimplementedPostBinaryDataServer
-	service service
-	log     *slog.Logger
+	vault vaultItems
+	log   *slog.Logger
 }
 
 // NewHandlers - конструктор ручки запроса сохранения в базу бинарные данные.
-func NewHandlers(log *slog.Logger, service service) *Handlers {
+func NewHandlers(log *slog.Logger, vault vaultItems) *Handlers {
 	return &Handlers{
-		log:     log,
-		service: service,
+		log:   log,
+		vault: vault,
 	}
 }
 
-// PostBinaryData - обрабатывает запрос сохранения.
+// PostBinaryData - сохраняет данные как запись типа binary.
 func (h *Handlers) PostBinaryData(ctx context.Context, in *pd.PostTextDataRequest) (*pd.Empty, error) {
 	if in.Data == "" {
 		h.log.Error("data is empty")
 		return nil, status.Errorf(codes.InvalidArgument, "data is empty")
 	}
 
-	userID := ctx.Value(middleware.UserIDContextKey).(int)
-
-	err := h.service.SaveBinaryData(ctx, userID, in.GetData())
-
+	_, err := h.vault.CreateItem(ctx, &pd2.CreateItemRequest{
+		Item: &pd2.Item{
+			Payload: &pd2.Item_Binary{Binary: &pd2.BinaryPayload{Data: []byte(in.GetData())}},
+		},
+	})
 	if err != nil {
-		h.log.Error("failed to save login and password", "error", err)
-		return nil, status.Errorf(codes.Internal, "failed to save login and password")
+		h.log.Error("failed to save binary data", "error", err)
+		return nil, err
 	}
 
 	return &pd.Empty{
@@ -4,32 +4,32 @@ import (
 	"context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"goph-keeper/internal/middleware"
 	pd "goph-keeper/internal/proto/v1"
+	pd2 "goph-keeper/internal/proto/v2"
 	"log/slog"
 )
 
-// service - интерфейс сервисного слоя.
-type service interface {
-	SaveCards(ctx context.Context, userID int, data string) error
+// vaultItems - единое API записей, в которое транслируется запрос v1.
+type vaultItems interface {
+	CreateItem(ctx context.Context, in *pd2.CreateItemRequest) (*pd2.CreateItemResponse, error)
 }
 
-// Handlers - структура ручки сохранения cards.
+// Handlers - совместимая с v1 ручка сохранения cards.
 type Handlers struct {
 	pd.UnimplementedPostCardsServer
-	log     *slog.Logger
-	service service
+	log   *slog.Logger
+	vault vaultItems
 }
 
 // NewHandlers - конструктор ручки запроса сохранения в базу данных cards.
-func NewHandlers(log *slog.Logger, service service) *Handlers {
+func NewHandlers(log *slog.Logger, vault vaultItems) *Handlers {
 	return &Handlers{
-		log:     log,
-		service: service,
+		log:   log,
+		vault: vault,
 	}
 }
 
-// PostCards - обрабатывает запрос сохранения.
+// PostCards - сохраняет данные карты как запись типа card.
 func (h *Handlers) PostCards(ctx context.Context, in *pd.PostTextDataRequest) (*pd.Empty, error) {
 
 	if in.Data == "" {
@@ -37,13 +37,14 @@ func (h *Handlers) PostCards(ctx context.Context, in *pd.PostTextDataRequest) (*
 		return nil, status.Errorf(codes.InvalidArgument, "data is empty")
 	}
 
-	userID := ctx.Value(middleware.UserIDContextKey).(int)
-
-	err := h.service.SaveCards(ctx, userID, in.GetData())
-
+	_, err := h.vault.CreateItem(ctx, &pd2.CreateItemRequest{
+		Item: &pd2.Item{
+			Payload: &pd2.Item_Card{Card: &pd2.CardPayload{Number: in.GetData()}},
+		},
+	})
 	if err != nil {
 		h.log.Error("failed to save cards in base", "error", err)
-		return nil, status.Errorf(codes.Internal, "failed to save cards")
+		return nil, err
 	}
 
 	return &pd.Empty{
@@ -2,36 +2,34 @@ package credentials
 
 import (
 	"context"
-	"errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"goph-keeper/internal/middleware"
 	pd "goph-keeper/internal/proto/v1"
-	"goph-keeper/internal/services/server/credentials"
+	pd2 "goph-keeper/internal/proto/v2"
 	"log/slog"
 )
 
-// service - интерфейс сервисного слоя.
-type serviceCredentials interface {
-	SaveLoginAndPassword(ctx context.Context, userID int, info, login, password string) error
+// vaultItems - единое API записей, в которое транслируется запрос v1.
+type vaultItems interface {
+	CreateItem(ctx context.Context, in *pd2.CreateItemRequest) (*pd2.CreateItemResponse, error)
 }
 
-// Handlers - структура ручки сохранения пароля и логина от ресурса.
+// Handlers - совместимая с v1 ручка сохранения пароля и логина от ресурса.
 type Handlers struct {
 	pd.UnimplementedPostCredentialsServer
-	log     *slog.Logger
-	service serviceCredentials
+	log   *slog.Logger
+	vault vaultItems
 }
 
 // NewHandlers - конструктор ручки запроса сохранения в базу пароль и логин от сервиса.
-func NewHandlers(log *slog.Logger, service serviceCredentials) *Handlers {
+func NewHandlers(log *slog.Logger, vault vaultItems) *Handlers {
 	return &Handlers{
-		log:     log,
-		service: service,
+		log:   log,
+		vault: vault,
 	}
 }
 
-// PostLoginAndPassword сохраняет логин и пароль.
+// PostLoginAndPassword сохраняет логин и пароль как запись типа login.
 func (h *Handlers) PostLoginAndPassword(ctx context.Context, in *pd.PostLoginAndPasswordRequest) (*pd.Empty, error) {
 
 	if in.Password == "" || in.Login == "" {
@@ -39,17 +37,19 @@ func (h *Handlers) PostLoginAndPassword(ctx context.Context, in *pd.PostLoginAnd
 		return nil, status.Errorf(codes.InvalidArgument, "password or login is empty")
 	}
 
-	userID := ctx.Value(middleware.UserIDContextKey).(int)
-
-	err := h.service.SaveLoginAndPassword(ctx, userID, in.GetResource(), in.GetLogin(), in.GetPassword())
-
+	_, err := h.vault.CreateItem(ctx, &pd2.CreateItemRequest{
+		Item: &pd2.Item{
+			Title: in.GetResource(),
+			Payload: &pd2.Item_Login{Login: &pd2.LoginPayload{
+				Resource: in.GetResource(),
+				Login:    in.GetLogin(),
+				Password: in.GetPassword(),
+			}},
+		},
+	})
 	if err != nil {
-		if errors.Is(err, credentials.ErrNotFoundUser) {
-			h.log.Error("failed to get login in base", "error", err)
-			return nil, status.Errorf(codes.NotFound, "login is not correct")
-		}
 		h.log.Error("failed to save login and password", "error", err)
-		return nil, status.Errorf(codes.Internal, "failed to save login and password")
+		return nil, err
 	}
 
 	return &pd.Empty{
@@ -4,45 +4,46 @@ import (
 	"context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"goph-keeper/internal/middleware"
 	pd "goph-keeper/internal/proto/v1"
+	pd2 "goph-keeper/internal/proto/v2"
 	"log/slog"
 )
 
-// service - интерфейс сервисного слоя.
-type service interface {
-	SaveTextData(ctx context.Context, userID int, data string) error
+// vaultItems - единое API записей, в которое транслируется запрос v1.
+type vaultItems interface {
+	CreateItem(ctx context.Context, in *pd2.CreateItemRequest) (*pd2.CreateItemResponse, error)
 }
 
-// Handlers - структура ручки сохранения текста.
+// Handlers - совместимая с v1 ручка сохранения текста.
 type Handlers struct {
 	pd.UnimplementedPostTextDataServer
-	log     *slog.Logger
-	service service
+	log   *slog.Logger
+	vault vaultItems
 }
 
 // NewHandlers - конструктор ручки запроса сохранения в базу данных текста.
-func NewHandlers(log *slog.Logger, service service) *Handlers {
+func NewHandlers(log *slog.Logger, vault vaultItems) *Handlers {
 	return &Handlers{
-		log:     log,
-		service: service,
+		log:   log,
+		vault: vault,
 	}
 }
 
-// PostTextData - обрабатывает запрос сохранения.
+// PostTextData - сохраняет текст как запись типа note.
 func (h *Handlers) PostTextData(ctx context.Context, in *pd.PostTextDataRequest) (*pd.Empty, error) {
 	if in.Data == "" {
 		h.log.Error("data is empty")
 		return nil, status.Errorf(codes.InvalidArgument, "data is empty")
 	}
 
-	userID := ctx.Value(middleware.UserIDContextKey).(int)
-
-	err := h.service.SaveTextData(ctx, userID, in.GetData())
-
+	_, err := h.vault.CreateItem(ctx, &pd2.CreateItemRequest{
+		Item: &pd2.Item{
+			Payload: &pd2.Item_Note{Note: &pd2.NotePayload{Text: in.GetData()}},
+		},
+	})
 	if err != nil {
-		h.log.Error("failed to save login and password", "error", err)
-		return nil, status.Errorf(codes.Internal, "failed to save login and password")
+		h.log.Error("failed to save text data", "error", err)
+		return nil, err
 	}
 
 	return &pd.Empty{
@@ -0,0 +1,234 @@
+package vault
+
+import (
+	"errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+	"time"
+)
+
+var (
+	ErrEmptyPayload    = errors.New("item payload is empty")
+	ErrPayloadMismatch = errors.New("item type does not match payload")
+	ErrSealedType      = errors.New("sealed item requires explicit type")
+)
+
+// unmarshalPayloadOpt - пропускает неизвестные поля, чтобы старые записи читались новыми версиями.
+var unmarshalPayloadOpt = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// payloadType - определяет тип записи по заполненному варианту payload.
+func payloadType(in *pd.Item) models.ItemType {
+	switch in.GetPayload().(type) {
+	case *pd.Item_Login:
+		return models.ItemTypeLogin
+	case *pd.Item_Note:
+		return models.ItemTypeNote
+	case *pd.Item_Binary:
+		return models.ItemTypeBinary
+	case *pd.Item_Card:
+		return models.ItemTypeCard
+	case *pd.Item_SshKey:
+		return models.ItemTypeSSHKey
+	case *pd.Item_Totp:
+		return models.ItemTypeTOTP
+	default:
+		return models.ItemTypeUnspecified
+	}
+}
+
+// itemFromProto - преобразует запись gRPC в модель сервисного слоя.
+// Payload сериализуется в JSON вида {"login": {...}}, чтобы сервер хранил его как есть.
+// Для зашифрованных на клиенте записей тип нельзя вывести из payload, он берется из Item.type.
+func itemFromProto(userID int, in *pd.Item) (models.Item, error) {
+	itemType := payloadType(in)
+	switch {
+	case in.GetSealed() != nil:
+		if in.GetType() == pd.ItemType_ITEM_TYPE_UNSPECIFIED {
+			return models.Item{}, ErrSealedType
+		}
+		itemType = models.ItemType(in.GetType())
+	case itemType == models.ItemTypeUnspecified:
+		return models.Item{}, ErrEmptyPayload
+	case in.GetType() != pd.ItemType_ITEM_TYPE_UNSPECIFIED && models.ItemType(in.GetType()) != itemType:
+		return models.Item{}, ErrPayloadMismatch
+	}
+
+	payload, err := protojson.Marshal(&pd.Item{Payload: in.GetPayload()})
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	return models.Item{
+		ID:         in.GetId(),
+		UserID:     userID,
+		Type:       itemType,
+		Title:      in.GetTitle(),
+		Tags:       in.GetTags(),
+		Favorite:   in.GetFavorite(),
+		FolderID:   in.GetFolderId(),
+		BlindIndex: in.GetBlindIndex(),
+		Payload:    payload,
+	}, nil
+}
+
+// itemToProto - преобразует модель сервисного слоя в запись gRPC.
+func itemToProto(item models.Item) (*pd.Item, error) {
+	var decoded pd.Item
+	if err := unmarshalPayloadOpt.Unmarshal(item.Payload, &decoded); err != nil {
+		return nil, err
+	}
+
+	return &pd.Item{
+		Id:         item.ID,
+		Type:       pd.ItemType(item.Type),
+		Title:      item.Title,
+		Tags:       item.Tags,
+		Favorite:   item.Favorite,
+		FolderId:   item.FolderID,
+		CreatedAt:  timestamppb.New(item.CreatedAt),
+		UpdatedAt:  timestamppb.New(item.UpdatedAt),
+		BlindIndex: item.BlindIndex,
+		DeletedAt:  deletedAtToProto(item.DeletedAt),
+		Payload:    decoded.GetPayload(),
+	}, nil
+}
+
+// deletedAtToProto - время удаления записи, nil у записей вне корзины.
+func deletedAtToProto(deletedAt time.Time) *timestamppb.Timestamp {
+	if deletedAt.IsZero() {
+		return nil
+	}
+	return timestamppb.New(deletedAt)
+}
+
+// tombstoneToProto - надгробие удаленной записи без данных.
+func tombstoneToProto(item models.Item) *pd.Item {
+	return &pd.Item{
+		Id:        item.ID,
+		Type:      pd.ItemType(item.Type),
+		Title:     item.Title,
+		Tags:      item.Tags,
+		Favorite:  item.Favorite,
+		FolderId:  item.FolderID,
+		CreatedAt: timestamppb.New(item.CreatedAt),
+		UpdatedAt: timestamppb.New(item.UpdatedAt),
+		DeletedAt: timestamppb.New(item.DeletedAt),
+	}
+}
+
+// listToProto - преобразует выдачу ListItems: записи из корзины становятся надгробиями.
+func listToProto(items []models.Item) ([]*pd.Item, error) {
+	out := make([]*pd.Item, 0, len(items))
+	for _, item := range items {
+		if !item.DeletedAt.IsZero() {
+			out = append(out, tombstoneToProto(item))
+			continue
+		}
+		converted, err := itemToProto(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+// itemsToProto - преобразует список моделей в записи gRPC.
+func itemsToProto(items []models.Item) ([]*pd.Item, error) {
+	out := make([]*pd.Item, 0, len(items))
+	for _, item := range items {
+		converted, err := itemToProto(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+// revisionsToProto - преобразует ревизии сервисного слоя в ревизии gRPC.
+func revisionsToProto(revisions []models.Revision) ([]*pd.Revision, error) {
+	out := make([]*pd.Revision, 0, len(revisions))
+	for _, rev := range revisions {
+		item, err := itemToProto(rev.Item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, &pd.Revision{
+			Id:        rev.ID,
+			ItemId:    rev.Item.ID,
+			ChangedBy: rev.ChangedByLogin,
+			ChangedAt: timestamppb.New(rev.CreatedAt),
+			Item:      item,
+		})
+	}
+	return out, nil
+}
+
+// folderFromProto - преобразует папку gRPC в модель сервисного слоя.
+func folderFromProto(userID int, in *pd.Folder) models.Folder {
+	return models.Folder{
+		ID:       in.GetId(),
+		UserID:   userID,
+		ParentID: in.GetParentId(),
+		Name:     in.GetName(),
+	}
+}
+
+// folderToProto - преобразует папку сервисного слоя в папку gRPC.
+func folderToProto(folder models.Folder) *pd.Folder {
+	return &pd.Folder{
+		Id:        folder.ID,
+		ParentId:  folder.ParentID,
+		Name:      folder.Name,
+		CreatedAt: timestamppb.New(folder.CreatedAt),
+		UpdatedAt: timestamppb.New(folder.UpdatedAt),
+	}
+}
+
+// shareToProto - преобразует доступ к записи в доступ gRPC.
+func shareToProto(share models.Share) *pd.Share {
+	return &pd.Share{
+		ItemId:     share.ItemID,
+		Recipient:  share.RecipientLogin,
+		Permission: pd.SharePermission(share.Permission),
+		WrappedKey: share.WrappedKey,
+		CreatedAt:  timestamppb.New(share.CreatedAt),
+		UpdatedAt:  timestamppb.New(share.UpdatedAt),
+	}
+}
+
+// sharedItemToProto - преобразует чужую запись в запись gRPC.
+func sharedItemToProto(shared models.SharedItem) (*pd.SharedItem, error) {
+	item, err := itemToProto(shared.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pd.SharedItem{
+		Item:       item,
+		Owner:      shared.OwnerLogin,
+		Permission: pd.SharePermission(shared.Permission),
+		WrappedKey: shared.WrappedKey,
+	}, nil
+}
+
+// pageRequestFromProto - собирает запрос страницы, без сортировки - DefaultSort.
+func pageRequestFromProto(size int32, token string, sort *pd.Sort) models.PageRequest {
+	req := models.PageRequest{
+		Size:  int(size),
+		Token: token,
+		Sort:  models.DefaultSort,
+	}
+
+	switch sort.GetField() {
+	case pd.SortField_SORT_FIELD_UPDATED_AT:
+		req.Sort = models.Sort{Field: models.SortByUpdatedAt, Descending: sort.GetDescending()}
+	case pd.SortField_SORT_FIELD_TITLE:
+		req.Sort = models.Sort{Field: models.SortByTitle, Descending: sort.GetDescending()}
+	}
+
+	return req
+}
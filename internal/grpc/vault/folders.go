@@ -0,0 +1,88 @@
+package vault
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/middleware"
+	pd "goph-keeper/internal/proto/v2"
+)
+
+// CreateFolder - создает папку.
+func (h *Handlers) CreateFolder(ctx context.Context, in *pd.CreateFolderRequest) (*pd.CreateFolderResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	created, err := h.service.CreateFolder(ctx, folderFromProto(userID, in.GetFolder()))
+	if err != nil {
+		return nil, h.statusError("failed to create folder", err)
+	}
+
+	return &pd.CreateFolderResponse{Folder: folderToProto(created)}, nil
+}
+
+// ListFolders - возвращает все папки пользователя.
+func (h *Handlers) ListFolders(ctx context.Context, in *pd.ListFoldersRequest) (*pd.ListFoldersResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	folders, err := h.service.ListFolders(ctx, userID)
+	if err != nil {
+		return nil, h.statusError("failed to list folders", err)
+	}
+
+	out := make([]*pd.Folder, 0, len(folders))
+	for _, folder := range folders {
+		out = append(out, folderToProto(folder))
+	}
+
+	return &pd.ListFoldersResponse{Folders: out}, nil
+}
+
+// UpdateFolder - переименовывает папку или переносит ее к другому родителю.
+func (h *Handlers) UpdateFolder(ctx context.Context, in *pd.UpdateFolderRequest) (*pd.UpdateFolderResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	updated, err := h.service.UpdateFolder(ctx, folderFromProto(userID, in.GetFolder()))
+	if err != nil {
+		return nil, h.statusError("failed to update folder", err)
+	}
+
+	return &pd.UpdateFolderResponse{Folder: folderToProto(updated)}, nil
+}
+
+// DeleteFolder - удаляет папку с вложенными папками.
+func (h *Handlers) DeleteFolder(ctx context.Context, in *pd.DeleteFolderRequest) (*pd.DeleteFolderResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	if err := h.service.DeleteFolder(ctx, userID, in.GetId()); err != nil {
+		return nil, h.statusError("failed to delete folder", err)
+	}
+
+	return &pd.DeleteFolderResponse{}, nil
+}
+
+// MoveItems - переносит записи в папку.
+func (h *Handlers) MoveItems(ctx context.Context, in *pd.MoveItemsRequest) (*pd.MoveItemsResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	moved, err := h.service.MoveItems(ctx, userID, in.GetItemIds(), in.GetFolderId())
+	if err != nil {
+		return nil, h.statusError("failed to move items", err)
+	}
+
+	return &pd.MoveItemsResponse{Moved: moved}, nil
+}
@@ -0,0 +1,341 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/middleware"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
+	pd "goph-keeper/internal/proto/v2"
+	"goph-keeper/internal/services/server/vault"
+	"goph-keeper/internal/storage/postgresql"
+	"log/slog"
+)
+
+// serviceVault - интерфейс сервисного слоя.
+//
+//go:generate mockgen -source=handlers.go -destination=handlers_mock.go -package=vault
+type serviceVault interface {
+	CreateItem(ctx context.Context, item models.Item) (models.Item, error)
+	GetItem(ctx context.Context, userID int, id int64) (models.Item, error)
+	UpdateItem(ctx context.Context, item models.Item) (models.Item, error)
+	DeleteItem(ctx context.Context, userID int, id int64) error
+	ListItems(ctx context.Context, userID int, itemType models.ItemType, req models.PageRequest) ([]models.Item, string, error)
+	Search(ctx context.Context, userID int, filter models.SearchFilter, req models.PageRequest) ([]models.Item, string, error)
+	ListRevisions(ctx context.Context, userID int, itemID int64) ([]models.Revision, error)
+	RestoreRevision(ctx context.Context, userID int, itemID, revisionID int64) (models.Item, error)
+	ListTrash(ctx context.Context, userID int) ([]models.Item, error)
+	RestoreItem(ctx context.Context, userID int, id int64) (models.Item, error)
+	PurgeItem(ctx context.Context, userID int, id int64) error
+	CreateFolder(ctx context.Context, folder models.Folder) (models.Folder, error)
+	ListFolders(ctx context.Context, userID int) ([]models.Folder, error)
+	UpdateFolder(ctx context.Context, folder models.Folder) (models.Folder, error)
+	DeleteFolder(ctx context.Context, userID int, id int64) error
+	MoveItems(ctx context.Context, userID int, ids []int64, folderID int64) (int64, error)
+	CreateKeyPair(ctx context.Context, keys models.KeyPair) (models.KeyPair, error)
+	GetKeyPair(ctx context.Context, userID int) (models.KeyPair, error)
+	GetPublicKey(ctx context.Context, login string) ([]byte, error)
+	ShareItem(ctx context.Context, ownerID int, itemID int64, recipientLogin string, permission models.SharePermission, wrappedKey []byte) (models.Share, error)
+	UnshareItem(ctx context.Context, ownerID int, itemID int64, recipientLogin string) error
+	ListShares(ctx context.Context, ownerID int, itemID int64) ([]models.Share, error)
+	ListSharedWithMe(ctx context.Context, userID int) ([]models.SharedItem, error)
+	UpdateSharedItem(ctx context.Context, userID int, item models.Item) (models.SharedItem, error)
+}
+
+// Handlers - ручки единого API записей хранилища.
+type Handlers struct {
+	pd.UnimplementedVaultServiceServer
+	log     *slog.Logger
+	service serviceVault
+}
+
+// NewHandlers - конструктор ручек хранилища.
+func NewHandlers(log *slog.Logger, service serviceVault) *Handlers {
+	return &Handlers{
+		log:     log,
+		service: service,
+	}
+}
+
+// CreateItem - сохраняет новую запись.
+func (h *Handlers) CreateItem(ctx context.Context, in *pd.CreateItemRequest) (*pd.CreateItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	item, err := itemFromProto(userID, in.GetItem())
+	if err != nil {
+		h.log.Error("failed to convert item", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	created, err := h.service.CreateItem(ctx, item)
+	if err != nil {
+		return nil, h.statusError("failed to create item", err)
+	}
+
+	out, err := itemToProto(created)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.CreateItemResponse{Item: out}, nil
+}
+
+// GetItem - возвращает запись по id.
+func (h *Handlers) GetItem(ctx context.Context, in *pd.GetItemRequest) (*pd.GetItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	item, err := h.service.GetItem(ctx, userID, in.GetId())
+	if err != nil {
+		return nil, h.statusError("failed to get item", err)
+	}
+
+	out, err := itemToProto(item)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.GetItemResponse{Item: out}, nil
+}
+
+// UpdateItem - перезаписывает запись целиком.
+func (h *Handlers) UpdateItem(ctx context.Context, in *pd.UpdateItemRequest) (*pd.UpdateItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	item, err := itemFromProto(userID, in.GetItem())
+	if err != nil {
+		h.log.Error("failed to convert item", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	updated, err := h.service.UpdateItem(ctx, item)
+	if err != nil {
+		return nil, h.statusError("failed to update item", err)
+	}
+
+	out, err := itemToProto(updated)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.UpdateItemResponse{Item: out}, nil
+}
+
+// DeleteItem - перемещает запись в корзину.
+func (h *Handlers) DeleteItem(ctx context.Context, in *pd.DeleteItemRequest) (*pd.DeleteItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	if err := h.service.DeleteItem(ctx, userID, in.GetId()); err != nil {
+		return nil, h.statusError("failed to delete item", err)
+	}
+
+	return &pd.DeleteItemResponse{}, nil
+}
+
+// ListItems - возвращает страницу записей пользователя, при необходимости только одного типа.
+func (h *Handlers) ListItems(ctx context.Context, in *pd.ListItemsRequest) (*pd.ListItemsResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	req := pageRequestFromProto(in.GetPageSize(), in.GetPageToken(), in.GetSort())
+
+	var (
+		items []models.Item
+		next  string
+		err   error
+	)
+	if in.GetIncludeDeleted() {
+		filter := models.SearchFilter{Type: models.ItemType(in.GetType()), IncludeDeleted: true}
+		items, next, err = h.service.Search(ctx, userID, filter, req)
+	} else {
+		items, next, err = h.service.ListItems(ctx, userID, models.ItemType(in.GetType()), req)
+	}
+	if err != nil {
+		return nil, h.statusError("failed to list items", err)
+	}
+
+	out, err := listToProto(items)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.ListItemsResponse{Items: out, NextPageToken: next}, nil
+}
+
+// Search - ищет записи по метаданным с постраничной выдачей.
+func (h *Handlers) Search(ctx context.Context, in *pd.SearchRequest) (*pd.SearchResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	filter := models.SearchFilter{
+		Type:          models.ItemType(in.GetType()),
+		Tag:           in.GetTag(),
+		Query:         in.GetQuery(),
+		FavoritesOnly: in.GetFavoritesOnly(),
+		BlindTokens:   in.GetBlindTokens(),
+	}
+	if in.GetUpdatedAfter() != nil {
+		filter.UpdatedAfter = in.GetUpdatedAfter().AsTime()
+	}
+
+	req := pageRequestFromProto(in.GetPageSize(), in.GetPageToken(), in.GetSort())
+
+	items, next, err := h.service.Search(ctx, userID, filter, req)
+	if err != nil {
+		return nil, h.statusError("failed to search items", err)
+	}
+
+	out, err := itemsToProto(items)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.SearchResponse{Items: out, NextPageToken: next}, nil
+}
+
+// ListRevisions - возвращает историю изменений записи.
+func (h *Handlers) ListRevisions(ctx context.Context, in *pd.ListRevisionsRequest) (*pd.ListRevisionsResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	revisions, err := h.service.ListRevisions(ctx, userID, in.GetItemId())
+	if err != nil {
+		return nil, h.statusError("failed to list revisions", err)
+	}
+
+	out, err := revisionsToProto(revisions)
+	if err != nil {
+		return nil, h.statusError("failed to convert revision", err)
+	}
+
+	return &pd.ListRevisionsResponse{Revisions: out}, nil
+}
+
+// RestoreRevision - возвращает запись к состоянию одной из ревизий.
+func (h *Handlers) RestoreRevision(ctx context.Context, in *pd.RestoreRevisionRequest) (*pd.RestoreRevisionResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	item, err := h.service.RestoreRevision(ctx, userID, in.GetItemId(), in.GetRevisionId())
+	if err != nil {
+		return nil, h.statusError("failed to restore revision", err)
+	}
+
+	out, err := itemToProto(item)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.RestoreRevisionResponse{Item: out}, nil
+}
+
+// ListTrash - возвращает записи пользователя из корзины.
+func (h *Handlers) ListTrash(ctx context.Context, in *pd.ListTrashRequest) (*pd.ListTrashResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	items, err := h.service.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, h.statusError("failed to list trash", err)
+	}
+
+	out, err := itemsToProto(items)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.ListTrashResponse{Items: out}, nil
+}
+
+// RestoreItem - возвращает запись из корзины.
+func (h *Handlers) RestoreItem(ctx context.Context, in *pd.RestoreItemRequest) (*pd.RestoreItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	item, err := h.service.RestoreItem(ctx, userID, in.GetId())
+	if err != nil {
+		return nil, h.statusError("failed to restore item", err)
+	}
+
+	out, err := itemToProto(item)
+	if err != nil {
+		return nil, h.statusError("failed to convert item", err)
+	}
+
+	return &pd.RestoreItemResponse{Item: out}, nil
+}
+
+// PurgeItem - окончательно удаляет запись из корзины.
+func (h *Handlers) PurgeItem(ctx context.Context, in *pd.PurgeItemRequest) (*pd.PurgeItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	if err := h.service.PurgeItem(ctx, userID, in.GetId()); err != nil {
+		return nil, h.statusError("failed to purge item", err)
+	}
+
+	return &pd.PurgeItemResponse{}, nil
+}
+
+// statusError - логирует ошибку и переводит ее в код gRPC.
+func (h *Handlers) statusError(msg string, err error) error {
+	h.log.Error(msg, "error", err)
+
+	switch {
+	case errors.Is(err, postgresql.ErrItemNotFound):
+		return status.Errorf(codes.NotFound, "item not found")
+	case errors.Is(err, postgresql.ErrRevisionNotFound):
+		return status.Errorf(codes.NotFound, "revision not found")
+	case errors.Is(err, postgresql.ErrFolderNotFound):
+		return status.Errorf(codes.NotFound, "folder not found")
+	case errors.Is(err, postgresql.ErrFolderExists):
+		return status.Errorf(codes.AlreadyExists, "folder with this name already exists")
+	case errors.Is(err, postgresql.ErrKeyPairNotFound):
+		return status.Errorf(codes.NotFound, "key pair not found")
+	case errors.Is(err, postgresql.ErrRecipientNotFound):
+		return status.Errorf(codes.NotFound, "recipient not found")
+	case errors.Is(err, postgresql.ErrShareNotFound):
+		return status.Errorf(codes.NotFound, "share not found")
+	case errors.Is(err, vault.ErrReadOnlyShare):
+		return status.Errorf(codes.PermissionDenied, "item is shared read-only")
+	case errors.Is(err, vault.ErrInvalidItem):
+		return status.Errorf(codes.InvalidArgument, "invalid item")
+	case errors.Is(err, vault.ErrInvalidFolder):
+		return status.Errorf(codes.InvalidArgument, "invalid folder")
+	case errors.Is(err, vault.ErrInvalidKeyPair):
+		return status.Errorf(codes.InvalidArgument, "invalid key pair")
+	case errors.Is(err, vault.ErrInvalidShare):
+		return status.Errorf(codes.InvalidArgument, "invalid share")
+	case errors.Is(err, pagination.ErrInvalidPageToken):
+		return status.Errorf(codes.InvalidArgument, "invalid page token")
+	default:
+		return status.Errorf(codes.Internal, "%s", msg)
+	}
+}
@@ -0,0 +1,394 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handlers.go
+
+// Package vault is a generated GoMock package.
+package vault
+
+import (
+	context "context"
+	models "goph-keeper/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockserviceVault is a mock of serviceVault interface.
+type MockserviceVault struct {
+	ctrl     *gomock.Controller
+	recorder *MockserviceVaultMockRecorder
+}
+
+// MockserviceVaultMockRecorder is the mock recorder for MockserviceVault.
+type MockserviceVaultMockRecorder struct {
+	mock *MockserviceVault
+}
+
+// NewMockserviceVault creates a new mock instance.
+func NewMockserviceVault(ctrl *gomock.Controller) *MockserviceVault {
+	mock := &MockserviceVault{ctrl: ctrl}
+	mock.recorder = &MockserviceVaultMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockserviceVault) EXPECT() *MockserviceVaultMockRecorder {
+	return m.recorder
+}
+
+// CreateFolder mocks base method.
+func (m *MockserviceVault) CreateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", ctx, folder)
+	ret0, _ := ret[0].(models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockserviceVaultMockRecorder) CreateFolder(ctx, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockserviceVault)(nil).CreateFolder), ctx, folder)
+}
+
+// CreateItem mocks base method.
+func (m *MockserviceVault) CreateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, item)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockserviceVaultMockRecorder) CreateItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockserviceVault)(nil).CreateItem), ctx, item)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockserviceVault) CreateKeyPair(ctx context.Context, keys models.KeyPair) (models.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", ctx, keys)
+	ret0, _ := ret[0].(models.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockserviceVaultMockRecorder) CreateKeyPair(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockserviceVault)(nil).CreateKeyPair), ctx, keys)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockserviceVault) DeleteFolder(ctx context.Context, userID int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockserviceVaultMockRecorder) DeleteFolder(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockserviceVault)(nil).DeleteFolder), ctx, userID, id)
+}
+
+// DeleteItem mocks base method.
+func (m *MockserviceVault) DeleteItem(ctx context.Context, userID int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockserviceVaultMockRecorder) DeleteItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockserviceVault)(nil).DeleteItem), ctx, userID, id)
+}
+
+// GetItem mocks base method.
+func (m *MockserviceVault) GetItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, userID, id)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockserviceVaultMockRecorder) GetItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockserviceVault)(nil).GetItem), ctx, userID, id)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockserviceVault) GetKeyPair(ctx context.Context, userID int) (models.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx, userID)
+	ret0, _ := ret[0].(models.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockserviceVaultMockRecorder) GetKeyPair(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockserviceVault)(nil).GetKeyPair), ctx, userID)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockserviceVault) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", ctx, login)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockserviceVaultMockRecorder) GetPublicKey(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockserviceVault)(nil).GetPublicKey), ctx, login)
+}
+
+// ListFolders mocks base method.
+func (m *MockserviceVault) ListFolders(ctx context.Context, userID int) ([]models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolders", ctx, userID)
+	ret0, _ := ret[0].([]models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolders indicates an expected call of ListFolders.
+func (mr *MockserviceVaultMockRecorder) ListFolders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolders", reflect.TypeOf((*MockserviceVault)(nil).ListFolders), ctx, userID)
+}
+
+// ListItems mocks base method.
+func (m *MockserviceVault) ListItems(ctx context.Context, userID int, itemType models.ItemType, req models.PageRequest) ([]models.Item, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", ctx, userID, itemType, req)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockserviceVaultMockRecorder) ListItems(ctx, userID, itemType, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockserviceVault)(nil).ListItems), ctx, userID, itemType, req)
+}
+
+// ListRevisions mocks base method.
+func (m *MockserviceVault) ListRevisions(ctx context.Context, userID int, itemID int64) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, userID, itemID)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockserviceVaultMockRecorder) ListRevisions(ctx, userID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockserviceVault)(nil).ListRevisions), ctx, userID, itemID)
+}
+
+// ListSharedWithMe mocks base method.
+func (m *MockserviceVault) ListSharedWithMe(ctx context.Context, userID int) ([]models.SharedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSharedWithMe", ctx, userID)
+	ret0, _ := ret[0].([]models.SharedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSharedWithMe indicates an expected call of ListSharedWithMe.
+func (mr *MockserviceVaultMockRecorder) ListSharedWithMe(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSharedWithMe", reflect.TypeOf((*MockserviceVault)(nil).ListSharedWithMe), ctx, userID)
+}
+
+// ListShares mocks base method.
+func (m *MockserviceVault) ListShares(ctx context.Context, ownerID int, itemID int64) ([]models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, ownerID, itemID)
+	ret0, _ := ret[0].([]models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockserviceVaultMockRecorder) ListShares(ctx, ownerID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockserviceVault)(nil).ListShares), ctx, ownerID, itemID)
+}
+
+// ListTrash mocks base method.
+func (m *MockserviceVault) ListTrash(ctx context.Context, userID int) ([]models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockserviceVaultMockRecorder) ListTrash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockserviceVault)(nil).ListTrash), ctx, userID)
+}
+
+// MoveItems mocks base method.
+func (m *MockserviceVault) MoveItems(ctx context.Context, userID int, ids []int64, folderID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveItems", ctx, userID, ids, folderID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveItems indicates an expected call of MoveItems.
+func (mr *MockserviceVaultMockRecorder) MoveItems(ctx, userID, ids, folderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveItems", reflect.TypeOf((*MockserviceVault)(nil).MoveItems), ctx, userID, ids, folderID)
+}
+
+// PurgeItem mocks base method.
+func (m *MockserviceVault) PurgeItem(ctx context.Context, userID int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeItem", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeItem indicates an expected call of PurgeItem.
+func (mr *MockserviceVaultMockRecorder) PurgeItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeItem", reflect.TypeOf((*MockserviceVault)(nil).PurgeItem), ctx, userID, id)
+}
+
+// RestoreItem mocks base method.
+func (m *MockserviceVault) RestoreItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreItem", ctx, userID, id)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreItem indicates an expected call of RestoreItem.
+func (mr *MockserviceVaultMockRecorder) RestoreItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreItem", reflect.TypeOf((*MockserviceVault)(nil).RestoreItem), ctx, userID, id)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockserviceVault) RestoreRevision(ctx context.Context, userID int, itemID, revisionID int64) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, userID, itemID, revisionID)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockserviceVaultMockRecorder) RestoreRevision(ctx, userID, itemID, revisionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockserviceVault)(nil).RestoreRevision), ctx, userID, itemID, revisionID)
+}
+
+// Search mocks base method.
+func (m *MockserviceVault) Search(ctx context.Context, userID int, filter models.SearchFilter, req models.PageRequest) ([]models.Item, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, userID, filter, req)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockserviceVaultMockRecorder) Search(ctx, userID, filter, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockserviceVault)(nil).Search), ctx, userID, filter, req)
+}
+
+// ShareItem mocks base method.
+func (m *MockserviceVault) ShareItem(ctx context.Context, ownerID int, itemID int64, recipientLogin string, permission models.SharePermission, wrappedKey []byte) (models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareItem", ctx, ownerID, itemID, recipientLogin, permission, wrappedKey)
+	ret0, _ := ret[0].(models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareItem indicates an expected call of ShareItem.
+func (mr *MockserviceVaultMockRecorder) ShareItem(ctx, ownerID, itemID, recipientLogin, permission, wrappedKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareItem", reflect.TypeOf((*MockserviceVault)(nil).ShareItem), ctx, ownerID, itemID, recipientLogin, permission, wrappedKey)
+}
+
+// UnshareItem mocks base method.
+func (m *MockserviceVault) UnshareItem(ctx context.Context, ownerID int, itemID int64, recipientLogin string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareItem", ctx, ownerID, itemID, recipientLogin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnshareItem indicates an expected call of UnshareItem.
+func (mr *MockserviceVaultMockRecorder) UnshareItem(ctx, ownerID, itemID, recipientLogin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareItem", reflect.TypeOf((*MockserviceVault)(nil).UnshareItem), ctx, ownerID, itemID, recipientLogin)
+}
+
+// UpdateFolder mocks base method.
+func (m *MockserviceVault) UpdateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFolder", ctx, folder)
+	ret0, _ := ret[0].(models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFolder indicates an expected call of UpdateFolder.
+func (mr *MockserviceVaultMockRecorder) UpdateFolder(ctx, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFolder", reflect.TypeOf((*MockserviceVault)(nil).UpdateFolder), ctx, folder)
+}
+
+// UpdateItem mocks base method.
+func (m *MockserviceVault) UpdateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, item)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockserviceVaultMockRecorder) UpdateItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockserviceVault)(nil).UpdateItem), ctx, item)
+}
+
+// UpdateSharedItem mocks base method.
+func (m *MockserviceVault) UpdateSharedItem(ctx context.Context, userID int, item models.Item) (models.SharedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSharedItem", ctx, userID, item)
+	ret0, _ := ret[0].(models.SharedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSharedItem indicates an expected call of UpdateSharedItem.
+func (mr *MockserviceVaultMockRecorder) UpdateSharedItem(ctx, userID, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSharedItem", reflect.TypeOf((*MockserviceVault)(nil).UpdateSharedItem), ctx, userID, item)
+}
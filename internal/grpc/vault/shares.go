@@ -0,0 +1,162 @@
+package vault
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"goph-keeper/internal/middleware"
+	"goph-keeper/internal/models"
+	pd "goph-keeper/internal/proto/v2"
+)
+
+// CreateKeyPair - сохраняет пару ключей пользователя, если ее еще нет.
+func (h *Handlers) CreateKeyPair(ctx context.Context, in *pd.CreateKeyPairRequest) (*pd.CreateKeyPairResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	keys, err := h.service.CreateKeyPair(ctx, models.KeyPair{
+		UserID:     userID,
+		PublicKey:  in.GetKeyPair().GetPublicKey(),
+		PrivateKey: in.GetKeyPair().GetPrivateKey(),
+	})
+	if err != nil {
+		return nil, h.statusError("failed to create key pair", err)
+	}
+
+	return &pd.CreateKeyPairResponse{
+		KeyPair: &pd.KeyPair{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey},
+	}, nil
+}
+
+// GetKeyPair - возвращает пару ключей пользователя.
+func (h *Handlers) GetKeyPair(ctx context.Context, in *pd.GetKeyPairRequest) (*pd.GetKeyPairResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	keys, err := h.service.GetKeyPair(ctx, userID)
+	if err != nil {
+		return nil, h.statusError("failed to get key pair", err)
+	}
+
+	return &pd.GetKeyPairResponse{
+		KeyPair: &pd.KeyPair{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey},
+	}, nil
+}
+
+// GetPublicKey - возвращает открытый ключ другого пользователя.
+func (h *Handlers) GetPublicKey(ctx context.Context, in *pd.GetPublicKeyRequest) (*pd.GetPublicKeyResponse, error) {
+	if _, ok := middleware.UserIDFromContext(ctx); !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	publicKey, err := h.service.GetPublicKey(ctx, in.GetLogin())
+	if err != nil {
+		return nil, h.statusError("failed to get public key", err)
+	}
+
+	return &pd.GetPublicKeyResponse{PublicKey: publicKey}, nil
+}
+
+// Share - открывает другому пользователю доступ к записи.
+func (h *Handlers) Share(ctx context.Context, in *pd.ShareRequest) (*pd.ShareResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	share, err := h.service.ShareItem(ctx, userID, in.GetItemId(), in.GetRecipient(),
+		models.SharePermission(in.GetPermission()), in.GetWrappedKey())
+	if err != nil {
+		return nil, h.statusError("failed to share item", err)
+	}
+
+	return &pd.ShareResponse{Share: shareToProto(share)}, nil
+}
+
+// Unshare - закрывает доступ другого пользователя к записи.
+func (h *Handlers) Unshare(ctx context.Context, in *pd.UnshareRequest) (*pd.UnshareResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	if err := h.service.UnshareItem(ctx, userID, in.GetItemId(), in.GetRecipient()); err != nil {
+		return nil, h.statusError("failed to unshare item", err)
+	}
+
+	return &pd.UnshareResponse{}, nil
+}
+
+// ListShares - возвращает получателей записи.
+func (h *Handlers) ListShares(ctx context.Context, in *pd.ListSharesRequest) (*pd.ListSharesResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	shares, err := h.service.ListShares(ctx, userID, in.GetItemId())
+	if err != nil {
+		return nil, h.statusError("failed to list shares", err)
+	}
+
+	out := make([]*pd.Share, 0, len(shares))
+	for _, share := range shares {
+		out = append(out, shareToProto(share))
+	}
+
+	return &pd.ListSharesResponse{Shares: out}, nil
+}
+
+// ListSharedWithMe - возвращает чужие записи, к которым пользователю открыт доступ.
+func (h *Handlers) ListSharedWithMe(ctx context.Context, in *pd.ListSharedWithMeRequest) (*pd.ListSharedWithMeResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	items, err := h.service.ListSharedWithMe(ctx, userID)
+	if err != nil {
+		return nil, h.statusError("failed to list shared items", err)
+	}
+
+	out := make([]*pd.SharedItem, 0, len(items))
+	for _, shared := range items {
+		item, err := sharedItemToProto(shared)
+		if err != nil {
+			return nil, h.statusError("failed to convert shared item", err)
+		}
+		out = append(out, item)
+	}
+
+	return &pd.ListSharedWithMeResponse{Items: out}, nil
+}
+
+// UpdateSharedItem - изменяет чужую запись, если доступ открыт на изменение.
+func (h *Handlers) UpdateSharedItem(ctx context.Context, in *pd.UpdateSharedItemRequest) (*pd.UpdateSharedItemResponse, error) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not authorized")
+	}
+
+	item, err := itemFromProto(userID, in.GetItem())
+	if err != nil {
+		h.log.Error("failed to convert item", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	updated, err := h.service.UpdateSharedItem(ctx, userID, item)
+	if err != nil {
+		return nil, h.statusError("failed to update shared item", err)
+	}
+
+	out, err := sharedItemToProto(updated)
+	if err != nil {
+		return nil, h.statusError("failed to convert shared item", err)
+	}
+
+	return &pd.UpdateSharedItemResponse{Item: out}, nil
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"goph-keeper/internal/models"
+	"io"
+	"strings"
+)
+
+// Типы записей Bitwarden.
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+)
+
+// bitwardenExport - незашифрованный JSON-экспорт Bitwarden.
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+// bitwardenItem - запись Bitwarden.
+type bitwardenItem struct {
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	Favorite bool   `json:"favorite"`
+	FolderID string `json:"folderId"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"fields"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+}
+
+// BitwardenJSON - записи из незашифрованного JSON-экспорта Bitwarden. Папка становится
+// меткой, записи identity пропускаются.
+func BitwardenJSON(r io.Reader) (Result, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	if export.Encrypted {
+		return Result{}, ErrEncryptedExport
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, f := range export.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	var res Result
+	for _, item := range export.Items {
+		e := entry{title: item.Name, notes: item.Notes, favorite: item.Favorite}
+		if folder := folders[item.FolderID]; folder != "" {
+			e.tags = []string{folder}
+		}
+		for _, f := range item.Fields {
+			if f.Value != "" {
+				e.extra = append(e.extra, [2]string{f.Name, f.Value})
+			}
+		}
+
+		switch item.Type {
+		case bitwardenLogin:
+			if item.Login != nil {
+				e.login = item.Login.Username
+				e.password = item.Login.Password
+				e.totp = item.Login.TOTP
+				if len(item.Login.URIs) > 0 {
+					e.resource = item.Login.URIs[0].URI
+				}
+			}
+		case bitwardenSecureNote:
+		case bitwardenCard:
+			if item.Card != nil {
+				e.card = &models.CardPayload{
+					Number: digits(item.Card.Number),
+					Holder: item.Card.CardholderName,
+					Expiry: expiry(item.Card.ExpMonth, item.Card.ExpYear),
+					CVV:    item.Card.Code,
+				}
+			}
+		case bitwardenIdentity:
+			res.Skipped = append(res.Skipped, Skipped{Title: item.Name, Reason: "identity items are not supported"})
+			continue
+		default:
+			res.Skipped = append(res.Skipped, Skipped{Title: item.Name, Reason: fmt.Sprintf("unknown item type %d", item.Type)})
+			continue
+		}
+		res.add(e)
+	}
+	return res, nil
+}
+
+// expiry - срок действия карты в виде MM/YY.
+func expiry(month, year string) string {
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
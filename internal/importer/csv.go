@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvAliases - поля записи и названия колонок, которые им соответствуют без явного
+// сопоставления. Названия сравниваются без учета регистра.
+var csvAliases = map[string][]string{
+	"title":    {"title", "name"},
+	"resource": {"resource", "url", "uri", "login_uri", "website", "site"},
+	"login":    {"login", "username", "user", "login_username", "email"},
+	"password": {"password", "login_password", "pass"},
+	"notes":    {"notes", "note", "comments", "extra"},
+	"tags":     {"tags", "group", "folder", "grouping"},
+	"favorite": {"favorite", "fav"},
+	"totp":     {"totp", "otp", "login_totp"},
+	"number":   {"number", "card_number", "card number"},
+	"holder":   {"holder", "cardholder", "cardholder_name", "card holder"},
+	"expiry":   {"expiry", "expiration", "exp"},
+	"cvv":      {"cvv", "cvc", "code", "security code"},
+}
+
+// ParseMapping - сопоставление полей записи колонкам CSV из строки
+// "title=Name,login=Username". Колонки задаются названием или номером с 1.
+func ParseMapping(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		field, column, ok := strings.Cut(part, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("invalid mapping %q, want field=column", part)
+		}
+		if _, known := csvAliases[field]; !known {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+		mapping[field] = strings.TrimSpace(column)
+	}
+	return mapping, nil
+}
+
+// CSV - записи из CSV с заголовком. mapping сопоставляет поля записи колонкам, остальные
+// поля ищутся по привычным названиям колонок. Строка с номером карты становится записью
+// card, с логином, паролем или адресом - login, с одним текстом - note.
+func CSV(r io.Reader, mapping map[string]string) (Result, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Result{}, fmt.Errorf("%w: empty csv", ErrInvalidFormat)
+		}
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	columns, err := csvColumns(header, mapping)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+		}
+
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		favorite, _ := strconv.ParseBool(get("favorite"))
+		e := entry{
+			title:    get("title"),
+			resource: get("resource"),
+			login:    get("login"),
+			password: get("password"),
+			notes:    get("notes"),
+			totp:     get("totp"),
+			tags:     splitTags(get("tags")),
+			favorite: favorite || get("favorite") == "1",
+		}
+		if number := digits(get("number")); number != "" {
+			e.card = &models.CardPayload{
+				Number: number,
+				Holder: get("holder"),
+				Expiry: get("expiry"),
+				CVV:    get("cvv"),
+			}
+		}
+		res.add(e)
+	}
+	return res, nil
+}
+
+// csvColumns - номера колонок для полей записи.
+func csvColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := index[name]; !ok {
+			index[name] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	for field, column := range mapping {
+		if n, err := strconv.Atoi(column); err == nil && n >= 1 && n <= len(header) {
+			columns[field] = n - 1
+			continue
+		}
+		i, ok := index[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf("%w: no column %q for field %s", ErrInvalidFormat, column, field)
+		}
+		columns[field] = i
+	}
+
+	for field, aliases := range csvAliases {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		for _, alias := range aliases {
+			if i, ok := index[alias]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: no known columns, pass a mapping", ErrInvalidFormat)
+	}
+	return columns, nil
+}
@@ -0,0 +1,197 @@
+// Package importer - разбор экспортов других менеджеров паролей в записи хранилища.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/totp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrEncryptedExport = errors.New("export is encrypted, export it unencrypted")
+	ErrInvalidFormat   = errors.New("invalid export format")
+)
+
+// Record - запись для импорта.
+type Record struct {
+	Item    models.Item
+	Payload models.Payload
+}
+
+// Skipped - запись экспорта, которую не удалось перенести.
+type Skipped struct {
+	Title  string
+	Reason string
+}
+
+// Result - записи, разобранные из экспорта, и пропущенные записи.
+type Result struct {
+	Records []Record
+	Skipped []Skipped
+}
+
+// entry - запись экспорта в общем виде. Из нее получаются записи хранилища.
+type entry struct {
+	title    string
+	resource string
+	login    string
+	password string
+	notes    string
+	totp     string
+	tags     []string
+	favorite bool
+
+	card *models.CardPayload
+	// extra - дополнительные поля, они попадают в заметку
+	extra [][2]string
+}
+
+// add - переводит запись экспорта в записи хранилища: login, card или note. Заметки и
+// дополнительные поля записи с логином или картой сохраняются отдельной заметкой, секрет
+// TOTP - отдельной записью totp.
+func (r *Result) add(e entry) {
+	title := strings.TrimSpace(e.title)
+	if title == "" {
+		title = firstNonEmpty(e.resource, e.login)
+	}
+	if title == "" && e.card != nil {
+		title = e.card.Holder
+	}
+
+	record := func(payload models.Payload, title string) {
+		r.Records = append(r.Records, Record{
+			Item:    models.Item{Type: payload.Type(), Title: title, Tags: e.tags, Favorite: e.favorite},
+			Payload: payload,
+		})
+	}
+
+	notes := e.notesText()
+	switch {
+	case e.card != nil && e.card.Number != "":
+		record(models.Payload{Card: e.card}, title)
+	case e.login != "" || e.password != "" || e.resource != "":
+		record(models.Payload{Login: &models.LoginPayload{Resource: e.resource, Login: e.login, Password: e.password}}, title)
+	case notes != "":
+		if title == "" {
+			title = firstLine(notes)
+		}
+		record(models.Payload{Note: &models.NotePayload{Text: notes}}, title)
+		notes = ""
+	case e.totp == "":
+		r.Skipped = append(r.Skipped, Skipped{Title: title, Reason: "no data"})
+		return
+	}
+
+	if notes != "" {
+		record(models.Payload{Note: &models.NotePayload{Text: notes}}, title+" (notes)")
+	}
+	if e.totp != "" {
+		otp, err := totp.Parse(e.totp)
+		if err != nil {
+			r.Skipped = append(r.Skipped, Skipped{Title: title + " (2FA)", Reason: err.Error()})
+			return
+		}
+		record(models.Payload{TOTP: &otp}, title+" (2FA)")
+	}
+}
+
+// notesText - заметки записи вместе с дополнительными полями.
+func (e entry) notesText() string {
+	notes := strings.TrimSpace(e.notes)
+	if len(e.extra) == 0 {
+		return notes
+	}
+
+	var sb strings.Builder
+	sb.WriteString(notes)
+	for _, kv := range e.extra {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%s: %s", kv[0], kv[1])
+	}
+	return sb.String()
+}
+
+// Key - ключ для поиска дубликатов: записи с одинаковым ключом считаются одной записью.
+// Для зашифрованных на клиенте записей ключа нет.
+func Key(item models.Item, payload models.Payload) string {
+	norm := func(s string) string {
+		return strings.ToLower(strings.TrimSpace(s))
+	}
+
+	switch {
+	case payload.Login != nil:
+		return "login|" + norm(firstNonEmpty(payload.Login.Resource, item.Title)) + "|" + norm(payload.Login.Login)
+	case payload.Card != nil:
+		return "card|" + digits(payload.Card.Number)
+	case payload.Note != nil:
+		sum := sha256.Sum256([]byte(strings.TrimSpace(payload.Note.Text)))
+		return "note|" + norm(item.Title) + "|" + hex.EncodeToString(sum[:8])
+	case payload.TOTP != nil:
+		return "totp|" + strings.ToUpper(payload.TOTP.Secret)
+	case payload.Sealed != nil:
+		return ""
+	default:
+		return item.Type.String() + "|" + norm(item.Title)
+	}
+}
+
+// splitTags - метки через запятую или точку с запятой, без повторов.
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return uniqueTags(tags)
+}
+
+// uniqueTags - метки без повторов и пустых, в порядке сортировки.
+func uniqueTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := tags[:0:0]
+	for _, tag := range tags {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// digits - только цифры строки.
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// firstNonEmpty - первая непустая строка.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// firstLine - первая строка текста, не длиннее 40 символов.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(line); len(r) > 40 {
+		line = string(r[:40])
+	}
+	return strings.TrimSpace(line)
+}
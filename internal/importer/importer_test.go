@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"errors"
+	"goph-keeper/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const keePassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta><RecycleBinUUID>bin</RecycleBinUUID></Meta>
+	<Root>
+		<Group>
+			<UUID>root</UUID><Name>Database</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>GitHub</Value></String>
+				<String><Key>UserName</Key><Value>alice</Value></String>
+				<String><Key>Password</Key><Value ProtectInMemory="True">pw1</Value></String>
+				<String><Key>URL</Key><Value>https://github.com</Value></String>
+				<String><Key>Notes</Key><Value>recovery codes in the safe</Value></String>
+				<String><Key>otp</Key><Value>otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP</Value></String>
+				<Tags>dev;work</Tags>
+				<History>
+					<Entry><String><Key>Password</Key><Value>old</Value></String></Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>g1</UUID><Name>Internet</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Wi-Fi</Value></String>
+					<String><Key>Notes</Key><Value>guest network</Value></String>
+					<String><Key>SSID</Key><Value>home</Value></String>
+				</Entry>
+			</Group>
+			<Group>
+				<UUID>bin</UUID><Name>Recycle Bin</Name>
+				<Entry><String><Key>Title</Key><Value>deleted</Value></String></Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+func TestKeePassXML(t *testing.T) {
+	res, err := KeePassXML(strings.NewReader(keePassXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Records) != 4 {
+		t.Fatalf("got %d records: %+v", len(res.Records), res.Records)
+	}
+
+	github := res.Records[0]
+	want := &models.LoginPayload{Resource: "https://github.com", Login: "alice", Password: "pw1"}
+	if github.Item.Title != "GitHub" || !reflect.DeepEqual(github.Payload.Login, want) {
+		t.Errorf("login = %+v %+v", github.Item, github.Payload.Login)
+	}
+	if !reflect.DeepEqual(github.Item.Tags, []string{"dev", "work"}) {
+		t.Errorf("tags = %v", github.Item.Tags)
+	}
+	if notes := res.Records[1]; notes.Item.Title != "GitHub (notes)" || notes.Payload.Note.Text != "recovery codes in the safe" {
+		t.Errorf("notes = %+v", notes)
+	}
+	if otp := res.Records[2]; otp.Item.Type != models.ItemTypeTOTP || otp.Payload.TOTP.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("totp = %+v", otp)
+	}
+
+	wifi := res.Records[3]
+	if wifi.Payload.Note == nil || wifi.Payload.Note.Text != "guest network\nSSID: home" {
+		t.Errorf("note = %+v", wifi.Payload)
+	}
+	if !reflect.DeepEqual(wifi.Item.Tags, []string{"Internet"}) {
+		t.Errorf("group tag = %v", wifi.Item.Tags)
+	}
+}
+
+const bitwardenJSON = `{
+	"encrypted": false,
+	"folders": [{"id": "f1", "name": "Finance"}],
+	"items": [
+		{"type": 1, "name": "bank", "folderId": "f1", "favorite": true,
+		 "login": {"username": "bob", "password": "secret", "uris": [{"uri": "https://bank.example"}]}},
+		{"type": 2, "name": "memo", "notes": "call mom", "folderId": null},
+		{"type": 3, "name": "visa", "card": {"cardholderName": "BOB", "number": "4111 1111 1111 1111",
+		 "expMonth": "3", "expYear": "2027", "code": "123"}},
+		{"type": 4, "name": "passport"}
+	]
+}`
+
+func TestBitwardenJSON(t *testing.T) {
+	res, err := BitwardenJSON(strings.NewReader(bitwardenJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Records) != 3 || len(res.Skipped) != 1 {
+		t.Fatalf("records = %+v, skipped = %+v", res.Records, res.Skipped)
+	}
+
+	bank := res.Records[0]
+	if !bank.Item.Favorite || !reflect.DeepEqual(bank.Item.Tags, []string{"Finance"}) ||
+		bank.Payload.Login.Resource != "https://bank.example" || bank.Payload.Login.Password != "secret" {
+		t.Errorf("login = %+v %+v", bank.Item, bank.Payload.Login)
+	}
+	if memo := res.Records[1]; memo.Payload.Note == nil || memo.Payload.Note.Text != "call mom" {
+		t.Errorf("note = %+v", memo.Payload)
+	}
+	wantCard := &models.CardPayload{Number: "4111111111111111", Holder: "BOB", Expiry: "03/27", CVV: "123"}
+	if card := res.Records[2]; !reflect.DeepEqual(card.Payload.Card, wantCard) {
+		t.Errorf("card = %+v", card.Payload.Card)
+	}
+
+	if _, err := BitwardenJSON(strings.NewReader(`{"encrypted": true}`)); !errors.Is(err, ErrEncryptedExport) {
+		t.Errorf("err = %v, want ErrEncryptedExport", err)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	data := "Name,Web Site,User,Secret,Group,Card number,CVV\n" +
+		"mail,https://mail.example,bob,pw,\"work,mail\",,\n" +
+		"visa,,,,,4111-1111-1111-1111,321\n" +
+		"empty,,,,,,\n"
+
+	mapping, err := ParseMapping("resource=Web Site, login=User, password=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := CSV(strings.NewReader(data), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Records) != 2 || len(res.Skipped) != 1 {
+		t.Fatalf("records = %+v, skipped = %+v", res.Records, res.Skipped)
+	}
+
+	mail := res.Records[0]
+	want := &models.LoginPayload{Resource: "https://mail.example", Login: "bob", Password: "pw"}
+	if mail.Item.Title != "mail" || !reflect.DeepEqual(mail.Payload.Login, want) ||
+		!reflect.DeepEqual(mail.Item.Tags, []string{"mail", "work"}) {
+		t.Errorf("login = %+v %+v", mail.Item, mail.Payload.Login)
+	}
+	if card := res.Records[1]; card.Payload.Card == nil || card.Payload.Card.Number != "4111111111111111" || card.Payload.Card.CVV != "321" {
+		t.Errorf("card = %+v", card.Payload)
+	}
+
+	if _, err := CSV(strings.NewReader(data), map[string]string{"login": "missing"}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("unknown column: err = %v", err)
+	}
+	if _, err := ParseMapping("color=Name"); err == nil {
+		t.Error("unknown field must be rejected")
+	}
+}
+
+func TestKey(t *testing.T) {
+	a := Key(models.Item{Title: "GitHub"}, models.Payload{Login: &models.LoginPayload{Resource: "github.com", Login: "Alice", Password: "1"}})
+	b := Key(models.Item{Title: "gh"}, models.Payload{Login: &models.LoginPayload{Resource: "GitHub.com ", Login: "alice", Password: "2"}})
+	if a != b {
+		t.Errorf("same account must be a duplicate: %q != %q", a, b)
+	}
+
+	c := Key(models.Item{}, models.Payload{Card: &models.CardPayload{Number: "4111 1111 1111 1111"}})
+	d := Key(models.Item{}, models.Payload{Card: &models.CardPayload{Number: "4111111111111111"}})
+	if c != d {
+		t.Errorf("card keys differ: %q != %q", c, d)
+	}
+
+	if Key(models.Item{}, models.Payload{Sealed: &models.SealedPayload{}}) != "" {
+		t.Error("sealed items have no key")
+	}
+}
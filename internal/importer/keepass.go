@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// keePassFile - XML-экспорт KeePass 2 (File - Export - KeePass XML).
+type keePassFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+// keePassGroup - группа записей. Записи истории лежат внутри History и сюда не попадают.
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// keePassEntry - запись KeePass.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+	Tags string `xml:"Tags"`
+}
+
+// keePassStandardFields - стандартные поля KeePass, остальные переносятся в заметку.
+var keePassStandardFields = map[string]bool{
+	"Title": true, "UserName": true, "Password": true, "URL": true, "Notes": true, "otp": true,
+}
+
+// KeePassXML - записи из незашифрованного XML-экспорта KeePass 2. Путь группы становится
+// меткой, корзина пропускается, поле otp (KeePassXC) становится записью totp.
+func KeePassXML(r io.Reader) (Result, error) {
+	var file keePassFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	var res Result
+	var walk func(g keePassGroup, path []string)
+	walk = func(g keePassGroup, path []string) {
+		if g.UUID != "" && g.UUID == file.Meta.RecycleBinUUID {
+			return
+		}
+		for _, e := range g.Entries {
+			res.add(keePassToEntry(e, path))
+		}
+		for _, child := range g.Groups {
+			walk(child, append(path[:len(path):len(path)], child.Name))
+		}
+	}
+	// корневая группа - сама база, ее имя в метки не попадает
+	for _, root := range file.Root.Groups {
+		walk(root, nil)
+	}
+	return res, nil
+}
+
+// keePassToEntry - запись KeePass в общем виде.
+func keePassToEntry(e keePassEntry, path []string) entry {
+	out := entry{tags: splitTags(e.Tags)}
+	if len(path) > 0 {
+		out.tags = uniqueTags(append(out.tags, strings.Join(path, "/")))
+	}
+
+	for _, s := range e.Strings {
+		switch s.Key {
+		case "Title":
+			out.title = s.Value
+		case "UserName":
+			out.login = s.Value
+		case "Password":
+			out.password = s.Value
+		case "URL":
+			out.resource = s.Value
+		case "Notes":
+			out.notes = s.Value
+		case "otp":
+			out.totp = s.Value
+		}
+		if !keePassStandardFields[s.Key] && s.Value != "" {
+			out.extra = append(out.extra, [2]string{s.Key, s.Value})
+		}
+	}
+	return out
+}
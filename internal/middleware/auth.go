@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -41,7 +42,7 @@ func (m *Middleware) AuthInterceptor(ctx context.Context) (context.Context, erro
 
 	token := tokens[0]
 	userID, err := m.service.ValidateToken(ctx, token) // Ваша логика проверки токена
-	if err != nil {
+	if err != nil || userID < 0 {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
 
@@ -49,3 +50,31 @@ func (m *Middleware) AuthInterceptor(ctx context.Context) (context.Context, erro
 	newCtx := context.WithValue(ctx, UserIDContextKey, userID)
 	return newCtx, nil
 }
+
+// UnaryAuthInterceptor - проверяет токен у всех методов, кроме перечисленных публичных.
+func (m *Middleware) UnaryAuthInterceptor(publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := make(map[string]struct{}, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := public[info.FullMethod]; ok {
+			return handler(ctx, req)
+		}
+
+		newCtx, err := m.AuthInterceptor(ctx)
+		if err != nil {
+			m.log.Error("failed to authorize request", "method", info.FullMethod, "error", err)
+			return nil, err
+		}
+
+		return handler(newCtx, req)
+	}
+}
+
+// UserIDFromContext - возвращает userID, добавленный в контекст AuthInterceptor.
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(UserIDContextKey).(int)
+	return userID, ok
+}
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS items (
+id BIGSERIAL PRIMARY KEY,
+user_id INT NOT NULL,
+type SMALLINT NOT NULL,
+title TEXT NOT NULL DEFAULT '',
+tags TEXT[] NOT NULL DEFAULT '{}',
+favorite BOOLEAN NOT NULL DEFAULT FALSE,
+payload JSONB NOT NULL,
+created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS items_user_id_type_idx ON items (user_id, type);
+-- +goose StatementEnd
+
+-- Переносим записи из таблиц v1 в единую таблицу items.
+-- +goose StatementBegin
+INSERT INTO items (user_id, type, title, payload, created_at, updated_at)
+SELECT user_id, 1, resource,
+       jsonb_build_object('login', jsonb_build_object('resource', resource, 'login', login, 'password', password)),
+       updated_at, updated_at
+FROM credentials;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO items (user_id, type, payload, created_at, updated_at)
+SELECT user_id, 2, jsonb_build_object('note', jsonb_build_object('text', text)), updated_at, updated_at
+FROM text_data;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO items (user_id, type, payload, created_at, updated_at)
+SELECT user_id, 3,
+       jsonb_build_object('binary', jsonb_build_object('data', replace(encode(binary_data, 'base64'), E'\n', ''))),
+       updated_at, updated_at
+FROM binary_data;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO items (user_id, type, payload, created_at, updated_at)
+SELECT user_id, 4, jsonb_build_object('card', jsonb_build_object('number', cards)), updated_at, updated_at
+FROM cards;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS items;
+-- +goose StatementEnd
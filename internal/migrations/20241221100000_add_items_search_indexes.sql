@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+-- +goose StatementEnd
+
+-- resource вычисляется только из открытых данных login, у зашифрованных записей он NULL.
+-- +goose StatementBegin
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS blind_index TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS resource TEXT GENERATED ALWAYS AS (payload -> 'login' ->> 'resource') STORED;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS items_user_id_updated_at_idx ON items (user_id, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS items_user_id_favorite_idx ON items (user_id) WHERE favorite;
+CREATE INDEX IF NOT EXISTS items_tags_idx ON items USING GIN (tags);
+CREATE INDEX IF NOT EXISTS items_blind_index_idx ON items USING GIN (blind_index);
+CREATE INDEX IF NOT EXISTS items_title_trgm_idx ON items USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS items_resource_trgm_idx ON items USING GIN (resource gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS items_resource_trgm_idx;
+DROP INDEX IF EXISTS items_title_trgm_idx;
+DROP INDEX IF EXISTS items_blind_index_idx;
+DROP INDEX IF EXISTS items_tags_idx;
+DROP INDEX IF EXISTS items_user_id_favorite_idx;
+DROP INDEX IF EXISTS items_user_id_updated_at_idx;
+ALTER TABLE items DROP COLUMN IF EXISTS resource, DROP COLUMN IF EXISTS blind_index;
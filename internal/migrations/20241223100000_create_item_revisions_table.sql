@@ -0,0 +1,33 @@
+-- +goose Up
+-- Ревизия хранит состояние записи после изменения: payload остается в том виде,
+-- в котором его прислал клиент, у зашифрованных записей - зашифрованным.
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS item_revisions (
+id BIGSERIAL PRIMARY KEY,
+item_id BIGINT NOT NULL,
+changed_by INT NOT NULL,
+type SMALLINT NOT NULL,
+title TEXT NOT NULL DEFAULT '',
+tags TEXT[] NOT NULL DEFAULT '{}',
+favorite BOOLEAN NOT NULL DEFAULT FALSE,
+blind_index TEXT[] NOT NULL DEFAULT '{}',
+payload JSONB NOT NULL,
+created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+FOREIGN KEY (changed_by) REFERENCES users(id)
+);
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS item_revisions_item_id_idx ON item_revisions (item_id, id DESC);
+
+-- Текущее состояние существующих записей становится их первой ревизией.
+-- +goose StatementBegin
+INSERT INTO item_revisions (item_id, changed_by, type, title, tags, favorite, blind_index, payload, created_at)
+SELECT id, user_id, type, title, tags, favorite, blind_index, payload, updated_at
+FROM items;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_revisions;
+-- +goose StatementEnd
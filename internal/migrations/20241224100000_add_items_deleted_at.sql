@@ -0,0 +1,16 @@
+-- +goose Up
+-- deleted_at - время перемещения записи в корзину, NULL - запись не удалена.
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS items_deleted_at_idx ON items (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS items_deleted_at_idx;
+-- +goose StatementBegin
+DELETE FROM items WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
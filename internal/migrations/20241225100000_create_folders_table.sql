@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS folders (
+id BIGSERIAL PRIMARY KEY,
+user_id INT NOT NULL,
+parent_id BIGINT,
+name TEXT NOT NULL,
+created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+FOREIGN KEY (user_id) REFERENCES users(id),
+FOREIGN KEY (parent_id) REFERENCES folders(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Имена уникальны среди папок одного уровня, parent_id NULL - корень.
+CREATE UNIQUE INDEX IF NOT EXISTS folders_user_id_parent_id_name_idx ON folders (user_id, COALESCE(parent_id, 0), name);
+
+-- folder_id NULL - запись вне папок.
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN IF NOT EXISTS folder_id BIGINT REFERENCES folders(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS items_folder_id_idx ON items (folder_id) WHERE folder_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS items_folder_id_idx;
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN IF EXISTS folder_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS folders;
+-- +goose StatementEnd
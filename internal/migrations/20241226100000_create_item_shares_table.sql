@@ -0,0 +1,41 @@
+-- +goose Up
+-- Пары ключей X25519 пользователей. Закрытый ключ зашифрован ключом хранилища
+-- пользователя на клиенте, сервер не может его прочитать.
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_keys (
+user_id INT PRIMARY KEY,
+public_key BYTEA NOT NULL,
+private_key BYTEA NOT NULL,
+created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Доступ к записи для другого пользователя. wrapped_key - ключ записи, зашифрованный
+-- открытым ключом получателя: открыть можно только запись, зашифрованную на клиенте,
+-- поэтому ключ есть всегда. permission: 1 - только чтение, 2 - чтение и изменение.
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS item_shares (
+id BIGSERIAL PRIMARY KEY,
+item_id BIGINT NOT NULL,
+recipient_id INT NOT NULL,
+wrapped_key BYTEA NOT NULL,
+permission SMALLINT NOT NULL CHECK (permission IN (1, 2)),
+created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+FOREIGN KEY (recipient_id) REFERENCES users(id) ON DELETE CASCADE,
+UNIQUE (item_id, recipient_id)
+);
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS item_shares_recipient_id_idx ON item_shares (recipient_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS item_shares_recipient_id_idx;
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_shares;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_keys;
+-- +goose StatementEnd
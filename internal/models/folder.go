@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// FolderAny - фильтр списка записей по папке: записи всех папок. Ноль - записи вне папок.
+const FolderAny int64 = -1
+
+// Folder - папка записей пользователя. ParentID - родительская папка, 0 - корень.
+type Folder struct {
+	ID        int64
+	UserID    int
+	ParentID  int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
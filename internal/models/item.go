@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// ItemType - тип записи хранилища.
+type ItemType int16
+
+const (
+	ItemTypeUnspecified ItemType = iota
+	ItemTypeLogin
+	ItemTypeNote
+	ItemTypeBinary
+	ItemTypeCard
+	ItemTypeSSHKey
+	ItemTypeTOTP
+)
+
+// String - возвращает название типа записи.
+func (t ItemType) String() string {
+	switch t {
+	case ItemTypeLogin:
+		return "login"
+	case ItemTypeNote:
+		return "note"
+	case ItemTypeBinary:
+		return "binary"
+	case ItemTypeCard:
+		return "card"
+	case ItemTypeSSHKey:
+		return "ssh"
+	case ItemTypeTOTP:
+		return "totp"
+	default:
+		return "unspecified"
+	}
+}
+
+// Item - запись хранилища пользователя.
+// Payload хранится в сериализованном виде, сервер его не разбирает.
+// BlindIndex - слепые токены поиска, которые клиент вычисляет для зашифрованных записей.
+// DeletedAt - время перемещения в корзину, нулевое у записей вне корзины.
+// FolderID - папка записи, 0 - запись вне папок.
+type Item struct {
+	ID         int64
+	UserID     int
+	Type       ItemType
+	Title      string
+	Tags       []string
+	Favorite   bool
+	FolderID   int64
+	BlindIndex []string
+	Payload    []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  time.Time
+}
@@ -0,0 +1,47 @@
+package models
+
+// SortField - поле сортировки списков записей.
+type SortField int
+
+const (
+	SortByUpdatedAt SortField = iota
+	SortByTitle
+)
+
+// String - возвращает название поля сортировки.
+func (f SortField) String() string {
+	if f == SortByTitle {
+		return "title"
+	}
+	return "updated_at"
+}
+
+// Sort - сортировка списка записей.
+type Sort struct {
+	Field      SortField
+	Descending bool
+}
+
+// DefaultSort - сортировка по умолчанию: сначала недавно измененные.
+var DefaultSort = Sort{Field: SortByUpdatedAt, Descending: true}
+
+// PageRequest - запрос страницы списка от клиента API.
+type PageRequest struct {
+	Size  int
+	Token string
+	Sort  Sort
+}
+
+// Cursor - позиция в списке: значение поля сортировки и id последней выданной записи.
+type Cursor struct {
+	Value string
+	ID    int64
+}
+
+// Page - параметры выборки страницы для слоя storage.
+// After - курсор, после которого начинается страница, nil - первая страница.
+type Page struct {
+	Limit int
+	Sort  Sort
+	After *Cursor
+}
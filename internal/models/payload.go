@@ -0,0 +1,99 @@
+package models
+
+import "encoding/json"
+
+// Payload - данные записи одного из типов. Формат JSON совпадает с protojson
+// сообщения Item v2, поэтому клиент и сервер хранят payload в одном виде.
+type Payload struct {
+	Login  *LoginPayload  `json:"login,omitempty"`
+	Note   *NotePayload   `json:"note,omitempty"`
+	Binary *BinaryPayload `json:"binary,omitempty"`
+	Card   *CardPayload   `json:"card,omitempty"`
+	SSHKey *SSHKeyPayload `json:"sshKey,omitempty"`
+	TOTP   *TOTPPayload   `json:"totp,omitempty"`
+	Sealed *SealedPayload `json:"sealed,omitempty"`
+}
+
+// LoginPayload - логин и пароль от ресурса.
+type LoginPayload struct {
+	Resource string `json:"resource,omitempty"`
+	Login    string `json:"login,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// NotePayload - произвольный текст.
+type NotePayload struct {
+	Text string `json:"text,omitempty"`
+}
+
+// BinaryPayload - бинарные данные.
+type BinaryPayload struct {
+	Name string `json:"name,omitempty"`
+	Data []byte `json:"data,omitempty"`
+}
+
+// CardPayload - данные банковской карты.
+type CardPayload struct {
+	Number string `json:"number,omitempty"`
+	Holder string `json:"holder,omitempty"`
+	Expiry string `json:"expiry,omitempty"`
+	CVV    string `json:"cvv,omitempty"`
+}
+
+// SSHKeyPayload - SSH-ключ. PrivateKey - PEM в формате OpenSSH без парольной фразы,
+// PublicKey - строка authorized_keys, Fingerprint - отпечаток SHA256.
+type SSHKeyPayload struct {
+	PrivateKey  string `json:"privateKey,omitempty"`
+	PublicKey   string `json:"publicKey,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// TOTPPayload - секрет генератора одноразовых кодов RFC 6238. Secret - base32,
+// Algorithm - SHA1, SHA256 или SHA512, Period - шаг в секундах.
+type TOTPPayload struct {
+	Secret    string `json:"secret,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	Account   string `json:"account,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Digits    int    `json:"digits,omitempty"`
+	Period    int    `json:"period,omitempty"`
+}
+
+// SealedPayload - данные, зашифрованные на клиенте.
+type SealedPayload struct {
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+	WrappedKey []byte `json:"wrappedKey,omitempty"`
+}
+
+// Type - возвращает тип записи по заполненному варианту данных.
+func (p Payload) Type() ItemType {
+	switch {
+	case p.Login != nil:
+		return ItemTypeLogin
+	case p.Note != nil:
+		return ItemTypeNote
+	case p.Binary != nil:
+		return ItemTypeBinary
+	case p.Card != nil:
+		return ItemTypeCard
+	case p.SSHKey != nil:
+		return ItemTypeSSHKey
+	case p.TOTP != nil:
+		return ItemTypeTOTP
+	default:
+		return ItemTypeUnspecified
+	}
+}
+
+// EncodePayload - сериализует данные записи.
+func EncodePayload(p Payload) ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// DecodePayload - восстанавливает данные записи.
+func DecodePayload(data []byte) (Payload, error) {
+	var p Payload
+	err := json.Unmarshal(data, &p)
+	return p, err
+}
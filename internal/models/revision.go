@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Revision - сохраненное состояние записи после одного изменения.
+// Item - снимок записи, ChangedBy и ChangedByLogin - пользователь, который внес изменение.
+type Revision struct {
+	ID             int64
+	Item           Item
+	ChangedBy      int
+	ChangedByLogin string
+	CreatedAt      time.Time
+}
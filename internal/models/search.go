@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SearchFilter - фильтры поиска записей. Пустые значения не ограничивают выборку.
+// Записи из корзины в выборку попадают только с IncludeDeleted.
+type SearchFilter struct {
+	Type           ItemType
+	Tag            string
+	Query          string
+	UpdatedAfter   time.Time
+	FavoritesOnly  bool
+	BlindTokens    []string
+	IncludeDeleted bool
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// SharePermission - права получателя на чужую запись.
+type SharePermission int16
+
+const (
+	SharePermissionUnspecified SharePermission = iota
+	SharePermissionRead
+	SharePermissionWrite
+)
+
+// String - возвращает название прав.
+func (p SharePermission) String() string {
+	switch p {
+	case SharePermissionRead:
+		return "read"
+	case SharePermissionWrite:
+		return "write"
+	default:
+		return "unspecified"
+	}
+}
+
+// KeyPair - пара ключей X25519 пользователя для обмена записями.
+// PrivateKey зашифрован на клиенте ключом хранилища пользователя.
+type KeyPair struct {
+	UserID     int
+	PublicKey  []byte
+	PrivateKey []byte
+	CreatedAt  time.Time
+}
+
+// Share - доступ к записи ItemID для пользователя RecipientID. WrappedKey - ключ
+// записи, зашифрованный открытым ключом получателя, пустой у записей, не
+// зашифрованных на клиенте.
+type Share struct {
+	ID             int64
+	ItemID         int64
+	RecipientID    int
+	RecipientLogin string
+	WrappedKey     []byte
+	Permission     SharePermission
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SharedItem - чужая запись, к которой пользователю открыт доступ.
+type SharedItem struct {
+	Item       Item
+	OwnerLogin string
+	Permission SharePermission
+	WrappedKey []byte
+}
@@ -0,0 +1,19 @@
+package models
+
+// LocalItem - запись локального кэша клиента с состоянием синхронизации.
+// ServerID - id записи на сервере, 0 - запись еще не отправлялась.
+type LocalItem struct {
+	Item
+	ServerID int64
+	Deleted  bool
+	Version  int64
+}
+
+// SyncResult - итог синхронизации с сервером.
+type SyncResult struct {
+	Created int
+	Updated int
+	Deleted int
+	Pulled  int
+	Removed int
+}
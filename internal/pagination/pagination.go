@@ -0,0 +1,107 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"goph-keeper/internal/models"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+var (
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// token - содержимое токена страницы. Сортировка сохраняется в токене,
+// чтобы нельзя было продолжить выдачу с другой сортировкой.
+type token struct {
+	Field      models.SortField `json:"f"`
+	Descending bool             `json:"d"`
+	Value      string           `json:"v"`
+	ID         int64            `json:"id"`
+}
+
+// NormalizeSize - приводит размер страницы к допустимому диапазону.
+func NormalizeSize(size int) int {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+	if size > MaxPageSize {
+		return MaxPageSize
+	}
+	return size
+}
+
+// CursorFor - возвращает курсор, указывающий на запись при заданной сортировке.
+func CursorFor(item models.Item, sort models.Sort) models.Cursor {
+	value := item.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	if sort.Field == models.SortByTitle {
+		value = item.Title
+	}
+	return models.Cursor{Value: value, ID: item.ID}
+}
+
+// EncodeToken - кодирует курсор в непрозрачный токен страницы.
+func EncodeToken(sort models.Sort, cursor models.Cursor) string {
+	raw, _ := json.Marshal(token{
+		Field:      sort.Field,
+		Descending: sort.Descending,
+		Value:      cursor.Value,
+		ID:         cursor.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeToken - восстанавливает курсор из токена. Пустой токен - первая страница (nil).
+func DecodeToken(pageToken string, sort models.Sort) (*models.Cursor, error) {
+	if pageToken == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	var t token
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	if t.Field != sort.Field || t.Descending != sort.Descending {
+		return nil, ErrInvalidPageToken
+	}
+
+	return &models.Cursor{Value: t.Value, ID: t.ID}, nil
+}
+
+// Paginate - готовит выборку страницы с запасом в одну запись,
+// по которому Trim определяет наличие следующей страницы.
+func Paginate(req models.PageRequest) (models.Page, error) {
+	after, err := DecodeToken(req.Token, req.Sort)
+	if err != nil {
+		return models.Page{}, err
+	}
+
+	return models.Page{
+		Limit: NormalizeSize(req.Size) + 1,
+		Sort:  req.Sort,
+		After: after,
+	}, nil
+}
+
+// Trim - обрезает лишнюю запись выборки и возвращает токен следующей страницы,
+// пустой если записей больше нет.
+func Trim(items []models.Item, page models.Page) ([]models.Item, string) {
+	size := page.Limit - 1
+	if len(items) <= size {
+		return items, ""
+	}
+
+	items = items[:size]
+	return items, EncodeToken(page.Sort, CursorFor(items[size-1], page.Sort))
+}
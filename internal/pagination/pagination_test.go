@@ -0,0 +1,56 @@
+package pagination
+
+import (
+	"errors"
+	"goph-keeper/internal/models"
+	"testing"
+	"time"
+)
+
+func TestPaginateAndTrim(t *testing.T) {
+	sort := models.Sort{Field: models.SortByTitle}
+
+	page, err := Paginate(models.PageRequest{Size: 2, Sort: sort})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Limit != 3 || page.After != nil {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	items := []models.Item{{ID: 1, Title: "a"}, {ID: 2, Title: "b"}, {ID: 3, Title: "c"}}
+	items, next := Trim(items, page)
+	if len(items) != 2 || next == "" {
+		t.Fatalf("unexpected trim result: %d items, next %q", len(items), next)
+	}
+
+	page, err = Paginate(models.PageRequest{Size: 2, Sort: sort, Token: next})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.After == nil || page.After.Value != "b" || page.After.ID != 2 {
+		t.Fatalf("unexpected cursor: %+v", page.After)
+	}
+
+	if _, next = Trim(items[:1], page); next != "" {
+		t.Errorf("last page must not have next token")
+	}
+}
+
+func TestDecodeToken_SortMismatch(t *testing.T) {
+	item := models.Item{ID: 7, UpdatedAt: time.Now()}
+	token := EncodeToken(models.DefaultSort, CursorFor(item, models.DefaultSort))
+
+	if _, err := DecodeToken(token, models.Sort{Field: models.SortByTitle}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+	if _, err := DecodeToken("!!!", models.DefaultSort); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func TestNormalizeSize(t *testing.T) {
+	if NormalizeSize(0) != DefaultPageSize || NormalizeSize(10000) != MaxPageSize || NormalizeSize(10) != 10 {
+		t.Errorf("unexpected normalized sizes")
+	}
+}
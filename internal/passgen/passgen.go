@@ -0,0 +1,224 @@
+// Package passgen - генератор паролей и парольных фраз на crypto/rand.
+package passgen
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Наборы символов.
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!#$%&*+-=?@^_~()[]{}<>/\\|.,:;'\"`"
+
+	// ambiguousChars - символы, которые легко спутать при чтении с экрана.
+	ambiguousChars = "Il1O0o|`'\".,:;"
+)
+
+// Ограничения политик.
+const (
+	MinLength = 4
+	MaxLength = 256
+	MinWords  = 3
+	MaxWords  = 20
+)
+
+var (
+	ErrInvalidPolicy = errors.New("invalid password policy")
+)
+
+//go:embed eff_large_wordlist.txt
+var wordlistData string
+
+// wordlist - большой словарь EFF для парольных фраз (7776 слов),
+// https://www.eff.org/dice, лицензия CC BY 3.0.
+var wordlist = strings.Fields(wordlistData)
+
+// Policy - правила генерации пароля.
+type Policy struct {
+	Length           int
+	Lower            bool
+	Upper            bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool
+}
+
+// DefaultPolicy - политика по умолчанию.
+var DefaultPolicy = Policy{
+	Length:  20,
+	Lower:   true,
+	Upper:   true,
+	Digits:  true,
+	Symbols: true,
+}
+
+// PassphrasePolicy - правила генерации парольной фразы.
+type PassphrasePolicy struct {
+	Words      int
+	Separator  string
+	Capitalize bool
+}
+
+// DefaultPassphrasePolicy - политика парольной фразы по умолчанию.
+var DefaultPassphrasePolicy = PassphrasePolicy{
+	Words:     6,
+	Separator: "-",
+}
+
+// classes - наборы символов включенных классов.
+func (p Policy) classes() []string {
+	var classes []string
+	add := func(enabled bool, chars string) {
+		if !enabled {
+			return
+		}
+		if p.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+
+	add(p.Lower, lowerChars)
+	add(p.Upper, upperChars)
+	add(p.Digits, digitChars)
+	add(p.Symbols, symbolChars)
+	return classes
+}
+
+// Alphabet - все символы, из которых составляется пароль.
+func (p Policy) Alphabet() string {
+	return strings.Join(p.classes(), "")
+}
+
+// Validate - проверяет, что по политике можно сгенерировать пароль.
+func (p Policy) Validate() error {
+	classes := p.classes()
+	if len(classes) == 0 || p.Length < MinLength || p.Length > MaxLength || p.Length < len(classes) {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// Entropy - оценка энтропии пароля в битах.
+func (p Policy) Entropy() float64 {
+	n := len(p.Alphabet())
+	if n == 0 {
+		return 0
+	}
+	return float64(p.Length) * math.Log2(float64(n))
+}
+
+// Validate - проверяет политику парольной фразы.
+func (p PassphrasePolicy) Validate() error {
+	if p.Words < MinWords || p.Words > MaxWords {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// Entropy - оценка энтропии парольной фразы в битах.
+func (p PassphrasePolicy) Entropy() float64 {
+	return float64(p.Words) * math.Log2(float64(len(wordlist)))
+}
+
+// Generate - генерирует пароль, в котором есть хотя бы один символ каждого включенного класса.
+func Generate(p Policy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	classes := p.classes()
+	alphabet := p.Alphabet()
+	password := make([]byte, 0, p.Length)
+
+	for _, class := range classes {
+		c, err := pick(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < p.Length {
+		c, err := pick(alphabet)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// перемешиваем, чтобы обязательные символы классов не стояли в начале
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
+
+// Passphrase - генерирует парольную фразу из слов словаря EFF.
+func Passphrase(p PassphrasePolicy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	words := make([]string, p.Words)
+	for i := range words {
+		n, err := randInt(len(wordlist))
+		if err != nil {
+			return "", err
+		}
+		words[i] = wordlist[n]
+		if p.Capitalize {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	return strings.Join(words, p.Separator), nil
+}
+
+// Strength - словесная оценка энтропии.
+func Strength(bits float64) string {
+	switch {
+	case bits < 40:
+		return "weak"
+	case bits < 60:
+		return "fair"
+	case bits < 80:
+		return "strong"
+	default:
+		return "very strong"
+	}
+}
+
+// pick - случайный символ строки.
+func pick(chars string) (byte, error) {
+	n, err := randInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n], nil
+}
+
+// randInt - равномерно распределенное случайное число в [0, n).
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
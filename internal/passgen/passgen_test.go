@@ -0,0 +1,77 @@
+package passgen
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	p := Policy{Length: 32, Lower: true, Upper: true, Digits: true, Symbols: true, ExcludeAmbiguous: true}
+
+	for i := 0; i < 100; i++ {
+		password, err := Generate(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(password) != p.Length {
+			t.Fatalf("unexpected length %d", len(password))
+		}
+		if strings.ContainsAny(password, ambiguousChars) {
+			t.Fatalf("password %q contains ambiguous characters", password)
+		}
+		for _, class := range p.classes() {
+			if !strings.ContainsAny(password, class) {
+				t.Fatalf("password %q has no characters of class %q", password, class)
+			}
+		}
+	}
+}
+
+func TestGenerate_InvalidPolicy(t *testing.T) {
+	policies := []Policy{
+		{Length: 20},
+		{Length: 2, Lower: true},
+		{Length: MaxLength + 1, Lower: true},
+	}
+	for _, p := range policies {
+		if _, err := Generate(p); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("policy %+v: expected ErrInvalidPolicy, got %v", p, err)
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	p := PassphrasePolicy{Words: 5, Separator: " ", Capitalize: true}
+
+	phrase, err := Passphrase(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	words := strings.Split(phrase, " ")
+	if len(words) != 5 {
+		t.Fatalf("unexpected words: %q", phrase)
+	}
+	for _, w := range words {
+		if w[:1] != strings.ToUpper(w[:1]) {
+			t.Errorf("word %q is not capitalized", w)
+		}
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	if len(wordlist) != 7776 {
+		t.Fatalf("unexpected wordlist size %d", len(wordlist))
+	}
+
+	got := PassphrasePolicy{Words: 6}.Entropy()
+	if math.Abs(got-77.55) > 0.01 {
+		t.Errorf("unexpected passphrase entropy %.2f", got)
+	}
+
+	got = Policy{Length: 10, Digits: true}.Entropy()
+	if math.Abs(got-33.22) > 0.01 {
+		t.Errorf("unexpected password entropy %.2f", got)
+	}
+}
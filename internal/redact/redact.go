@@ -0,0 +1,37 @@
+// Package redact - маскирование секретов на экране и в логах.
+package redact
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Masked - замена скрытого секрета. Длина фиксирована, чтобы не раскрывать длину секрета.
+const Masked = "••••••••"
+
+// sensitiveKeys - подстроки имен атрибутов лога, значения которых считаются секретами.
+var sensitiveKeys = []string{"password", "token", "secret", "cvv", "key", "card"}
+
+// Mask - скрывает непустой секрет.
+func Mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Masked
+}
+
+// ReplaceAttr - функция для slog.HandlerOptions, которая маскирует значения
+// атрибутов с именами, похожими на секреты.
+func ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+
+	key := strings.ToLower(a.Key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return slog.String(a.Key, Masked)
+		}
+	}
+	return a
+}
@@ -0,0 +1,31 @@
+package redact
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: ReplaceAttr}))
+
+	log.Info("save", "login", "alice", "password", "hunter2", slog.Group("card", "CVV", "123"), "access_token", "abc")
+
+	out := buf.String()
+	for _, secret := range []string{"hunter2", "123", "abc"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains secret %q: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "login=alice") {
+		t.Errorf("log must keep non-secret attributes: %s", out)
+	}
+}
+
+func TestMask(t *testing.T) {
+	if Mask("") != "" || Mask("x") != Masked {
+		t.Errorf("unexpected mask")
+	}
+}
@@ -0,0 +1,99 @@
+package audit_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"goph-keeper/internal/audit"
+	"goph-keeper/internal/breach"
+	"goph-keeper/internal/models"
+	"time"
+)
+
+// Report - отчет по паролям пользователя с данным токеном.
+func (s *ServiceClient) Report(ctx context.Context, token string, opts audit.Options) (audit.Report, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return audit.Report{}, err
+	}
+
+	return s.report(ctx, userID, opts)
+}
+
+// ReportForLogin - отчет по паролям пользователя с данным логином. Используется из
+// командной строки, где токена нет.
+func (s *ServiceClient) ReportForLogin(ctx context.Context, login string, opts audit.Options) (audit.Report, error) {
+	userID, err := s.storage.GetUserIDWithLogin(ctx, login)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return audit.Report{}, err
+	}
+
+	return s.report(ctx, userID, opts)
+}
+
+// report - собирает учетные данные из локального кэша и анализирует их.
+// Зашифрованные записи пропускаются.
+func (s *ServiceClient) report(ctx context.Context, userID int, opts audit.Options) (audit.Report, error) {
+	items, err := s.storage.AllItems(ctx, userID, models.ItemTypeLogin)
+	if err != nil {
+		return audit.Report{}, err
+	}
+
+	entries := make([]audit.Entry, 0, len(items))
+	for _, item := range items {
+		payload, err := models.DecodePayload(item.Payload)
+		if err != nil || payload.Login == nil {
+			continue
+		}
+		entries = append(entries, audit.Entry{
+			ItemID:    item.ID,
+			Title:     item.Title,
+			Resource:  payload.Login.Resource,
+			Login:     payload.Login.Login,
+			Password:  payload.Login.Password,
+			UpdatedAt: item.UpdatedAt,
+		})
+	}
+
+	checked := s.checkBreaches(ctx, entries)
+
+	report := audit.Analyze(entries, time.Now(), opts)
+	report.BreachChecked = checked
+	return report, nil
+}
+
+// checkBreaches - проставляет записям число появлений пароля в утечках. Каждый
+// пароль проверяется один раз: результаты запоминаются по хешу, а не по самому паролю.
+// Возвращает false, если проверка отключена или не удалась.
+func (s *ServiceClient) checkBreaches(ctx context.Context, entries []audit.Entry) bool {
+	counts := make(map[[sha256.Size]byte]int)
+	for i := range entries {
+		password := entries[i].Password
+		if password == "" {
+			continue
+		}
+
+		hash := sha256.Sum256([]byte(password))
+		count, ok := counts[hash]
+		if !ok {
+			var err error
+			count, err = s.breach.Check(ctx, password)
+			if err != nil {
+				if !errors.Is(err, breach.ErrDisabled) {
+					s.log.Error("failed to check password in breaches", "error", err)
+				}
+				// частичный результат не показываем
+				for j := range entries {
+					entries[j].BreachCount = 0
+				}
+				return false
+			}
+			counts[hash] = count
+		}
+		entries[i].BreachCount = count
+	}
+
+	return true
+}
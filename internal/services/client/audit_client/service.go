@@ -0,0 +1,31 @@
+package audit_client
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"log/slog"
+)
+
+type storageClient interface {
+	GetUserIDWithToken(ctx context.Context, token string) (int, error)
+	GetUserIDWithLogin(ctx context.Context, login string) (int, error)
+	AllItems(ctx context.Context, userID int, itemType models.ItemType) ([]models.Item, error)
+}
+
+type breachChecker interface {
+	Check(ctx context.Context, password string) (int, error)
+}
+
+// ServiceClient - сервис аудита паролей локального хранилища.
+type ServiceClient struct {
+	log     *slog.Logger
+	storage storageClient
+	breach  breachChecker
+}
+
+func NewService(log *slog.Logger, storage storageClient, breach breachChecker) *ServiceClient {
+	return &ServiceClient{
+		log:     log,
+		storage: storage,
+		breach:  breach}
+}
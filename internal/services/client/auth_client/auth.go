@@ -11,6 +11,10 @@ type storage interface {
 	SaveLoginAndToken(ctx context.Context, login, token string) error
 	UpdateLoginAndToken(ctx context.Context, userID int, token string) error
 	GetUserIDWithLogin(ctx context.Context, login string) (int, error)
+	SetSession(ctx context.Context, userID int) error
+	GetSessionToken(ctx context.Context) (string, error)
+	GetSessionLogin(ctx context.Context) (string, error)
+	DeleteSession(ctx context.Context) error
 }
 
 type Service struct {
@@ -28,21 +32,39 @@ func NewService(log *slog.Logger, db storage) *Service {
 func (s *Service) SaveTokenInBase(ctx context.Context, login, token string) error {
 	// получаем user_id с помощью login
 	userID, err := s.db.GetUserIDWithLogin(ctx, login)
-	if err != nil {
-		if !errors.Is(err, sql.ErrNoRows) {
-			err = s.db.SaveLoginAndToken(ctx, login, token)
-			if err != nil {
-				return err
-			}
-			return nil
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// пользователь впервые входит с этого клиента
+		if err = s.db.SaveLoginAndToken(ctx, login, token); err != nil {
+			return err
 		}
-		s.log.Error("failed to check user id", "error:", err)
-		return err
-	}
-	err = s.db.UpdateLoginAndToken(ctx, userID, token)
-	if err != nil {
+		if userID, err = s.db.GetUserIDWithLogin(ctx, login); err != nil {
+			return err
+		}
+	case err != nil:
+		s.log.Error("failed to check user id", "error", err)
 		return err
+	default:
+		if err = s.db.UpdateLoginAndToken(ctx, userID, token); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	// вошедший пользователь становится текущим для команд командной строки
+	return s.db.SetSession(ctx, userID)
+}
+
+// CurrentToken - токен текущего пользователя.
+func (s *Service) CurrentToken(ctx context.Context) (string, error) {
+	return s.db.GetSessionToken(ctx)
+}
+
+// CurrentLogin - логин текущего пользователя.
+func (s *Service) CurrentLogin(ctx context.Context) (string, error) {
+	return s.db.GetSessionLogin(ctx)
+}
+
+// Logout - удаляет токен текущего пользователя и завершает сессию.
+func (s *Service) Logout(ctx context.Context) error {
+	return s.db.DeleteSession(ctx)
 }
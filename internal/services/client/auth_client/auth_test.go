@@ -0,0 +1,69 @@
+package auth_client
+
+import (
+	"context"
+	"errors"
+	"goph-keeper/internal/storage/sqlite"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, err := sqlite.NewSqlStorage(log, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := NewService(log, db)
+	ctx := context.Background()
+
+	type step struct {
+		name      string
+		login     string // вход под login, если не пуст, иначе выход
+		wantLogin string
+		wantToken string
+		wantErr   error
+	}
+	steps := []step{
+		{name: "first login", login: "alice", wantLogin: "alice", wantToken: "tok-1"},
+		{name: "switch user", login: "bob", wantLogin: "bob", wantToken: "tok-2"},
+		{name: "logout", wantErr: sqlite.ErrNoSession},
+		{name: "login again", login: "alice", wantLogin: "alice", wantToken: "tok-3"},
+		{name: "logout again", wantErr: sqlite.ErrNoSession},
+	}
+
+	userIDs := map[string]int{}
+	for _, st := range steps {
+		t.Run(st.name, func(t *testing.T) {
+			if st.login == "" {
+				if err := s.Logout(ctx); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				if err := s.SaveTokenInBase(ctx, st.login, st.wantToken); err != nil {
+					t.Fatal(err)
+				}
+				// повторный вход переиспользует локального пользователя и его кэш
+				id, err := db.GetUserIDWithLogin(ctx, st.login)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if prev, ok := userIDs[st.login]; ok && prev != id {
+					t.Errorf("user id for %s changed: %d -> %d", st.login, prev, id)
+				}
+				userIDs[st.login] = id
+			}
+
+			token, err := s.CurrentToken(ctx)
+			if !errors.Is(err, st.wantErr) || token != st.wantToken {
+				t.Errorf("CurrentToken() = %q, %v, want %q, %v", token, err, st.wantToken, st.wantErr)
+			}
+			login, err := s.CurrentLogin(ctx)
+			if !errors.Is(err, st.wantErr) || login != st.wantLogin {
+				t.Errorf("CurrentLogin() = %q, %v, want %q, %v", login, err, st.wantLogin, st.wantErr)
+			}
+		})
+	}
+}
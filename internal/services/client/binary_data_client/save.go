@@ -1,8 +1,27 @@
 package binary_data_client
 
-import "context"
+import (
+	"context"
+	"goph-keeper/internal/models"
+)
 
-func (s *ServiceClient) SaveBinaryData(ctx context.Context, data string) error {
+// SaveBinaryData - сохраняет данные как запись типа binary.
+func (s *ServiceClient) SaveBinaryData(ctx context.Context, token, data string) error {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id")
+		return err
+	}
 
-	return s.storage.SaveBinaryDataInDatabase(ctx, data)
+	payload, err := models.EncodePayload(models.Payload{Binary: &models.BinaryPayload{Data: []byte(data)}})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.storage.CreateItem(ctx, models.Item{
+		UserID:  userID,
+		Type:    models.ItemTypeBinary,
+		Payload: payload,
+	})
+	return err
 }
@@ -1,7 +1,27 @@
 package cards_client
 
-import "context"
+import (
+	"context"
+	"goph-keeper/internal/models"
+)
 
-func (s *ServiceClient) SaveCards(ctx context.Context, card string) error {
-	return s.storage.SaveCardsInDatabase(ctx, card)
+// SaveCards - сохраняет номер карты как запись типа card.
+func (s *ServiceClient) SaveCards(ctx context.Context, token, card string) error {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id")
+		return err
+	}
+
+	payload, err := models.EncodePayload(models.Payload{Card: &models.CardPayload{Number: card}})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.storage.CreateItem(ctx, models.Item{
+		UserID:  userID,
+		Type:    models.ItemTypeCard,
+		Payload: payload,
+	})
+	return err
 }
@@ -2,11 +2,13 @@ package cards_client
 
 import (
 	"context"
+	"goph-keeper/internal/models"
 	"log/slog"
 )
 
 type storageClient interface {
-	SaveCardsInDatabase(ctx context.Context, card string) error
+	CreateItem(ctx context.Context, item models.Item) (models.Item, error)
+	GetUserIDWithToken(ctx context.Context, token string) (int, error)
 }
 
 type ServiceClient struct {
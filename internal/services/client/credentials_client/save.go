@@ -3,13 +3,14 @@ package credentials_client
 import (
 	"context"
 	"errors"
+	"goph-keeper/internal/models"
 )
 
 var (
 	ErrNotFoundUser = errors.New("user not found")
 )
 
-// SaveLoginAndPassword сохраняет логин и пароль от ресурса.
+// SaveLoginAndPassword сохраняет логин и пароль от ресурса как запись типа login.
 func (s *ServiceClient) SaveLoginAndPassword(ctx context.Context, token, resource, login, password string) error {
 	// получаем user_id
 	userID, err := s.storage.GetUserIDWithToken(ctx, token)
@@ -18,7 +19,22 @@ func (s *ServiceClient) SaveLoginAndPassword(ctx context.Context, token, resourc
 		return err
 	}
 
-	err = s.storage.SaveLoginAndPasswordInCredentials(ctx, userID, resource, login, password)
+	payload, err := models.EncodePayload(models.Payload{Login: &models.LoginPayload{
+		Resource: resource,
+		Login:    login,
+		Password: password,
+	}})
+	if err != nil {
+		s.log.Error("failed to encode payload")
+		return err
+	}
+
+	_, err = s.storage.CreateItem(ctx, models.Item{
+		UserID:  userID,
+		Type:    models.ItemTypeLogin,
+		Title:   resource,
+		Payload: payload,
+	})
 	if err != nil {
 		s.log.Error("failed to save data")
 		return err
@@ -2,11 +2,12 @@ package credentials_client
 
 import (
 	"context"
+	"goph-keeper/internal/models"
 	"log/slog"
 )
 
 type credentialsClient interface {
-	SaveLoginAndPasswordInCredentials(ctx context.Context, userID int, resource, login, password string) error
+	CreateItem(ctx context.Context, item models.Item) (models.Item, error)
 	GetUserIDWithToken(ctx context.Context, token string) (int, error)
 }
 
@@ -2,12 +2,16 @@ package get_all_data
 
 import (
 	"context"
-	"database/sql"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
 	"log/slog"
 )
 
 type storage interface {
-	GetAll(ctx context.Context, tableName string) (*sql.Rows, error)
+	GetUserIDWithToken(ctx context.Context, token string) (int, error)
+	ListItems(ctx context.Context, userID int, itemType models.ItemType, folderID int64, page models.Page) ([]models.Item, error)
+	AllItems(ctx context.Context, userID int, itemType models.ItemType) ([]models.Item, error)
+	Folders(ctx context.Context, userID int) ([]models.Folder, error)
 }
 
 type GetAll struct {
@@ -22,6 +26,37 @@ func NewService(log *slog.Logger, db storage) *GetAll {
 	}
 }
 
-func (s *GetAll) GetAllData(ctx context.Context, token, tableName string) (*sql.Rows, error) {
-	return s.DB.GetAll(ctx, tableName)
+// GetPage - возвращает страницу записей пользователя и токен следующей страницы.
+// При folderID отличном от models.FolderAny возвращаются только записи этой папки.
+// Пустой токен в ответе означает, что записей больше нет.
+func (s *GetAll) GetPage(ctx context.Context, token string, itemType models.ItemType, folderID int64, req models.PageRequest) ([]models.Item, string, error) {
+	userID, err := s.DB.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return nil, "", err
+	}
+
+	page, err := pagination.Paginate(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items, err := s.DB.ListItems(ctx, userID, itemType, folderID, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items, next := pagination.Trim(items, page)
+	return items, next, nil
+}
+
+// Folders - возвращает дерево папок пользователя из локального кэша.
+func (s *GetAll) Folders(ctx context.Context, token string) ([]models.Folder, error) {
+	userID, err := s.DB.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return nil, err
+	}
+
+	return s.DB.Folders(ctx, userID)
 }
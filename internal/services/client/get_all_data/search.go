@@ -0,0 +1,64 @@
+package get_all_data
+
+import (
+	"context"
+	"goph-keeper/internal/fuzzy"
+	"goph-keeper/internal/models"
+)
+
+// Веса полей при нечетком поиске: совпадение в названии важнее совпадения в тегах.
+const (
+	weightTitle    = 4
+	weightResource = 3
+	weightLogin    = 2
+	weightTag      = 1
+)
+
+// Search - нечетко ищет query по title, tags, resource и login записей локального кэша
+// и возвращает записи по убыванию релевантности.
+func (s *GetAll) Search(ctx context.Context, token string, itemType models.ItemType, query string) ([]models.Item, error) {
+	userID, err := s.DB.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return nil, err
+	}
+
+	items, err := s.DB.AllItems(ctx, userID, itemType)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]fuzzy.Candidate, len(items))
+	for i, item := range items {
+		candidates[i] = searchCandidate(item)
+	}
+
+	matches := fuzzy.Rank(query, candidates)
+	found := make([]models.Item, len(matches))
+	for i, m := range matches {
+		found[i] = items[m.Index]
+	}
+
+	return found, nil
+}
+
+// searchCandidate - поля записи, по которым идет поиск.
+func searchCandidate(item models.Item) fuzzy.Candidate {
+	c := fuzzy.Candidate{
+		Fields:  []string{item.Title},
+		Weights: []int{weightTitle},
+	}
+
+	// зашифрованные записи ищутся только по метаданным
+	if payload, err := models.DecodePayload(item.Payload); err == nil && payload.Login != nil {
+		c.Fields = append(c.Fields, payload.Login.Resource, payload.Login.Login)
+		c.Weights = append(c.Weights, weightResource, weightLogin)
+	}
+
+	for _, tag := range item.Tags {
+		c.Fields = append(c.Fields, tag)
+		c.Weights = append(c.Weights, weightTag)
+	}
+
+	return c
+}
@@ -0,0 +1,161 @@
+package items_client
+
+import (
+	"context"
+	"errors"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/storage/sqlite"
+	"strconv"
+)
+
+var (
+	ErrInvalidItem   = errors.New("invalid item")
+	ErrItemNotFound  = errors.New("item not found")
+	ErrAmbiguousName = errors.New("several items have this title, use id")
+)
+
+// CreateItem - сохраняет новую запись пользователя. Тип записи определяется
+// по заполненному варианту payload, у зашифрованной на клиенте записи он задан в item.
+func (s *ServiceClient) CreateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return models.Item{}, err
+	}
+
+	item.UserID = userID
+	item.Type = itemType(item, payload)
+	if item.Type == models.ItemTypeUnspecified {
+		return models.Item{}, ErrInvalidItem
+	}
+
+	item.Payload, err = models.EncodePayload(payload)
+	if err != nil {
+		s.log.Error("failed to encode payload", "error", err)
+		return models.Item{}, err
+	}
+
+	return s.storage.CreateItem(ctx, item)
+}
+
+// CreateItems - сохраняет новые записи пользователя одной транзакцией. Данные записей
+// (Payload) уже закодированы, тип определяется по ним.
+func (s *ServiceClient) CreateItems(ctx context.Context, token string, items []models.Item) ([]models.Item, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return nil, err
+	}
+
+	for i := range items {
+		payload, err := models.DecodePayload(items[i].Payload)
+		if err != nil {
+			return nil, err
+		}
+		items[i].UserID = userID
+		items[i].Type = itemType(items[i], payload)
+		if items[i].Type == models.ItemTypeUnspecified {
+			return nil, ErrInvalidItem
+		}
+	}
+
+	return s.storage.CreateItems(ctx, items)
+}
+
+// Resolve - находит запись по id или по точному названию.
+func (s *ServiceClient) Resolve(ctx context.Context, token, ref string) (models.Item, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return models.Item{}, err
+	}
+
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		item, err := s.storage.GetItem(ctx, userID, id)
+		if err == nil || !errors.Is(err, sqlite.ErrItemNotFound) {
+			return item, err
+		}
+		// число может быть и названием записи
+	}
+
+	items, err := s.storage.FindItemsByTitle(ctx, userID, ref)
+	if err != nil {
+		return models.Item{}, err
+	}
+	switch len(items) {
+	case 0:
+		return models.Item{}, ErrItemNotFound
+	case 1:
+		return items[0], nil
+	default:
+		return models.Item{}, ErrAmbiguousName
+	}
+}
+
+// GetItem - возвращает запись пользователя по id.
+func (s *ServiceClient) GetItem(ctx context.Context, token string, id int64) (models.Item, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return models.Item{}, err
+	}
+
+	return s.storage.GetItem(ctx, userID, id)
+}
+
+// UpdateItem - перезаписывает метаданные и данные записи пользователя.
+// Тип записи определяется по заполненному варианту payload.
+func (s *ServiceClient) UpdateItem(ctx context.Context, token string, item models.Item, payload models.Payload) (models.Item, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return models.Item{}, err
+	}
+
+	item.UserID = userID
+	if t := payload.Type(); t != models.ItemTypeUnspecified {
+		item.Type = t
+	}
+	if item.ID <= 0 || item.Type == models.ItemTypeUnspecified {
+		return models.Item{}, ErrInvalidItem
+	}
+
+	item.Payload, err = models.EncodePayload(payload)
+	if err != nil {
+		s.log.Error("failed to encode payload", "error", err)
+		return models.Item{}, err
+	}
+
+	return s.storage.UpdateItem(ctx, item)
+}
+
+// itemType - тип записи по ее данным. Данные, зашифрованные на клиенте, тип не
+// раскрывают, поэтому для них остается тип, заданный в записи.
+func itemType(item models.Item, payload models.Payload) models.ItemType {
+	if payload.Sealed != nil {
+		return item.Type
+	}
+	return payload.Type()
+}
+
+// DeleteItem - удаляет запись пользователя.
+func (s *ServiceClient) DeleteItem(ctx context.Context, token string, id int64) error {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return err
+	}
+
+	return s.storage.DeleteItem(ctx, userID, id)
+}
+
+// MoveItem - переносит запись пользователя в папку, folderID - id папки на сервере, 0 - корень.
+func (s *ServiceClient) MoveItem(ctx context.Context, token string, id, folderID int64) (models.Item, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return models.Item{}, err
+	}
+
+	return s.storage.MoveItem(ctx, userID, id, folderID)
+}
@@ -0,0 +1,30 @@
+package items_client
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"log/slog"
+)
+
+type storageClient interface {
+	GetUserIDWithToken(ctx context.Context, token string) (int, error)
+	CreateItem(ctx context.Context, item models.Item) (models.Item, error)
+	CreateItems(ctx context.Context, items []models.Item) ([]models.Item, error)
+	GetItem(ctx context.Context, userID int, id int64) (models.Item, error)
+	FindItemsByTitle(ctx context.Context, userID int, title string) ([]models.Item, error)
+	UpdateItem(ctx context.Context, item models.Item) (models.Item, error)
+	DeleteItem(ctx context.Context, userID int, id int64) error
+	MoveItem(ctx context.Context, userID int, id, folderID int64) (models.Item, error)
+}
+
+// ServiceClient - сервис работы с отдельными записями локального хранилища.
+type ServiceClient struct {
+	log     *slog.Logger
+	storage storageClient
+}
+
+func NewService(log *slog.Logger, storage storageClient) *ServiceClient {
+	return &ServiceClient{
+		log:     log,
+		storage: storage}
+}
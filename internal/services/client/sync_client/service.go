@@ -0,0 +1,29 @@
+package sync_client
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"log/slog"
+)
+
+type storageClient interface {
+	GetUserIDWithToken(ctx context.Context, token string) (int, error)
+	DirtyItems(ctx context.Context, userID int) ([]models.LocalItem, error)
+	MarkSynced(ctx context.Context, id, serverID, version int64) error
+	PurgeItem(ctx context.Context, id int64) error
+	ServerID(ctx context.Context, userID int, id int64) (int64, error)
+	ApplyServerItems(ctx context.Context, userID int, items []models.Item) (int, int, error)
+	ReplaceFolders(ctx context.Context, userID int, folders []models.Folder) error
+}
+
+// ServiceClient - локальная часть синхронизации кэша клиента с сервером.
+type ServiceClient struct {
+	log     *slog.Logger
+	storage storageClient
+}
+
+func NewService(log *slog.Logger, storage storageClient) *ServiceClient {
+	return &ServiceClient{
+		log:     log,
+		storage: storage}
+}
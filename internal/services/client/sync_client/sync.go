@@ -0,0 +1,61 @@
+package sync_client
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+)
+
+// PendingChanges - локальные изменения, которые нужно отправить на сервер.
+func (s *ServiceClient) PendingChanges(ctx context.Context, token string) ([]models.LocalItem, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return nil, err
+	}
+
+	return s.storage.DirtyItems(ctx, userID)
+}
+
+// MarkSynced - отмечает запись отправленной, serverID - ее id на сервере, version - версия
+// записи, которая ушла на сервер.
+func (s *ServiceClient) MarkSynced(ctx context.Context, id, serverID, version int64) error {
+	return s.storage.MarkSynced(ctx, id, serverID, version)
+}
+
+// Purge - окончательно удаляет запись, удаление которой дошло до сервера.
+func (s *ServiceClient) Purge(ctx context.Context, id int64) error {
+	return s.storage.PurgeItem(ctx, id)
+}
+
+// ServerID - id локальной записи на сервере, 0 - запись еще не отправлялась.
+func (s *ServiceClient) ServerID(ctx context.Context, token string, id int64) (int64, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return 0, err
+	}
+
+	return s.storage.ServerID(ctx, userID, id)
+}
+
+// ApplyRemote - приводит кэш к полному списку записей сервера.
+func (s *ServiceClient) ApplyRemote(ctx context.Context, token string, items []models.Item) (int, int, error) {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return 0, 0, err
+	}
+
+	return s.storage.ApplyServerItems(ctx, userID, items)
+}
+
+// ApplyFolders - заменяет копию дерева папок в кэше папками сервера.
+func (s *ServiceClient) ApplyFolders(ctx context.Context, token string, folders []models.Folder) error {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id", "error", err)
+		return err
+	}
+
+	return s.storage.ReplaceFolders(ctx, userID, folders)
+}
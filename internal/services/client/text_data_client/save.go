@@ -1,7 +1,27 @@
 package text_data_client
 
-import "context"
+import (
+	"context"
+	"goph-keeper/internal/models"
+)
 
-func (s *ServiceClient) SaveTextData(ctx context.Context, data string) error {
-	return s.storage.SaveTextDataInDatabase(ctx, data)
+// SaveTextData - сохраняет текст как запись типа note.
+func (s *ServiceClient) SaveTextData(ctx context.Context, token, data string) error {
+	userID, err := s.storage.GetUserIDWithToken(ctx, token)
+	if err != nil {
+		s.log.Error("failed to get user_id")
+		return err
+	}
+
+	payload, err := models.EncodePayload(models.Payload{Note: &models.NotePayload{Text: data}})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.storage.CreateItem(ctx, models.Item{
+		UserID:  userID,
+		Type:    models.ItemTypeNote,
+		Payload: payload,
+	})
+	return err
 }
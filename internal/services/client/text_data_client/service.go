@@ -2,11 +2,13 @@ package text_data_client
 
 import (
 	"context"
+	"goph-keeper/internal/models"
 	"log/slog"
 )
 
 type storageTextDataClient interface {
-	SaveTextDataInDatabase(ctx context.Context, data string) error
+	CreateItem(ctx context.Context, item models.Item) (models.Item, error)
+	GetUserIDWithToken(ctx context.Context, token string) (int, error)
 }
 
 type ServiceClient struct {
@@ -0,0 +1,93 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/storage/postgresql"
+	"strings"
+)
+
+var (
+	ErrInvalidFolder = errors.New("invalid folder")
+)
+
+// CreateFolder - проверяет и сохраняет новую папку пользователя.
+func (s *Service) CreateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	folder.Name = strings.TrimSpace(folder.Name)
+	if err := validateFolder(folder); err != nil {
+		return models.Folder{}, err
+	}
+
+	return s.storage.CreateFolder(ctx, folder)
+}
+
+// ListFolders - возвращает все папки пользователя, дерево собирает клиент по ParentID.
+func (s *Service) ListFolders(ctx context.Context, userID int) ([]models.Folder, error) {
+	return s.storage.ListFolders(ctx, userID)
+}
+
+// UpdateFolder - переименовывает папку и переносит ее к другому родителю.
+// Папку нельзя перенести в саму себя или во вложенную в нее папку.
+func (s *Service) UpdateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	folder.Name = strings.TrimSpace(folder.Name)
+	if folder.ID <= 0 {
+		return models.Folder{}, ErrInvalidFolder
+	}
+	if err := validateFolder(folder); err != nil {
+		return models.Folder{}, err
+	}
+
+	if folder.ParentID == folder.ID {
+		return models.Folder{}, ErrInvalidFolder
+	}
+
+	// перенос во вложенную папку хранилище проверяет в одной транзакции с изменением
+	updated, err := s.storage.UpdateFolder(ctx, folder)
+	if errors.Is(err, postgresql.ErrFolderCycle) {
+		return models.Folder{}, ErrInvalidFolder
+	}
+	return updated, err
+}
+
+// DeleteFolder - удаляет папку пользователя с вложенными папками, записи переносятся в корень.
+func (s *Service) DeleteFolder(ctx context.Context, userID int, id int64) error {
+	return s.storage.DeleteFolder(ctx, userID, id)
+}
+
+// MoveItems - переносит записи пользователя в папку, folderID 0 - в корень.
+func (s *Service) MoveItems(ctx context.Context, userID int, ids []int64, folderID int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, ErrInvalidItem
+	}
+
+	if folderID != 0 {
+		folders, err := s.storage.ListFolders(ctx, userID)
+		if err != nil {
+			return 0, err
+		}
+		if !hasFolder(folders, folderID) {
+			return 0, postgresql.ErrFolderNotFound
+		}
+	}
+
+	return s.storage.MoveItems(ctx, userID, ids, folderID)
+}
+
+// validateFolder - проверяет имя папки. Слэш запрещен: клиенты задают папку путем "Работа/Серверы".
+func validateFolder(folder models.Folder) error {
+	if folder.Name == "" || strings.Contains(folder.Name, "/") || folder.ParentID < 0 {
+		return ErrInvalidFolder
+	}
+	return nil
+}
+
+// hasFolder - есть ли папка среди папок пользователя.
+func hasFolder(folders []models.Folder, id int64) bool {
+	for _, f := range folders {
+		if f.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/storage/postgresql"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+// folderTree - Работа(1) / Серверы(2) / Прод(3), Личное(4)
+var folderTree = []models.Folder{
+	{ID: 1, UserID: 1, Name: "Работа"},
+	{ID: 2, UserID: 1, ParentID: 1, Name: "Серверы"},
+	{ID: 3, UserID: 1, ParentID: 2, Name: "Прод"},
+	{ID: 4, UserID: 1, Name: "Личное"},
+}
+
+func TestService_UpdateFolder_Cycle(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	cases := []struct {
+		name       string
+		folder     models.Folder
+		storageErr error
+		noStorage  bool
+		wantErr    error
+	}{
+		{
+			name:      "into_itself",
+			folder:    models.Folder{ID: 1, UserID: 1, ParentID: 1, Name: "Работа"},
+			noStorage: true,
+			wantErr:   ErrInvalidFolder,
+		},
+		{
+			name:       "into_descendant",
+			folder:     models.Folder{ID: 1, UserID: 1, ParentID: 3, Name: "Работа"},
+			storageErr: postgresql.ErrFolderCycle,
+			wantErr:    ErrInvalidFolder,
+		},
+		{
+			name:   "into_sibling",
+			folder: models.Folder{ID: 2, UserID: 1, ParentID: 4, Name: "Серверы"},
+		},
+	}
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			storage := NewMockstorageVault(ctrl)
+
+			if !cc.noStorage {
+				storage.EXPECT().UpdateFolder(ctx, cc.folder).Return(cc.folder, cc.storageErr)
+			}
+
+			serv := NewService(log, storage, 0)
+
+			_, err := serv.UpdateFolder(ctx, cc.folder)
+			if !errors.Is(err, cc.wantErr) {
+				t.Errorf("unexpected error: got %v, want %v", err, cc.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_CreateFolder_InvalidName(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	serv := NewService(log, NewMockstorageVault(ctrl), 0)
+
+	for _, name := range []string{"", "  ", "Работа/Серверы"} {
+		_, err := serv.CreateFolder(context.Background(), models.Folder{UserID: 1, Name: name})
+		if !errors.Is(err, ErrInvalidFolder) {
+			t.Errorf("CreateFolder(%q): expected ErrInvalidFolder, got %v", name, err)
+		}
+	}
+}
+
+func TestService_MoveItems_UnknownFolder(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	storage.EXPECT().ListFolders(ctx, 1).Return(folderTree, nil)
+
+	serv := NewService(log, storage, 0)
+
+	if _, err := serv.MoveItems(ctx, 1, []int64{7}, 42); !errors.Is(err, postgresql.ErrFolderNotFound) {
+		t.Errorf("expected ErrFolderNotFound, got %v", err)
+	}
+}
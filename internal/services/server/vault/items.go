@@ -0,0 +1,57 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"goph-keeper/internal/models"
+)
+
+var (
+	ErrInvalidItem = errors.New("invalid item")
+)
+
+// CreateItem - проверяет и сохраняет новую запись пользователя.
+func (s *Service) CreateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	if err := validateItem(item); err != nil {
+		return models.Item{}, err
+	}
+
+	return s.storage.CreateItem(ctx, item)
+}
+
+// GetItem - возвращает запись пользователя.
+func (s *Service) GetItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	return s.storage.GetItem(ctx, userID, id)
+}
+
+// UpdateItem - проверяет и перезаписывает запись пользователя.
+func (s *Service) UpdateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	if item.ID <= 0 {
+		return models.Item{}, ErrInvalidItem
+	}
+	if err := validateItem(item); err != nil {
+		return models.Item{}, err
+	}
+
+	updated, err := s.storage.UpdateItem(ctx, item)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	s.pruneRevisions(ctx, updated.ID)
+
+	return updated, nil
+}
+
+// DeleteItem - перемещает запись пользователя в корзину.
+func (s *Service) DeleteItem(ctx context.Context, userID int, id int64) error {
+	return s.storage.DeleteItem(ctx, userID, id)
+}
+
+// validateItem - проверяет обязательные поля записи.
+func validateItem(item models.Item) error {
+	if item.Type == models.ItemTypeUnspecified || len(item.Payload) == 0 {
+		return ErrInvalidItem
+	}
+	return nil
+}
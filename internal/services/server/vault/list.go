@@ -0,0 +1,32 @@
+package vault
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
+	"strings"
+)
+
+// ListItems - возвращает страницу записей пользователя указанного типа или всех записей.
+func (s *Service) ListItems(ctx context.Context, userID int, itemType models.ItemType, req models.PageRequest) ([]models.Item, string, error) {
+	return s.Search(ctx, userID, models.SearchFilter{Type: itemType}, req)
+}
+
+// Search - ищет записи пользователя по метаданным.
+// Возвращает страницу записей и токен следующей страницы, пустой если записей больше нет.
+func (s *Service) Search(ctx context.Context, userID int, filter models.SearchFilter, req models.PageRequest) ([]models.Item, string, error) {
+	page, err := pagination.Paginate(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	items, err := s.storage.SearchItems(ctx, userID, filter, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items, next := pagination.Trim(items, page)
+	return items, next, nil
+}
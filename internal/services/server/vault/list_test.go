@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/pagination"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestService_Search_Pagination(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	sort := models.Sort{Field: models.SortByTitle}
+	req := models.PageRequest{Size: 2, Sort: sort}
+
+	// первая страница: хранилище вернуло на одну запись больше размера страницы
+	storage.EXPECT().SearchItems(ctx, 1, gomock.Any(), models.Page{Limit: 3, Sort: sort}).
+		DoAndReturn(func(_ context.Context, _ int, f models.SearchFilter, _ models.Page) ([]models.Item, error) {
+			if f.Query != "mail" {
+				t.Errorf("query is not trimmed: %q", f.Query)
+			}
+			return []models.Item{{ID: 1, Title: "a"}, {ID: 2, Title: "b"}, {ID: 3, Title: "c"}}, nil
+		})
+	// вторая страница начинается после последней выданной записи
+	storage.EXPECT().SearchItems(ctx, 1, gomock.Any(),
+		models.Page{Limit: 3, Sort: sort, After: &models.Cursor{Value: "b", ID: 2}}).
+		Return([]models.Item{{ID: 3, Title: "c"}}, nil)
+
+	serv := NewService(log, storage, 0)
+
+	items, next, err := serv.Search(ctx, 1, models.SearchFilter{Query: " mail "}, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || next == "" {
+		t.Fatalf("unexpected first page: %d items, next %q", len(items), next)
+	}
+
+	req.Token = next
+	items, next, err = serv.Search(ctx, 1, models.SearchFilter{Query: "mail"}, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || next != "" {
+		t.Fatalf("unexpected last page: %d items, next %q", len(items), next)
+	}
+}
+
+func TestService_ListItems_InvalidToken(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	serv := NewService(log, NewMockstorageVault(ctrl), 0)
+
+	_, _, err := serv.ListItems(context.Background(), 1, models.ItemTypeLogin,
+		models.PageRequest{Token: "broken", Sort: models.DefaultSort})
+	if !errors.Is(err, pagination.ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package vault
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+)
+
+// ListRevisions - возвращает историю изменений записи пользователя от новых ревизий к старым.
+func (s *Service) ListRevisions(ctx context.Context, userID int, itemID int64) ([]models.Revision, error) {
+	// Проверяем запись отдельно: у чужой или удаленной записи история пуста,
+	// а клиенту нужен NotFound.
+	if _, err := s.storage.GetItem(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.ListRevisions(ctx, userID, itemID)
+}
+
+// RestoreRevision - возвращает запись к состоянию ревизии. Восстановление - обычное изменение:
+// оно сохраняется новой ревизией, поэтому его тоже можно откатить. Папка в ревизиях не хранится,
+// запись остается в текущей.
+func (s *Service) RestoreRevision(ctx context.Context, userID int, itemID, revisionID int64) (models.Item, error) {
+	current, err := s.storage.GetItem(ctx, userID, itemID)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	rev, err := s.storage.GetRevision(ctx, userID, itemID, revisionID)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	item := rev.Item
+	item.ID = itemID
+	item.UserID = userID
+	item.FolderID = current.FolderID
+
+	return s.UpdateItem(ctx, item)
+}
+
+// pruneRevisions - удаляет ревизии сверх лимита. Ошибка не прерывает изменение записи:
+// лишние ревизии будут удалены при следующем изменении.
+func (s *Service) pruneRevisions(ctx context.Context, itemID int64) {
+	if s.revisionLimit <= 0 {
+		return
+	}
+
+	if err := s.storage.PruneRevisions(ctx, itemID, s.revisionLimit); err != nil {
+		s.log.Error("failed to prune item revisions", "item_id", itemID, "error", err)
+	}
+}
@@ -0,0 +1,96 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/storage/postgresql"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestService_RestoreRevision(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	snapshot := models.Item{ID: 7, UserID: 1, Type: models.ItemTypeNote, Title: "old", Payload: []byte(`{"note":{"text":"a"}}`)}
+	storage.EXPECT().GetItem(ctx, 1, int64(7)).Return(models.Item{ID: 7, UserID: 1, Title: "new", FolderID: 4}, nil)
+	storage.EXPECT().GetRevision(ctx, 1, int64(7), int64(3)).
+		Return(models.Revision{ID: 3, Item: snapshot, ChangedBy: 1}, nil)
+	storage.EXPECT().UpdateItem(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, item models.Item) (models.Item, error) {
+			if item.ID != 7 || item.UserID != 1 || item.Title != "old" || item.FolderID != 4 {
+				t.Errorf("unexpected restored item: %+v", item)
+			}
+			return item, nil
+		})
+	// восстановление создает ревизию, поэтому лимит применяется и здесь
+	storage.EXPECT().PruneRevisions(ctx, int64(7), 5).Return(nil)
+
+	serv := NewService(log, storage, 5)
+
+	item, err := serv.RestoreRevision(ctx, 1, 7, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Title != "old" {
+		t.Errorf("unexpected title: %q", item.Title)
+	}
+}
+
+func TestService_RestoreRevision_NotFound(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	storage.EXPECT().GetItem(ctx, 1, int64(7)).Return(models.Item{ID: 7, UserID: 1}, nil)
+	storage.EXPECT().GetRevision(ctx, 1, int64(7), int64(3)).
+		Return(models.Revision{}, postgresql.ErrRevisionNotFound)
+
+	serv := NewService(log, storage, 5)
+
+	if _, err := serv.RestoreRevision(ctx, 1, 7, 3); !errors.Is(err, postgresql.ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+func TestService_ListRevisions_ItemNotFound(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	storage.EXPECT().GetItem(ctx, 2, int64(7)).Return(models.Item{}, postgresql.ErrItemNotFound)
+
+	serv := NewService(log, storage, 0)
+
+	if _, err := serv.ListRevisions(ctx, 2, 7); !errors.Is(err, postgresql.ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestService_UpdateItem_UnlimitedRevisions(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	item := models.Item{ID: 7, UserID: 1, Type: models.ItemTypeNote, Payload: []byte(`{"note":{}}`)}
+	// без лимита PruneRevisions не вызывается
+	storage.EXPECT().UpdateItem(ctx, item).Return(item, nil)
+
+	serv := NewService(log, storage, 0)
+
+	if _, err := serv.UpdateItem(ctx, item); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
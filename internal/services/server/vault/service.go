@@ -0,0 +1,61 @@
+package vault
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"log/slog"
+	"time"
+)
+
+// storageVault - интерфейс storage для сервиса хранилища.
+//
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=vault
+type storageVault interface {
+	CreateItem(ctx context.Context, item models.Item) (models.Item, error)
+	GetItem(ctx context.Context, userID int, id int64) (models.Item, error)
+	UpdateItem(ctx context.Context, item models.Item) (models.Item, error)
+	DeleteItem(ctx context.Context, userID int, id int64) error
+	SearchItems(ctx context.Context, userID int, filter models.SearchFilter, page models.Page) ([]models.Item, error)
+	ListRevisions(ctx context.Context, userID int, itemID int64) ([]models.Revision, error)
+	GetRevision(ctx context.Context, userID int, itemID, revisionID int64) (models.Revision, error)
+	PruneRevisions(ctx context.Context, itemID int64, keep int) error
+	ListTrash(ctx context.Context, userID int) ([]models.Item, error)
+	RestoreItem(ctx context.Context, userID int, id int64) (models.Item, error)
+	PurgeItem(ctx context.Context, userID int, id int64) error
+	CreateFolder(ctx context.Context, folder models.Folder) (models.Folder, error)
+	ListFolders(ctx context.Context, userID int) ([]models.Folder, error)
+	UpdateFolder(ctx context.Context, folder models.Folder) (models.Folder, error)
+	DeleteFolder(ctx context.Context, userID int, id int64) error
+	MoveItems(ctx context.Context, userID int, ids []int64, folderID int64) (int64, error)
+	CreateKeyPair(ctx context.Context, keys models.KeyPair) (models.KeyPair, error)
+	GetKeyPair(ctx context.Context, userID int) (models.KeyPair, error)
+	GetRecipient(ctx context.Context, login string) (models.KeyPair, error)
+	ShareItem(ctx context.Context, ownerID int, share models.Share) (models.Share, error)
+	UnshareItem(ctx context.Context, ownerID int, itemID int64, recipientID int) error
+	ListShares(ctx context.Context, ownerID int, itemID int64) ([]models.Share, error)
+	ListSharedWithMe(ctx context.Context, userID int) ([]models.SharedItem, error)
+	GetSharedItem(ctx context.Context, userID int, itemID int64) (models.SharedItem, error)
+	UpdateSharedItem(ctx context.Context, userID int, item models.Item) (models.Item, error)
+}
+
+// storageTrash - интерфейс storage для очистки корзины.
+type storageTrash interface {
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Service - сервис записей хранилища.
+// revisionLimit - сколько последних ревизий хранится у записи, 0 - без ограничения.
+type Service struct {
+	log           *slog.Logger
+	storage       storageVault
+	revisionLimit int
+}
+
+// NewService - конструктор сервиса хранилища.
+func NewService(log *slog.Logger, storage storageVault, revisionLimit int) *Service {
+	return &Service{
+		log:           log,
+		storage:       storage,
+		revisionLimit: revisionLimit,
+	}
+}
@@ -0,0 +1,445 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package vault is a generated GoMock package.
+package vault
+
+import (
+	context "context"
+	models "goph-keeper/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockstorageVault is a mock of storageVault interface.
+type MockstorageVault struct {
+	ctrl     *gomock.Controller
+	recorder *MockstorageVaultMockRecorder
+}
+
+// MockstorageVaultMockRecorder is the mock recorder for MockstorageVault.
+type MockstorageVaultMockRecorder struct {
+	mock *MockstorageVault
+}
+
+// NewMockstorageVault creates a new mock instance.
+func NewMockstorageVault(ctrl *gomock.Controller) *MockstorageVault {
+	mock := &MockstorageVault{ctrl: ctrl}
+	mock.recorder = &MockstorageVaultMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockstorageVault) EXPECT() *MockstorageVaultMockRecorder {
+	return m.recorder
+}
+
+// CreateFolder mocks base method.
+func (m *MockstorageVault) CreateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", ctx, folder)
+	ret0, _ := ret[0].(models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockstorageVaultMockRecorder) CreateFolder(ctx, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockstorageVault)(nil).CreateFolder), ctx, folder)
+}
+
+// CreateItem mocks base method.
+func (m *MockstorageVault) CreateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, item)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockstorageVaultMockRecorder) CreateItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockstorageVault)(nil).CreateItem), ctx, item)
+}
+
+// CreateKeyPair mocks base method.
+func (m *MockstorageVault) CreateKeyPair(ctx context.Context, keys models.KeyPair) (models.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyPair", ctx, keys)
+	ret0, _ := ret[0].(models.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKeyPair indicates an expected call of CreateKeyPair.
+func (mr *MockstorageVaultMockRecorder) CreateKeyPair(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyPair", reflect.TypeOf((*MockstorageVault)(nil).CreateKeyPair), ctx, keys)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockstorageVault) DeleteFolder(ctx context.Context, userID int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockstorageVaultMockRecorder) DeleteFolder(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockstorageVault)(nil).DeleteFolder), ctx, userID, id)
+}
+
+// DeleteItem mocks base method.
+func (m *MockstorageVault) DeleteItem(ctx context.Context, userID int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockstorageVaultMockRecorder) DeleteItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockstorageVault)(nil).DeleteItem), ctx, userID, id)
+}
+
+// GetItem mocks base method.
+func (m *MockstorageVault) GetItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, userID, id)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockstorageVaultMockRecorder) GetItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockstorageVault)(nil).GetItem), ctx, userID, id)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockstorageVault) GetKeyPair(ctx context.Context, userID int) (models.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx, userID)
+	ret0, _ := ret[0].(models.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockstorageVaultMockRecorder) GetKeyPair(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockstorageVault)(nil).GetKeyPair), ctx, userID)
+}
+
+// GetRecipient mocks base method.
+func (m *MockstorageVault) GetRecipient(ctx context.Context, login string) (models.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipient", ctx, login)
+	ret0, _ := ret[0].(models.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipient indicates an expected call of GetRecipient.
+func (mr *MockstorageVaultMockRecorder) GetRecipient(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipient", reflect.TypeOf((*MockstorageVault)(nil).GetRecipient), ctx, login)
+}
+
+// GetRevision mocks base method.
+func (m *MockstorageVault) GetRevision(ctx context.Context, userID int, itemID, revisionID int64) (models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, userID, itemID, revisionID)
+	ret0, _ := ret[0].(models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockstorageVaultMockRecorder) GetRevision(ctx, userID, itemID, revisionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockstorageVault)(nil).GetRevision), ctx, userID, itemID, revisionID)
+}
+
+// GetSharedItem mocks base method.
+func (m *MockstorageVault) GetSharedItem(ctx context.Context, userID int, itemID int64) (models.SharedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedItem", ctx, userID, itemID)
+	ret0, _ := ret[0].(models.SharedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedItem indicates an expected call of GetSharedItem.
+func (mr *MockstorageVaultMockRecorder) GetSharedItem(ctx, userID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedItem", reflect.TypeOf((*MockstorageVault)(nil).GetSharedItem), ctx, userID, itemID)
+}
+
+// ListFolders mocks base method.
+func (m *MockstorageVault) ListFolders(ctx context.Context, userID int) ([]models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolders", ctx, userID)
+	ret0, _ := ret[0].([]models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolders indicates an expected call of ListFolders.
+func (mr *MockstorageVaultMockRecorder) ListFolders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolders", reflect.TypeOf((*MockstorageVault)(nil).ListFolders), ctx, userID)
+}
+
+// ListRevisions mocks base method.
+func (m *MockstorageVault) ListRevisions(ctx context.Context, userID int, itemID int64) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, userID, itemID)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockstorageVaultMockRecorder) ListRevisions(ctx, userID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockstorageVault)(nil).ListRevisions), ctx, userID, itemID)
+}
+
+// ListSharedWithMe mocks base method.
+func (m *MockstorageVault) ListSharedWithMe(ctx context.Context, userID int) ([]models.SharedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSharedWithMe", ctx, userID)
+	ret0, _ := ret[0].([]models.SharedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSharedWithMe indicates an expected call of ListSharedWithMe.
+func (mr *MockstorageVaultMockRecorder) ListSharedWithMe(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSharedWithMe", reflect.TypeOf((*MockstorageVault)(nil).ListSharedWithMe), ctx, userID)
+}
+
+// ListShares mocks base method.
+func (m *MockstorageVault) ListShares(ctx context.Context, ownerID int, itemID int64) ([]models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, ownerID, itemID)
+	ret0, _ := ret[0].([]models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockstorageVaultMockRecorder) ListShares(ctx, ownerID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockstorageVault)(nil).ListShares), ctx, ownerID, itemID)
+}
+
+// ListTrash mocks base method.
+func (m *MockstorageVault) ListTrash(ctx context.Context, userID int) ([]models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockstorageVaultMockRecorder) ListTrash(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockstorageVault)(nil).ListTrash), ctx, userID)
+}
+
+// MoveItems mocks base method.
+func (m *MockstorageVault) MoveItems(ctx context.Context, userID int, ids []int64, folderID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveItems", ctx, userID, ids, folderID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveItems indicates an expected call of MoveItems.
+func (mr *MockstorageVaultMockRecorder) MoveItems(ctx, userID, ids, folderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveItems", reflect.TypeOf((*MockstorageVault)(nil).MoveItems), ctx, userID, ids, folderID)
+}
+
+// PruneRevisions mocks base method.
+func (m *MockstorageVault) PruneRevisions(ctx context.Context, itemID int64, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRevisions", ctx, itemID, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneRevisions indicates an expected call of PruneRevisions.
+func (mr *MockstorageVaultMockRecorder) PruneRevisions(ctx, itemID, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRevisions", reflect.TypeOf((*MockstorageVault)(nil).PruneRevisions), ctx, itemID, keep)
+}
+
+// PurgeItem mocks base method.
+func (m *MockstorageVault) PurgeItem(ctx context.Context, userID int, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeItem", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeItem indicates an expected call of PurgeItem.
+func (mr *MockstorageVaultMockRecorder) PurgeItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeItem", reflect.TypeOf((*MockstorageVault)(nil).PurgeItem), ctx, userID, id)
+}
+
+// RestoreItem mocks base method.
+func (m *MockstorageVault) RestoreItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreItem", ctx, userID, id)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreItem indicates an expected call of RestoreItem.
+func (mr *MockstorageVaultMockRecorder) RestoreItem(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreItem", reflect.TypeOf((*MockstorageVault)(nil).RestoreItem), ctx, userID, id)
+}
+
+// SearchItems mocks base method.
+func (m *MockstorageVault) SearchItems(ctx context.Context, userID int, filter models.SearchFilter, page models.Page) ([]models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchItems", ctx, userID, filter, page)
+	ret0, _ := ret[0].([]models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchItems indicates an expected call of SearchItems.
+func (mr *MockstorageVaultMockRecorder) SearchItems(ctx, userID, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchItems", reflect.TypeOf((*MockstorageVault)(nil).SearchItems), ctx, userID, filter, page)
+}
+
+// ShareItem mocks base method.
+func (m *MockstorageVault) ShareItem(ctx context.Context, ownerID int, share models.Share) (models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareItem", ctx, ownerID, share)
+	ret0, _ := ret[0].(models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareItem indicates an expected call of ShareItem.
+func (mr *MockstorageVaultMockRecorder) ShareItem(ctx, ownerID, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareItem", reflect.TypeOf((*MockstorageVault)(nil).ShareItem), ctx, ownerID, share)
+}
+
+// UnshareItem mocks base method.
+func (m *MockstorageVault) UnshareItem(ctx context.Context, ownerID int, itemID int64, recipientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareItem", ctx, ownerID, itemID, recipientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnshareItem indicates an expected call of UnshareItem.
+func (mr *MockstorageVaultMockRecorder) UnshareItem(ctx, ownerID, itemID, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareItem", reflect.TypeOf((*MockstorageVault)(nil).UnshareItem), ctx, ownerID, itemID, recipientID)
+}
+
+// UpdateFolder mocks base method.
+func (m *MockstorageVault) UpdateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFolder", ctx, folder)
+	ret0, _ := ret[0].(models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFolder indicates an expected call of UpdateFolder.
+func (mr *MockstorageVaultMockRecorder) UpdateFolder(ctx, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFolder", reflect.TypeOf((*MockstorageVault)(nil).UpdateFolder), ctx, folder)
+}
+
+// UpdateItem mocks base method.
+func (m *MockstorageVault) UpdateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, item)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockstorageVaultMockRecorder) UpdateItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockstorageVault)(nil).UpdateItem), ctx, item)
+}
+
+// UpdateSharedItem mocks base method.
+func (m *MockstorageVault) UpdateSharedItem(ctx context.Context, userID int, item models.Item) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSharedItem", ctx, userID, item)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSharedItem indicates an expected call of UpdateSharedItem.
+func (mr *MockstorageVaultMockRecorder) UpdateSharedItem(ctx, userID, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSharedItem", reflect.TypeOf((*MockstorageVault)(nil).UpdateSharedItem), ctx, userID, item)
+}
+
+// MockstorageTrash is a mock of storageTrash interface.
+type MockstorageTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockstorageTrashMockRecorder
+}
+
+// MockstorageTrashMockRecorder is the mock recorder for MockstorageTrash.
+type MockstorageTrashMockRecorder struct {
+	mock *MockstorageTrash
+}
+
+// NewMockstorageTrash creates a new mock instance.
+func NewMockstorageTrash(ctrl *gomock.Controller) *MockstorageTrash {
+	mock := &MockstorageTrash{ctrl: ctrl}
+	mock.recorder = &MockstorageTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockstorageTrash) EXPECT() *MockstorageTrashMockRecorder {
+	return m.recorder
+}
+
+// PurgeTrash mocks base method.
+func (m *MockstorageTrash) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockstorageTrashMockRecorder) PurgeTrash(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockstorageTrash)(nil).PurgeTrash), ctx, before)
+}
@@ -0,0 +1,175 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/storage/postgresql"
+	"strings"
+)
+
+// publicKeySize - размер открытого ключа X25519.
+const publicKeySize = 32
+
+var (
+	ErrInvalidKeyPair = errors.New("invalid key pair")
+	ErrInvalidShare   = errors.New("invalid share")
+	ErrReadOnlyShare  = errors.New("item is shared read-only")
+)
+
+// CreateKeyPair - проверяет и сохраняет пару ключей пользователя. Уже сохраненная
+// пара не заменяется и возвращается как есть.
+func (s *Service) CreateKeyPair(ctx context.Context, keys models.KeyPair) (models.KeyPair, error) {
+	if len(keys.PublicKey) != publicKeySize || len(keys.PrivateKey) == 0 {
+		return models.KeyPair{}, ErrInvalidKeyPair
+	}
+
+	return s.storage.CreateKeyPair(ctx, keys)
+}
+
+// GetKeyPair - возвращает пару ключей пользователя.
+func (s *Service) GetKeyPair(ctx context.Context, userID int) (models.KeyPair, error) {
+	return s.storage.GetKeyPair(ctx, userID)
+}
+
+// GetPublicKey - возвращает открытый ключ пользователя с логином login.
+func (s *Service) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	recipient, err := s.storage.GetRecipient(ctx, strings.TrimSpace(login))
+	if err != nil {
+		return nil, err
+	}
+	if len(recipient.PublicKey) == 0 {
+		return nil, postgresql.ErrKeyPairNotFound
+	}
+
+	return recipient.PublicKey, nil
+}
+
+// ShareItem - открывает пользователю recipientLogin доступ к записи владельца ownerID.
+// Повторная выдача тому же пользователю заменяет ключ и права.
+//
+// Открыть можно только запись, зашифрованную на клиенте, и только с ключом записи,
+// перешифрованным открытым ключом получателя (wrappedKey). Так у сервера никогда нет
+// читаемой копии данных, выданной другому пользователю.
+func (s *Service) ShareItem(ctx context.Context, ownerID int, itemID int64, recipientLogin string,
+	permission models.SharePermission, wrappedKey []byte) (models.Share, error) {
+	if permission != models.SharePermissionRead && permission != models.SharePermissionWrite {
+		return models.Share{}, ErrInvalidShare
+	}
+	if len(wrappedKey) == 0 {
+		return models.Share{}, ErrInvalidShare
+	}
+
+	item, err := s.storage.GetItem(ctx, ownerID, itemID)
+	if err != nil {
+		return models.Share{}, err
+	}
+	if !isSealed(item) {
+		return models.Share{}, ErrInvalidShare
+	}
+
+	recipient, err := s.storage.GetRecipient(ctx, strings.TrimSpace(recipientLogin))
+	if err != nil {
+		return models.Share{}, err
+	}
+	if recipient.UserID == ownerID {
+		return models.Share{}, ErrInvalidShare
+	}
+	// ключ записи зашифрован открытым ключом получателя, без пары ключей его не открыть
+	if len(recipient.PublicKey) == 0 {
+		return models.Share{}, postgresql.ErrKeyPairNotFound
+	}
+
+	share, err := s.storage.ShareItem(ctx, ownerID, models.Share{
+		ItemID:      itemID,
+		RecipientID: recipient.UserID,
+		WrappedKey:  wrappedKey,
+		Permission:  permission,
+	})
+	if err != nil {
+		return models.Share{}, err
+	}
+
+	share.RecipientLogin = strings.TrimSpace(recipientLogin)
+	return share, nil
+}
+
+// UnshareItem - закрывает пользователю recipientLogin доступ к записи владельца ownerID.
+func (s *Service) UnshareItem(ctx context.Context, ownerID int, itemID int64, recipientLogin string) error {
+	recipient, err := s.storage.GetRecipient(ctx, strings.TrimSpace(recipientLogin))
+	if err != nil {
+		if errors.Is(err, postgresql.ErrRecipientNotFound) {
+			return postgresql.ErrShareNotFound
+		}
+		return err
+	}
+
+	return s.storage.UnshareItem(ctx, ownerID, itemID, recipient.UserID)
+}
+
+// ListShares - возвращает получателей записи владельца ownerID.
+func (s *Service) ListShares(ctx context.Context, ownerID int, itemID int64) ([]models.Share, error) {
+	// пустой список не должен скрывать, что записи нет
+	if _, err := s.storage.GetItem(ctx, ownerID, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.storage.ListShares(ctx, ownerID, itemID)
+}
+
+// ListSharedWithMe - возвращает чужие записи, к которым пользователю открыт доступ.
+func (s *Service) ListSharedWithMe(ctx context.Context, userID int) ([]models.SharedItem, error) {
+	return s.storage.ListSharedWithMe(ctx, userID)
+}
+
+// UpdateSharedItem - перезаписывает чужую запись, если пользователю открыт доступ на изменение.
+// Данные зашифрованной записи должны остаться зашифрованными тем же ключом записи, иначе
+// владелец и другие получатели не смогут их открыть. Слепые токены поиска вычисляет только
+// владелец, поэтому у записи остаются прежние.
+func (s *Service) UpdateSharedItem(ctx context.Context, userID int, item models.Item) (models.SharedItem, error) {
+	if item.ID <= 0 {
+		return models.SharedItem{}, ErrInvalidItem
+	}
+	if err := validateItem(item); err != nil {
+		return models.SharedItem{}, err
+	}
+
+	shared, err := s.storage.GetSharedItem(ctx, userID, item.ID)
+	if err != nil {
+		return models.SharedItem{}, err
+	}
+	if shared.Permission != models.SharePermissionWrite {
+		return models.SharedItem{}, ErrReadOnlyShare
+	}
+	if old := sealedKey(shared.Item); old != nil && !bytes.Equal(old, sealedKey(item)) {
+		return models.SharedItem{}, ErrInvalidShare
+	}
+	item.BlindIndex = shared.Item.BlindIndex
+
+	updated, err := s.storage.UpdateSharedItem(ctx, userID, item)
+	if err != nil {
+		return models.SharedItem{}, err
+	}
+
+	s.pruneRevisions(ctx, updated.ID)
+
+	// папка владельца получателю не видна
+	updated.FolderID = 0
+	shared.Item = updated
+	return shared, nil
+}
+
+// isSealed - зашифрованы ли данные записи на клиенте.
+func isSealed(item models.Item) bool {
+	return sealedKey(item) != nil
+}
+
+// sealedKey - зашифрованный ключ записи, nil - данные записи не зашифрованы на клиенте.
+func sealedKey(item models.Item) []byte {
+	payload, err := models.DecodePayload(item.Payload)
+	if err != nil || payload.Sealed == nil || len(payload.Sealed.WrappedKey) == 0 {
+		return nil
+	}
+	return payload.Sealed.WrappedKey
+}
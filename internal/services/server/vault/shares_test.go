@@ -0,0 +1,232 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"goph-keeper/internal/models"
+	"goph-keeper/internal/storage/postgresql"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestService_ShareItem(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	publicKey := bytes.Repeat([]byte{1}, publicKeySize)
+	sealed := models.Item{ID: 7, UserID: 1, Type: models.ItemTypeLogin,
+		Payload: []byte(`{"sealed":{"ciphertext":"AQ==","wrappedKey":"Ag=="}}`)}
+	plain := models.Item{ID: 7, UserID: 1, Type: models.ItemTypeNote, Payload: []byte(`{"note":{"text":"memo"}}`)}
+
+	cases := []struct {
+		name       string
+		item       models.Item
+		recipient  models.KeyPair
+		permission models.SharePermission
+		wrappedKey []byte
+		wantErr    error
+	}{
+		{
+			name:       "read_only",
+			item:       sealed,
+			recipient:  models.KeyPair{UserID: 2, PublicKey: publicKey},
+			permission: models.SharePermissionRead,
+			wrappedKey: []byte("wrapped"),
+		},
+		{
+			name:       "write",
+			item:       sealed,
+			recipient:  models.KeyPair{UserID: 2, PublicKey: publicKey},
+			permission: models.SharePermissionWrite,
+			wrappedKey: []byte("wrapped"),
+		},
+		{
+			// без ключа записи серверу пришлось бы выдать получателю читаемую копию
+			name:       "without_wrapped_key",
+			item:       sealed,
+			recipient:  models.KeyPair{UserID: 2, PublicKey: publicKey},
+			permission: models.SharePermissionWrite,
+			wantErr:    ErrInvalidShare,
+		},
+		{
+			name:       "plain_item",
+			item:       plain,
+			recipient:  models.KeyPair{UserID: 2, PublicKey: publicKey},
+			permission: models.SharePermissionRead,
+			wrappedKey: []byte("wrapped"),
+			wantErr:    ErrInvalidShare,
+		},
+		{
+			name:       "without_key_pair",
+			item:       sealed,
+			recipient:  models.KeyPair{UserID: 2},
+			permission: models.SharePermissionRead,
+			wrappedKey: []byte("wrapped"),
+			wantErr:    postgresql.ErrKeyPairNotFound,
+		},
+		{
+			name:       "to_self",
+			item:       sealed,
+			recipient:  models.KeyPair{UserID: 1, PublicKey: publicKey},
+			permission: models.SharePermissionRead,
+			wrappedKey: []byte("wrapped"),
+			wantErr:    ErrInvalidShare,
+		},
+	}
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			storage := NewMockstorageVault(ctrl)
+
+			if len(cc.wrappedKey) > 0 {
+				storage.EXPECT().GetItem(ctx, 1, int64(7)).Return(cc.item, nil)
+				if isSealed(cc.item) {
+					storage.EXPECT().GetRecipient(ctx, "bob").Return(cc.recipient, nil)
+				}
+			}
+			if cc.wantErr == nil {
+				storage.EXPECT().ShareItem(ctx, 1, models.Share{
+					ItemID:      7,
+					RecipientID: cc.recipient.UserID,
+					WrappedKey:  cc.wrappedKey,
+					Permission:  cc.permission,
+				}).Return(models.Share{ID: 3, ItemID: 7, RecipientID: 2, Permission: cc.permission}, nil)
+			}
+
+			serv := NewService(log, storage, 0)
+
+			share, err := serv.ShareItem(ctx, 1, 7, " bob ", cc.permission, cc.wrappedKey)
+			if !errors.Is(err, cc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %v", err, cc.wantErr)
+			}
+			if err == nil && (share.ID != 3 || share.RecipientLogin != "bob") {
+				t.Errorf("unexpected share: %+v", share)
+			}
+		})
+	}
+}
+
+func TestService_ShareItem_InvalidPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageVault(ctrl)
+
+	serv := NewService(slog.New(slog.NewTextHandler(os.Stdout, nil)), storage, 0)
+
+	_, err := serv.ShareItem(context.Background(), 1, 7, "bob", models.SharePermissionUnspecified, nil)
+	if !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("unexpected error: got %v, want %v", err, ErrInvalidShare)
+	}
+}
+
+func TestService_UpdateSharedItem(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	item := models.Item{ID: 7, Type: models.ItemTypeNote, Title: "memo", Payload: []byte(`{"note":{"text":"v2"}}`)}
+
+	cases := []struct {
+		name       string
+		permission models.SharePermission
+		wantErr    error
+	}{
+		{
+			name:       "write",
+			permission: models.SharePermissionWrite,
+		},
+		{
+			name:       "read_only",
+			permission: models.SharePermissionRead,
+			wantErr:    ErrReadOnlyShare,
+		},
+	}
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			storage := NewMockstorageVault(ctrl)
+
+			storage.EXPECT().GetSharedItem(ctx, 2, int64(7)).Return(models.SharedItem{
+				Item:       models.Item{ID: 7, UserID: 1, FolderID: 5},
+				OwnerLogin: "alice",
+				Permission: cc.permission,
+			}, nil)
+			if cc.wantErr == nil {
+				updated := item
+				updated.UserID, updated.FolderID = 1, 5
+				storage.EXPECT().UpdateSharedItem(ctx, 2, item).Return(updated, nil)
+			}
+
+			serv := NewService(log, storage, 0)
+
+			shared, err := serv.UpdateSharedItem(ctx, 2, item)
+			if !errors.Is(err, cc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %v", err, cc.wantErr)
+			}
+			// папка владельца не уходит получателю
+			if err == nil && (shared.OwnerLogin != "alice" || shared.Item.FolderID != 0 || shared.Item.Title != "memo") {
+				t.Errorf("unexpected shared item: %+v", shared)
+			}
+		})
+	}
+}
+
+func TestService_UpdateSharedItem_Sealed(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	stored := models.Item{ID: 7, UserID: 1, Type: models.ItemTypeLogin, Title: "vpn", BlindIndex: []string{"t1"},
+		Payload: []byte(`{"sealed":{"ciphertext":"AQ==","wrappedKey":"Ag=="}}`)}
+
+	cases := []struct {
+		name    string
+		payload string
+		wantErr error
+	}{
+		{
+			name:    "same_item_key",
+			payload: `{"sealed":{"ciphertext":"Aw==","wrappedKey":"Ag=="}}`,
+		},
+		{
+			// владелец не открыл бы данные, зашифрованные другим ключом
+			name:    "other_item_key",
+			payload: `{"sealed":{"ciphertext":"Aw==","wrappedKey":"BA=="}}`,
+			wantErr: ErrInvalidShare,
+		},
+		{
+			name:    "plain",
+			payload: `{"login":{"password":"x"}}`,
+			wantErr: ErrInvalidShare,
+		},
+	}
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			storage := NewMockstorageVault(ctrl)
+
+			item := models.Item{ID: 7, Type: models.ItemTypeLogin, Title: "vpn", Payload: []byte(cc.payload)}
+			storage.EXPECT().GetSharedItem(ctx, 2, int64(7)).Return(models.SharedItem{
+				Item:       stored,
+				OwnerLogin: "alice",
+				Permission: models.SharePermissionWrite,
+			}, nil)
+			if cc.wantErr == nil {
+				// слепые токены владельца остаются у записи
+				want := item
+				want.BlindIndex = stored.BlindIndex
+				storage.EXPECT().UpdateSharedItem(ctx, 2, want).Return(want, nil)
+			}
+
+			serv := NewService(log, storage, 0)
+
+			if _, err := serv.UpdateSharedItem(ctx, 2, item); !errors.Is(err, cc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %v", err, cc.wantErr)
+			}
+		})
+	}
+}
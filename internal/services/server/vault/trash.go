@@ -0,0 +1,53 @@
+package vault
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"log/slog"
+	"time"
+)
+
+// ListTrash - возвращает записи пользователя из корзины.
+func (s *Service) ListTrash(ctx context.Context, userID int) ([]models.Item, error) {
+	return s.storage.ListTrash(ctx, userID)
+}
+
+// RestoreItem - возвращает запись пользователя из корзины.
+func (s *Service) RestoreItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	return s.storage.RestoreItem(ctx, userID, id)
+}
+
+// PurgeItem - окончательно удаляет запись пользователя из корзины.
+func (s *Service) PurgeItem(ctx context.Context, userID int, id int64) error {
+	return s.storage.PurgeItem(ctx, userID, id)
+}
+
+// TrashPurger - задача воркера: окончательно удаляет записи, пролежавшие в корзине дольше retention.
+type TrashPurger struct {
+	log       *slog.Logger
+	storage   storageTrash
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewTrashPurger - конструктор задачи очистки корзины.
+func NewTrashPurger(log *slog.Logger, storage storageTrash, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		log:       log,
+		storage:   storage,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Do - удаляет просроченные записи корзины. Ошибка только логируется: следующий запуск повторит очистку.
+func (p *TrashPurger) Do(ctx context.Context) {
+	purged, err := p.storage.PurgeTrash(ctx, p.now().Add(-p.retention))
+	if err != nil {
+		p.log.Error("failed to purge trash", "error", err)
+		return
+	}
+	if purged > 0 {
+		p.log.Info("trash purged", "items", purged)
+	}
+}
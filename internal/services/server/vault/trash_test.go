@@ -0,0 +1,31 @@
+package vault
+
+import (
+	"context"
+	"database/sql"
+	"github.com/golang/mock/gomock"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTrashPurger_Do(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storage := NewMockstorageTrash(ctrl)
+
+	now := time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)
+	// удаляются записи, попавшие в корзину раньше now - retention
+	storage.EXPECT().PurgeTrash(ctx, now.Add(-30*24*time.Hour)).Return(int64(2), nil)
+	storage.EXPECT().PurgeTrash(ctx, now.Add(-30*24*time.Hour)).Return(int64(0), sql.ErrConnDone)
+
+	purger := NewTrashPurger(log, storage, 30*24*time.Hour)
+	purger.now = func() time.Time { return now }
+
+	purger.Do(ctx)
+	// ошибка не паникует и не останавливает воркер
+	purger.Do(ctx)
+}
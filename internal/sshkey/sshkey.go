@@ -0,0 +1,83 @@
+// Package sshkey - разбор SSH-ключей для записей хранилища.
+package sshkey
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"goph-keeper/internal/models"
+	"strings"
+)
+
+var (
+	ErrPassphraseRequired = errors.New("private key is protected by a passphrase")
+	ErrInvalidKey         = errors.New("invalid ssh private key")
+)
+
+// FromPrivateKey - запись SSH-ключа из закрытого ключа в любом формате, который понимает
+// OpenSSH. Ключ с парольной фразой расшифровывается и сохраняется без нее: его защищает
+// хранилище.
+func FromPrivateKey(pemBytes, passphrase []byte, comment string) (models.SSHKeyPayload, error) {
+	var (
+		raw any
+		err error
+	)
+	if len(passphrase) > 0 {
+		raw, err = ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, passphrase)
+	} else {
+		raw, err = ssh.ParseRawPrivateKey(pemBytes)
+	}
+
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missing):
+		return models.SSHKeyPayload{}, ErrPassphraseRequired
+	case err != nil:
+		return models.SSHKeyPayload{}, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(raw, comment)
+	if err != nil {
+		return models.SSHKeyPayload{}, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(raw)
+	if err != nil {
+		return models.SSHKeyPayload{}, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	return models.SSHKeyPayload{
+		PrivateKey:  string(pem.EncodeToMemory(block)),
+		PublicKey:   AuthorizedKey(signer.PublicKey(), comment),
+		Comment:     comment,
+		Fingerprint: ssh.FingerprintSHA256(signer.PublicKey()),
+	}, nil
+}
+
+// AuthorizedKey - открытый ключ строкой authorized_keys с комментарием.
+func AuthorizedKey(pub ssh.PublicKey, comment string) string {
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// CommentFromPublicKey - комментарий из строки authorized_keys (файла .pub).
+func CommentFromPublicKey(line []byte) string {
+	_, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	if err != nil {
+		return ""
+	}
+	return comment
+}
+
+// Signer - подписчик по закрытому ключу записи.
+func Signer(key models.SSHKeyPayload) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	return signer, nil
+}
@@ -0,0 +1,96 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"golang.org/x/crypto/ssh"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T, passphrase string) (ed25519.PrivateKey, []byte) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, pem.EncodeToMemory(block)
+}
+
+func TestFromPrivateKey(t *testing.T) {
+	priv, pemBytes := newKey(t, "")
+
+	key, err := FromPrivateKey(pemBytes, nil, "me@laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Fingerprint != ssh.FingerprintSHA256(pub) {
+		t.Errorf("fingerprint = %q, want %q", key.Fingerprint, ssh.FingerprintSHA256(pub))
+	}
+	if !strings.HasPrefix(key.PublicKey, "ssh-ed25519 ") || !strings.HasSuffix(key.PublicKey, " me@laptop") {
+		t.Errorf("unexpected public key %q", key.PublicKey)
+	}
+
+	signer, err := Signer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := signer.Sign(rand.Reader, []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.Verify([]byte("data"), sig); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+}
+
+func TestFromPrivateKeyPassphrase(t *testing.T) {
+	_, pemBytes := newKey(t, "secret")
+
+	if _, err := FromPrivateKey(pemBytes, nil, ""); !errors.Is(err, ErrPassphraseRequired) {
+		t.Fatalf("err = %v, want ErrPassphraseRequired", err)
+	}
+	if _, err := FromPrivateKey(pemBytes, []byte("wrong"), ""); err == nil {
+		t.Fatal("expected an error for a wrong passphrase")
+	}
+
+	key, err := FromPrivateKey(pemBytes, []byte("secret"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// в хранилище ключ лежит без парольной фразы
+	if _, err := ssh.ParsePrivateKey([]byte(key.PrivateKey)); err != nil {
+		t.Errorf("stored key must not require a passphrase: %v", err)
+	}
+}
+
+func TestFromPrivateKeyInvalid(t *testing.T) {
+	if _, err := FromPrivateKey([]byte("not a key"), nil, ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("err = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestCommentFromPublicKey(t *testing.T) {
+	priv, _ := newKey(t, "")
+	pub, _ := ssh.NewPublicKey(priv.Public())
+	if got := CommentFromPublicKey([]byte(AuthorizedKey(pub, "me@host") + "\n")); got != "me@host" {
+		t.Errorf("comment = %q", got)
+	}
+}
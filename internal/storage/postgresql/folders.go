@@ -0,0 +1,226 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"goph-keeper/internal/models"
+)
+
+var (
+	ErrFolderNotFound = errors.New("folder not found")
+	ErrFolderExists   = errors.New("folder with this name already exists")
+	ErrFolderCycle    = errors.New("folder cannot be moved into itself or its subfolder")
+)
+
+// uniqueViolation - код ошибки PostgreSQL при нарушении уникального индекса.
+const uniqueViolation = "23505"
+
+// folderColumns - перечень колонок таблицы folders в порядке сканирования.
+// Папка в корне сканируется с ParentID 0.
+const folderColumns = "id, user_id, COALESCE(parent_id, 0), name, created_at, updated_at"
+
+// scanFolder - сканирует строку таблицы folders в models.Folder.
+func scanFolder(row rowScanner) (models.Folder, error) {
+	var folder models.Folder
+	err := row.Scan(
+		&folder.ID,
+		&folder.UserID,
+		&folder.ParentID,
+		&folder.Name,
+		&folder.CreatedAt,
+		&folder.UpdatedAt,
+	)
+	return folder, err
+}
+
+// folderError - переводит ошибки записи папки в ошибки storage.
+func (p *Postgresql) folderError(msg string, err error) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrFolderNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == uniqueViolation:
+		return ErrFolderExists
+	default:
+		p.log.Error(msg, "error", err)
+		return err
+	}
+}
+
+// CreateFolder - сохраняет новую папку пользователя. Родительская папка должна принадлежать
+// тому же пользователю, иначе возвращается ErrFolderNotFound.
+func (p *Postgresql) CreateFolder(ctx context.Context, folder models.Folder) (models.Folder, error) {
+	query := `INSERT INTO folders (user_id, parent_id, name)
+		SELECT $1, NULLIF($2::BIGINT, 0), $3
+		WHERE $2::BIGINT = 0 OR EXISTS (SELECT 1 FROM folders WHERE id = $2 AND user_id = $1)
+		RETURNING ` + folderColumns
+
+	created, err := scanFolder(p.storage.QueryRowContext(ctx, query, folder.UserID, folder.ParentID, folder.Name))
+	if err != nil {
+		return models.Folder{}, p.folderError("failed to create folder", err)
+	}
+
+	return created, nil
+}
+
+// ListFolders - возвращает все папки пользователя.
+func (p *Postgresql) ListFolders(ctx context.Context, userID int) ([]models.Folder, error) {
+	query := `SELECT ` + folderColumns + ` FROM folders WHERE user_id = $1 ORDER BY name, id`
+
+	rows, err := p.storage.QueryContext(ctx, query, userID)
+	if err != nil {
+		p.log.Error("failed to list folders", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []models.Folder
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			p.log.Error("failed to scan folder", "error", err)
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.Error("failed to iterate folders", "error", err)
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// UpdateFolder - переименовывает папку пользователя и переносит ее к другому родителю.
+// Папку нельзя перенести в саму себя или во вложенную в нее папку (ErrFolderCycle).
+// Проверка и перенос идут в одной транзакции, а папки пользователя на это время
+// блокируются: два встречных переноса (A в B и B в A) не сохранят цикл.
+func (p *Postgresql) UpdateFolder(ctx context.Context, folder models.Folder) (updated models.Folder, err error) {
+	tx, err := p.storage.BeginTx(ctx, nil)
+	if err != nil {
+		p.log.Error("failed to begin transaction", "error", err)
+		return models.Folder{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `SELECT id FROM folders WHERE user_id = $1 ORDER BY id FOR UPDATE`, folder.UserID); err != nil {
+		p.log.Error("failed to lock folders", "error", err)
+		return models.Folder{}, err
+	}
+
+	if folder.ParentID != 0 {
+		// UNION отбрасывает повторы, поэтому обход закончится даже на цикле в базе
+		query := `WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM folders WHERE id = $1 AND user_id = $2
+				UNION
+				SELECT f.id, f.parent_id FROM folders f JOIN ancestors a ON f.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $3)`
+
+		var cycle bool
+		if err = tx.QueryRowContext(ctx, query, folder.ParentID, folder.UserID, folder.ID).Scan(&cycle); err != nil {
+			p.log.Error("failed to check folder ancestors", "error", err)
+			return models.Folder{}, err
+		}
+		if cycle {
+			err = ErrFolderCycle
+			return models.Folder{}, err
+		}
+	}
+
+	query := `UPDATE folders
+		SET name = $1, parent_id = NULLIF($2::BIGINT, 0), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND user_id = $4
+		  AND ($2::BIGINT = 0 OR EXISTS (SELECT 1 FROM folders WHERE id = $2 AND user_id = $4))
+		RETURNING ` + folderColumns
+
+	updated, err = scanFolder(tx.QueryRowContext(ctx, query, folder.Name, folder.ParentID, folder.ID, folder.UserID))
+	if err != nil {
+		err = p.folderError("failed to update folder", err)
+		return models.Folder{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		p.log.Error("failed to commit transaction", "error", err)
+		return models.Folder{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteFolder - удаляет папку пользователя вместе с вложенными папками. Записи из них
+// переносятся в корень; updated_at меняется, чтобы перенос увидели клиенты.
+func (p *Postgresql) DeleteFolder(ctx context.Context, userID int, id int64) (err error) {
+	tx, err := p.storage.BeginTx(ctx, nil)
+	if err != nil {
+		p.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = $1 AND user_id = $2
+			UNION
+			SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id
+		)
+		UPDATE items SET folder_id = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $2 AND folder_id IN (SELECT id FROM subtree)`
+	if _, err = tx.ExecContext(ctx, query, id, userID); err != nil {
+		p.log.Error("failed to move items out of folder", "error", err)
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM folders WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		p.log.Error("failed to delete folder", "error", err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		p.log.Error("failed to get affected rows", "error", err)
+		return err
+	}
+	if n == 0 {
+		err = ErrFolderNotFound
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		p.log.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MoveItems - переносит записи пользователя в папку, folderID 0 - в корень.
+// Возвращает число перенесенных записей; записи корзины не переносятся.
+func (p *Postgresql) MoveItems(ctx context.Context, userID int, ids []int64, folderID int64) (int64, error) {
+	query := `UPDATE items SET folder_id = NULLIF($1::BIGINT, 0), updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $2 AND id = ANY($3) AND deleted_at IS NULL
+		  AND ($1::BIGINT = 0 OR EXISTS (SELECT 1 FROM folders WHERE id = $1 AND user_id = $2))`
+
+	res, err := p.storage.ExecContext(ctx, query, folderID, userID, ids)
+	if err != nil {
+		p.log.Error("failed to move items", "error", err)
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		p.log.Error("failed to get affected rows", "error", err)
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,197 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"goph-keeper/internal/models"
+)
+
+var (
+	ErrItemNotFound = errors.New("item not found")
+)
+
+// itemColumns - перечень колонок таблицы items в порядке сканирования.
+// Запись вне папок сканируется с FolderID 0.
+const itemColumns = "id, user_id, type, title, tags, favorite, COALESCE(folder_id, 0), blind_index, payload, created_at, updated_at, deleted_at"
+
+// rowScanner - общий интерфейс для *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanItem - сканирует строку таблицы items в models.Item.
+func (p *Postgresql) scanItem(row rowScanner) (models.Item, error) {
+	var (
+		item      models.Item
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Type,
+		&item.Title,
+		p.typeMap.SQLScanner(&item.Tags),
+		&item.Favorite,
+		&item.FolderID,
+		p.typeMap.SQLScanner(&item.BlindIndex),
+		&item.Payload,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&deletedAt,
+	)
+	item.DeletedAt = deletedAt.Time
+	return item, err
+}
+
+// CreateItem - сохраняет новую запись и ее первую ревизию, возвращает запись с присвоенным id.
+func (p *Postgresql) CreateItem(ctx context.Context, item models.Item) (created models.Item, err error) {
+	// папка, которой нет у пользователя (например, удаленная на другом устройстве), заменяется корнем
+	query := `INSERT INTO items (user_id, type, title, tags, favorite, blind_index, payload, folder_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT id FROM folders WHERE id = $8 AND user_id = $1))
+		RETURNING ` + itemColumns
+
+	tx, err := p.storage.BeginTx(ctx, nil)
+	if err != nil {
+		p.log.Error("failed to begin transaction", "error", err)
+		return models.Item{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	created, err = p.scanItem(tx.QueryRowContext(ctx, query,
+		item.UserID, item.Type, item.Title, tagsOrEmpty(item.Tags), item.Favorite,
+		tagsOrEmpty(item.BlindIndex), item.Payload, item.FolderID))
+	if err != nil {
+		p.log.Error("failed to create item", "error", err)
+		return models.Item{}, err
+	}
+
+	if err = p.insertRevision(ctx, tx, created, item.UserID); err != nil {
+		return models.Item{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		p.log.Error("failed to commit transaction", "error", err)
+		return models.Item{}, err
+	}
+
+	return created, nil
+}
+
+// GetItem - возвращает запись пользователя по id.
+func (p *Postgresql) GetItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+
+	item, err := p.scanItem(p.storage.QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrItemNotFound
+		}
+		p.log.Error("failed to get item", "error", err)
+		return models.Item{}, err
+	}
+
+	return item, nil
+}
+
+// UpdateItem - перезаписывает метаданные и данные записи пользователя и сохраняет
+// новое состояние ревизией.
+func (p *Postgresql) UpdateItem(ctx context.Context, item models.Item) (updated models.Item, err error) {
+	query := `UPDATE items
+		SET type = $1, title = $2, tags = $3, favorite = $4, blind_index = $5, payload = $6,
+			folder_id = (SELECT id FROM folders WHERE id = $9 AND user_id = $8),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7 AND user_id = $8 AND deleted_at IS NULL
+		RETURNING ` + itemColumns
+
+	tx, err := p.storage.BeginTx(ctx, nil)
+	if err != nil {
+		p.log.Error("failed to begin transaction", "error", err)
+		return models.Item{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	updated, err = p.scanItem(tx.QueryRowContext(ctx, query,
+		item.Type, item.Title, tagsOrEmpty(item.Tags), item.Favorite, tagsOrEmpty(item.BlindIndex),
+		item.Payload, item.ID, item.UserID, item.FolderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrItemNotFound
+		}
+		p.log.Error("failed to update item", "error", err)
+		return models.Item{}, err
+	}
+
+	if err = p.insertRevision(ctx, tx, updated, item.UserID); err != nil {
+		return models.Item{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		p.log.Error("failed to commit transaction", "error", err)
+		return models.Item{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteItem - перемещает запись пользователя в корзину. updated_at меняется, чтобы
+// удаление увидели клиенты, которые синхронизируются по дате изменения.
+func (p *Postgresql) DeleteItem(ctx context.Context, userID int, id int64) error {
+	query := `UPDATE items SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+
+	res, err := p.storage.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		p.log.Error("failed to delete item", "error", err)
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		p.log.Error("failed to get affected rows", "error", err)
+		return err
+	}
+	if n == 0 {
+		return ErrItemNotFound
+	}
+
+	return nil
+}
+
+// scanItems - сканирует все строки выборки и закрывает rows.
+func (p *Postgresql) scanItems(rows *sql.Rows) ([]models.Item, error) {
+	defer rows.Close()
+
+	var items []models.Item
+	for rows.Next() {
+		item, err := p.scanItem(rows)
+		if err != nil {
+			p.log.Error("failed to scan item", "error", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.Error("failed to iterate items", "error", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// tagsOrEmpty - заменяет nil на пустой срез, чтобы не нарушать NOT NULL колонок-массивов.
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
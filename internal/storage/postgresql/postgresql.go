@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"github.com/jackc/pgx/v5/pgtype"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 	"log/slog"
@@ -17,13 +18,15 @@ var (
 // Postgresql - подключение к базе данных.
 type Postgresql struct {
 	storage *sql.DB
+	typeMap *pgtype.Map
 	log     *slog.Logger
 }
 
 // NewPostgresql - конструктор, который возвращает Postgresql и error.
 func NewPostgresql(log *slog.Logger) (*Postgresql, error) {
 	p := &Postgresql{
-		log: log,
+		typeMap: pgtype.NewMap(),
+		log:     log,
 	}
 	err := p.initDB()
 	return p, err
@@ -127,63 +130,6 @@ func (p *Postgresql) SaveTableUserAndUpdateToken(login, accessToken string) erro
 	return nil
 }
 
-// SaveLoginAndPasswordInCredentials - сохраняет полученный логин и пароль от ресурса в базу.
-func (p *Postgresql) SaveLoginAndPasswordInCredentials(
-	ctx context.Context,
-	userID int,
-	resource string,
-	login string,
-	password string) error {
-	query := `INSERT INTO credentials (user_id,resource, login, password) VALUES ($1, $2, $3, $4)`
-
-	_, err := p.storage.ExecContext(ctx, query, userID, resource, login, password)
-	if err != nil {
-		p.log.Error("failed to save in credentials", "error", err)
-		return err
-	}
-
-	return nil
-}
-
-// SaveTextData - сохраняет получены текст в базу.
-func (p *Postgresql) SaveTextData(ctx context.Context, userID int, data string) error {
-	query := `INSERT INTO text_data (user_id, data) VALUES ($1, $2)`
-
-	_, err := p.storage.ExecContext(ctx, query, userID, data)
-	if err != nil {
-		p.log.Error("failed to save in credentials", "error", err)
-		return err
-	}
-
-	return nil
-}
-
-// SaveBinaryData - сохраняет полученные бинарные данные.
-func (p *Postgresql) SaveBinaryData(ctx context.Context, uid int, data string) error {
-	query := `INSERT INTO binary_data (user_id, data) VALUES ($1, $2)`
-
-	_, err := p.storage.ExecContext(ctx, query, uid, data)
-	if err != nil {
-		p.log.Error("failed to save in credentials", "error", err)
-		return err
-	}
-
-	return nil
-}
-
-// SaveCards - сохраняет полученные данные по картам в базу.
-func (p *Postgresql) SaveCards(ctx context.Context, userID int, cards string) error {
-	query := `INSERT INTO cards (user_id, cards) VALUES ($1, $2)`
-
-	_, err := p.storage.ExecContext(ctx, query, userID, cards)
-	if err != nil {
-		p.log.Error("failed to save in cards", "error", err)
-		return err
-	}
-
-	return nil
-}
-
 // GetUserIDByToken - получает user_id по токену.
 func (p *Postgresql) GetUserIDByToken(ctx context.Context, token string) (int, error) {
 	query := "SELECT id FROM users WHERE token = $1"
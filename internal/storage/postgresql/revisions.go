@@ -0,0 +1,123 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"goph-keeper/internal/models"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// revisionColumns - перечень колонок выборки ревизий в порядке сканирования.
+// Владелец ревизии - владелец записи, поэтому user_id берется из items.
+const revisionColumns = `r.id, r.item_id, i.user_id, r.type, r.title, r.tags, r.favorite, r.blind_index,
+	r.payload, i.created_at, r.created_at, r.changed_by, COALESCE(u.login, '')`
+
+// revisionFrom - выборка ревизий записи вместе с владельцем и автором изменения.
+const revisionFrom = ` FROM item_revisions r
+	JOIN items i ON i.id = r.item_id
+	LEFT JOIN users u ON u.id = r.changed_by`
+
+// scanRevision - сканирует строку выборки ревизий в models.Revision.
+func (p *Postgresql) scanRevision(row rowScanner) (models.Revision, error) {
+	var rev models.Revision
+	err := row.Scan(
+		&rev.ID,
+		&rev.Item.ID,
+		&rev.Item.UserID,
+		&rev.Item.Type,
+		&rev.Item.Title,
+		p.typeMap.SQLScanner(&rev.Item.Tags),
+		&rev.Item.Favorite,
+		p.typeMap.SQLScanner(&rev.Item.BlindIndex),
+		&rev.Item.Payload,
+		&rev.Item.CreatedAt,
+		&rev.CreatedAt,
+		&rev.ChangedBy,
+		&rev.ChangedByLogin,
+	)
+	rev.Item.UpdatedAt = rev.CreatedAt
+	return rev, err
+}
+
+// insertRevision - сохраняет состояние записи ревизией в рамках транзакции изменения.
+func (p *Postgresql) insertRevision(ctx context.Context, tx *sql.Tx, item models.Item, changedBy int) error {
+	query := `INSERT INTO item_revisions (item_id, changed_by, type, title, tags, favorite, blind_index, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := tx.ExecContext(ctx, query,
+		item.ID, changedBy, item.Type, item.Title, tagsOrEmpty(item.Tags), item.Favorite,
+		tagsOrEmpty(item.BlindIndex), item.Payload, item.UpdatedAt)
+	if err != nil {
+		p.log.Error("failed to save item revision", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListRevisions - возвращает ревизии записи пользователя от новых к старым.
+func (p *Postgresql) ListRevisions(ctx context.Context, userID int, itemID int64) ([]models.Revision, error) {
+	query := `SELECT ` + revisionColumns + revisionFrom + `
+		WHERE r.item_id = $1 AND i.user_id = $2
+		ORDER BY r.id DESC`
+
+	rows, err := p.storage.QueryContext(ctx, query, itemID, userID)
+	if err != nil {
+		p.log.Error("failed to list item revisions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []models.Revision
+	for rows.Next() {
+		rev, err := p.scanRevision(rows)
+		if err != nil {
+			p.log.Error("failed to scan item revision", "error", err)
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.Error("failed to iterate item revisions", "error", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetRevision - возвращает ревизию записи пользователя по id.
+func (p *Postgresql) GetRevision(ctx context.Context, userID int, itemID, revisionID int64) (models.Revision, error) {
+	query := `SELECT ` + revisionColumns + revisionFrom + `
+		WHERE r.id = $1 AND r.item_id = $2 AND i.user_id = $3`
+
+	rev, err := p.scanRevision(p.storage.QueryRowContext(ctx, query, revisionID, itemID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Revision{}, ErrRevisionNotFound
+		}
+		p.log.Error("failed to get item revision", "error", err)
+		return models.Revision{}, err
+	}
+
+	return rev, nil
+}
+
+// PruneRevisions - оставляет у записи только keep последних ревизий.
+func (p *Postgresql) PruneRevisions(ctx context.Context, itemID int64, keep int) error {
+	query := `DELETE FROM item_revisions
+		WHERE item_id = $1 AND id NOT IN (
+			SELECT id FROM item_revisions WHERE item_id = $1 ORDER BY id DESC LIMIT $2
+		)`
+
+	if _, err := p.storage.ExecContext(ctx, query, itemID, keep); err != nil {
+		p.log.Error("failed to prune item revisions", "error", err)
+		return err
+	}
+
+	return nil
+}
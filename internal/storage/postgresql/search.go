@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"goph-keeper/internal/models"
+	"strings"
+)
+
+// likeEscaper - экранирует спецсимволы шаблона LIKE в пользовательском запросе.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchItems - ищет записи пользователя по метаданным и возвращает одну страницу.
+// Условия собираются только из заданных фильтров, чтобы планировщик мог использовать индексы:
+// GIN по tags и blind_index, триграммы по title и resource, (user_id, updated_at, id) и
+// (user_id, title, id) для сортировки и курсора. Зашифрованное содержимое записи в поиске не участвует.
+func (p *Postgresql) SearchItems(ctx context.Context, userID int, filter models.SearchFilter, page models.Page) ([]models.Item, error) {
+	conditions := []string{"user_id = $1"}
+	args := []any{userID}
+
+	addArg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if filter.Type != models.ItemTypeUnspecified {
+		conditions = append(conditions, "type = "+addArg(filter.Type))
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "tags @> ARRAY["+addArg(filter.Tag)+"]::TEXT[]")
+	}
+	if filter.Query != "" {
+		pattern := addArg("%" + likeEscaper.Replace(filter.Query) + "%")
+		conditions = append(conditions, "(title ILIKE "+pattern+" OR resource ILIKE "+pattern+")")
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		conditions = append(conditions, "updated_at > "+addArg(filter.UpdatedAfter))
+	}
+	if filter.FavoritesOnly {
+		conditions = append(conditions, "favorite")
+	}
+	if len(filter.BlindTokens) > 0 {
+		conditions = append(conditions, "blind_index @> "+addArg(filter.BlindTokens))
+	}
+
+	column, cast := "updated_at", "::TIMESTAMPTZ"
+	if page.Sort.Field == models.SortByTitle {
+		column, cast = "title", ""
+	}
+	direction, operator := "ASC", ">"
+	if page.Sort.Descending {
+		direction, operator = "DESC", "<"
+	}
+
+	// курсор: строки строго после последней выданной записи в порядке сортировки
+	if page.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s%s, %s)",
+			column, operator, addArg(page.After.Value), cast, addArg(page.After.ID)))
+	}
+
+	query := `SELECT ` + itemColumns + ` FROM items
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + column + ` ` + direction + `, id ` + direction + `
+		LIMIT ` + addArg(page.Limit)
+
+	rows, err := p.storage.QueryContext(ctx, query, args...)
+	if err != nil {
+		p.log.Error("failed to search items", "error", err)
+		return nil, err
+	}
+
+	return p.scanItems(rows)
+}
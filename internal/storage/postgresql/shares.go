@@ -0,0 +1,277 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"goph-keeper/internal/models"
+)
+
+var (
+	ErrKeyPairNotFound   = errors.New("key pair not found")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrShareNotFound     = errors.New("share not found")
+)
+
+// sharedItemColumns - колонки выборки чужих записей: запись, логин владельца и доступ.
+const sharedItemColumns = `i.id, i.user_id, i.type, i.title, i.tags, i.favorite, 0, i.blind_index, i.payload,
+	i.created_at, i.updated_at, i.deleted_at, u.login, s.permission, s.wrapped_key`
+
+// sharedItemFrom - чужие записи пользователя $1, записи корзины владельца не показываются.
+const sharedItemFrom = ` FROM item_shares s
+	JOIN items i ON i.id = s.item_id AND i.deleted_at IS NULL
+	JOIN users u ON u.id = i.user_id
+	WHERE s.recipient_id = $1`
+
+// CreateKeyPair - сохраняет пару ключей пользователя. Если пара уже есть, она не
+// заменяется (иначе выданные ключи записей перестанут открываться) и возвращается как есть.
+func (p *Postgresql) CreateKeyPair(ctx context.Context, keys models.KeyPair) (models.KeyPair, error) {
+	query := `INSERT INTO user_keys (user_id, public_key, private_key) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING`
+
+	if _, err := p.storage.ExecContext(ctx, query, keys.UserID, keys.PublicKey, keys.PrivateKey); err != nil {
+		p.log.Error("failed to save key pair", "error", err)
+		return models.KeyPair{}, err
+	}
+
+	return p.GetKeyPair(ctx, keys.UserID)
+}
+
+// GetKeyPair - возвращает пару ключей пользователя.
+func (p *Postgresql) GetKeyPair(ctx context.Context, userID int) (models.KeyPair, error) {
+	query := `SELECT user_id, public_key, private_key, created_at FROM user_keys WHERE user_id = $1`
+
+	var keys models.KeyPair
+	err := p.storage.QueryRowContext(ctx, query, userID).
+		Scan(&keys.UserID, &keys.PublicKey, &keys.PrivateKey, &keys.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.KeyPair{}, ErrKeyPairNotFound
+		}
+		p.log.Error("failed to get key pair", "error", err)
+		return models.KeyPair{}, err
+	}
+
+	return keys, nil
+}
+
+// GetRecipient - возвращает id пользователя с логином login и его открытый ключ
+// (пустой, если пары ключей у пользователя еще нет).
+func (p *Postgresql) GetRecipient(ctx context.Context, login string) (models.KeyPair, error) {
+	query := `SELECT u.id, COALESCE(k.public_key, '') FROM users u
+		LEFT JOIN user_keys k ON k.user_id = u.id
+		WHERE u.login = $1`
+
+	var keys models.KeyPair
+	if err := p.storage.QueryRowContext(ctx, query, login).Scan(&keys.UserID, &keys.PublicKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.KeyPair{}, ErrRecipientNotFound
+		}
+		p.log.Error("failed to get recipient", "error", err)
+		return models.KeyPair{}, err
+	}
+
+	return keys, nil
+}
+
+// ShareItem - открывает или меняет доступ к записи владельца ownerID. Повторная выдача
+// тому же получателю заменяет ключ и права.
+func (p *Postgresql) ShareItem(ctx context.Context, ownerID int, share models.Share) (models.Share, error) {
+	query := `INSERT INTO item_shares (item_id, recipient_id, wrapped_key, permission)
+		SELECT $1, $2, $3::BYTEA, $4
+		WHERE EXISTS (SELECT 1 FROM items WHERE id = $1 AND user_id = $5 AND deleted_at IS NULL)
+		ON CONFLICT (item_id, recipient_id) DO UPDATE
+			SET wrapped_key = EXCLUDED.wrapped_key, permission = EXCLUDED.permission, updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at`
+
+	err := p.storage.QueryRowContext(ctx, query,
+		share.ItemID, share.RecipientID, share.WrappedKey, share.Permission, ownerID).
+		Scan(&share.ID, &share.CreatedAt, &share.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Share{}, ErrItemNotFound
+		}
+		p.log.Error("failed to share item", "error", err)
+		return models.Share{}, err
+	}
+
+	return share, nil
+}
+
+// UnshareItem - закрывает доступ получателя к записи владельца ownerID.
+func (p *Postgresql) UnshareItem(ctx context.Context, ownerID int, itemID int64, recipientID int) error {
+	query := `DELETE FROM item_shares s USING items i
+		WHERE s.item_id = i.id AND i.user_id = $1 AND s.item_id = $2 AND s.recipient_id = $3`
+
+	res, err := p.storage.ExecContext(ctx, query, ownerID, itemID, recipientID)
+	if err != nil {
+		p.log.Error("failed to unshare item", "error", err)
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		p.log.Error("failed to get affected rows", "error", err)
+		return err
+	}
+	if n == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
+
+// ListShares - возвращает получателей записи владельца ownerID.
+func (p *Postgresql) ListShares(ctx context.Context, ownerID int, itemID int64) ([]models.Share, error) {
+	query := `SELECT s.id, s.item_id, s.recipient_id, u.login, s.wrapped_key, s.permission,
+			s.created_at, s.updated_at
+		FROM item_shares s
+		JOIN items i ON i.id = s.item_id
+		JOIN users u ON u.id = s.recipient_id
+		WHERE s.item_id = $1 AND i.user_id = $2
+		ORDER BY u.login`
+
+	rows, err := p.storage.QueryContext(ctx, query, itemID, ownerID)
+	if err != nil {
+		p.log.Error("failed to list shares", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []models.Share
+	for rows.Next() {
+		var share models.Share
+		err := rows.Scan(&share.ID, &share.ItemID, &share.RecipientID, &share.RecipientLogin,
+			&share.WrappedKey, &share.Permission, &share.CreatedAt, &share.UpdatedAt)
+		if err != nil {
+			p.log.Error("failed to scan share", "error", err)
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.Error("failed to iterate shares", "error", err)
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// scanSharedItem - сканирует строку выборки чужих записей. Папка владельца получателю
+// не видна, поэтому FolderID всегда 0.
+func (p *Postgresql) scanSharedItem(row rowScanner) (models.SharedItem, error) {
+	var (
+		shared    models.SharedItem
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(
+		&shared.Item.ID,
+		&shared.Item.UserID,
+		&shared.Item.Type,
+		&shared.Item.Title,
+		p.typeMap.SQLScanner(&shared.Item.Tags),
+		&shared.Item.Favorite,
+		&shared.Item.FolderID,
+		p.typeMap.SQLScanner(&shared.Item.BlindIndex),
+		&shared.Item.Payload,
+		&shared.Item.CreatedAt,
+		&shared.Item.UpdatedAt,
+		&deletedAt,
+		&shared.OwnerLogin,
+		&shared.Permission,
+		&shared.WrappedKey,
+	)
+	shared.Item.DeletedAt = deletedAt.Time
+	return shared, err
+}
+
+// ListSharedWithMe - возвращает чужие записи, к которым пользователю открыт доступ.
+func (p *Postgresql) ListSharedWithMe(ctx context.Context, userID int) ([]models.SharedItem, error) {
+	query := `SELECT ` + sharedItemColumns + sharedItemFrom + `
+		ORDER BY i.title, i.id`
+
+	rows, err := p.storage.QueryContext(ctx, query, userID)
+	if err != nil {
+		p.log.Error("failed to list shared items", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.SharedItem
+	for rows.Next() {
+		shared, err := p.scanSharedItem(rows)
+		if err != nil {
+			p.log.Error("failed to scan shared item", "error", err)
+			return nil, err
+		}
+		items = append(items, shared)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.Error("failed to iterate shared items", "error", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetSharedItem - возвращает чужую запись, к которой пользователю открыт доступ.
+func (p *Postgresql) GetSharedItem(ctx context.Context, userID int, itemID int64) (models.SharedItem, error) {
+	query := `SELECT ` + sharedItemColumns + sharedItemFrom + ` AND s.item_id = $2`
+
+	shared, err := p.scanSharedItem(p.storage.QueryRowContext(ctx, query, userID, itemID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.SharedItem{}, ErrShareNotFound
+		}
+		p.log.Error("failed to get shared item", "error", err)
+		return models.SharedItem{}, err
+	}
+
+	return shared, nil
+}
+
+// UpdateSharedItem - перезаписывает данные чужой записи получателем с правом изменения
+// и сохраняет новое состояние ревизией от его имени. Избранное и папка остаются
+// за владельцем.
+func (p *Postgresql) UpdateSharedItem(ctx context.Context, userID int, item models.Item) (updated models.Item, err error) {
+	query := `UPDATE items
+		SET type = $1, title = $2, tags = $3, blind_index = $4, payload = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM item_shares WHERE item_id = $6 AND recipient_id = $7 AND permission = $8)
+		RETURNING ` + itemColumns
+
+	tx, err := p.storage.BeginTx(ctx, nil)
+	if err != nil {
+		p.log.Error("failed to begin transaction", "error", err)
+		return models.Item{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	updated, err = p.scanItem(tx.QueryRowContext(ctx, query,
+		item.Type, item.Title, tagsOrEmpty(item.Tags), tagsOrEmpty(item.BlindIndex), item.Payload,
+		item.ID, userID, models.SharePermissionWrite))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrShareNotFound
+		}
+		p.log.Error("failed to update shared item", "error", err)
+		return models.Item{}, err
+	}
+
+	if err = p.insertRevision(ctx, tx, updated, userID); err != nil {
+		return models.Item{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		p.log.Error("failed to commit transaction", "error", err)
+		return models.Item{}, err
+	}
+
+	return updated, nil
+}
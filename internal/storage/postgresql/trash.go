@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"goph-keeper/internal/models"
+	"time"
+)
+
+// ListTrash - возвращает записи пользователя из корзины от недавно удаленных к давним.
+func (p *Postgresql) ListTrash(ctx context.Context, userID int) ([]models.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`
+
+	rows, err := p.storage.QueryContext(ctx, query, userID)
+	if err != nil {
+		p.log.Error("failed to list trash", "error", err)
+		return nil, err
+	}
+
+	return p.scanItems(rows)
+}
+
+// RestoreItem - возвращает запись пользователя из корзины.
+func (p *Postgresql) RestoreItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	query := `UPDATE items SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		RETURNING ` + itemColumns
+
+	item, err := p.scanItem(p.storage.QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrItemNotFound
+		}
+		p.log.Error("failed to restore item", "error", err)
+		return models.Item{}, err
+	}
+
+	return item, nil
+}
+
+// PurgeItem - окончательно удаляет запись пользователя из корзины. Ревизии удаляются каскадом.
+func (p *Postgresql) PurgeItem(ctx context.Context, userID int, id int64) error {
+	query := `DELETE FROM items WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+
+	res, err := p.storage.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		p.log.Error("failed to purge item", "error", err)
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		p.log.Error("failed to get affected rows", "error", err)
+		return err
+	}
+	if n == 0 {
+		return ErrItemNotFound
+	}
+
+	return nil
+}
+
+// PurgeTrash - окончательно удаляет записи всех пользователей, попавшие в корзину раньше before.
+// Возвращает число удаленных записей.
+func (p *Postgresql) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM items WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	res, err := p.storage.ExecContext(ctx, query, before)
+	if err != nil {
+		p.log.Error("failed to purge trash", "error", err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"goph-keeper/internal/models"
+	"time"
+)
+
+// Folders - возвращает копию дерева папок пользователя, полученную при последней синхронизации.
+func (s *Storage) Folders(ctx context.Context, userID int) ([]models.Folder, error) {
+	query := `SELECT id, user_id, parent_id, name, created_at, updated_at FROM folders
+		WHERE user_id = $1
+		ORDER BY name COLLATE NOCASE, id`
+
+	rows, err := s.storage.QueryContext(ctx, query, userID)
+	if err != nil {
+		s.log.Error("failed to get folders", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []models.Folder
+	for rows.Next() {
+		var folder models.Folder
+		err := rows.Scan(&folder.ID, &folder.UserID, &folder.ParentID, &folder.Name, &folder.CreatedAt, &folder.UpdatedAt)
+		if err != nil {
+			s.log.Error("failed to scan folder", "error", err)
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+
+	return folders, rows.Err()
+}
+
+// ReplaceFolders - заменяет копию дерева папок пользователя состоянием сервера.
+// Записи синхронизированного кэша, чья папка удалена на сервере, переносятся в корень.
+func (s *Storage) ReplaceFolders(ctx context.Context, userID int, folders []models.Folder) (err error) {
+	tx, err := s.storage.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM folders WHERE user_id = $1`, userID); err != nil {
+		s.log.Error("failed to clear folders", "error", err)
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO folders (id, user_id, parent_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, folder := range folders {
+		if _, err = stmt.ExecContext(ctx, folder.ID, userID, folder.ParentID, folder.Name,
+			formatTime(folder.CreatedAt), formatTime(folder.UpdatedAt)); err != nil {
+			s.log.Error("failed to save folder", "error", err)
+			return err
+		}
+	}
+
+	query := `UPDATE items SET folder_id = 0
+		WHERE user_id = $1 AND folder_id <> 0 AND folder_id NOT IN (SELECT id FROM folders WHERE user_id = $1)`
+	if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+		s.log.Error("failed to reset items folder", "error", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MoveItem - переносит запись пользователя в папку (0 - в корень) без изменения ее данных.
+// Запись помечается измененной и уходит на сервер при следующей синхронизации.
+func (s *Storage) MoveItem(ctx context.Context, userID int, id, folderID int64) (models.Item, error) {
+	query := `UPDATE items SET folder_id = $1, updated_at = $2, dirty = 1, version = version + 1
+		WHERE id = $3 AND user_id = $4 AND deleted = 0
+		RETURNING ` + itemColumns
+
+	item, err := scanItem(s.storage.QueryRowContext(ctx, query, folderID, formatTime(time.Now()), id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrItemNotFound
+		}
+		s.log.Error("failed to move item", "error", err)
+		return models.Item{}, err
+	}
+
+	return item, nil
+}
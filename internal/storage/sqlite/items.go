@@ -0,0 +1,355 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"strings"
+	"time"
+)
+
+var (
+	ErrItemNotFound = errors.New("item not found")
+)
+
+// timeLayout - формат хранения времени в items. Фиксированная ширина дробной части
+// нужна, чтобы строковое сравнение в курсоре совпадало с хронологическим порядком.
+const timeLayout = "2006-01-02 15:04:05.000000"
+
+// itemColumns - перечень колонок таблицы items в порядке сканирования.
+const itemColumns = "id, user_id, type, title, tags, favorite, folder_id, blind_index, payload, created_at, updated_at"
+
+// rowScanner - общий интерфейс для *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// formatTime - приводит время к формату хранения.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// scanItem - сканирует строку таблицы items в models.Item.
+func scanItem(row rowScanner) (models.Item, error) {
+	var (
+		item       models.Item
+		tags       string
+		blindIndex string
+	)
+	err := row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Type,
+		&item.Title,
+		&tags,
+		&item.Favorite,
+		&item.FolderID,
+		&blindIndex,
+		&item.Payload,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &item.Tags); err != nil {
+		return models.Item{}, err
+	}
+	if err := json.Unmarshal([]byte(blindIndex), &item.BlindIndex); err != nil {
+		return models.Item{}, err
+	}
+
+	return item, nil
+}
+
+// CreateItem - сохраняет новую запись в локальной базе.
+func (s *Storage) CreateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	tags, err := json.Marshal(tagsOrEmpty(item.Tags))
+	if err != nil {
+		return models.Item{}, err
+	}
+	blindIndex, err := json.Marshal(tagsOrEmpty(item.BlindIndex))
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	now := formatTime(time.Now())
+	query := `INSERT INTO items (user_id, type, title, tags, favorite, folder_id, blind_index, payload, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + itemColumns
+
+	created, err := scanItem(s.storage.QueryRowContext(ctx, query, item.UserID, item.Type, item.Title, string(tags),
+		item.Favorite, item.FolderID, string(blindIndex), item.Payload, now, now))
+	if err != nil {
+		s.log.Error("failed to create item", "error", err)
+		return models.Item{}, err
+	}
+
+	return created, nil
+}
+
+// CreateItems - сохраняет новые записи одной транзакцией: либо все, либо ни одной.
+// Заданные даты создания и изменения сохраняются (восстановление из копии), пустые
+// заменяются текущим временем.
+func (s *Storage) CreateItems(ctx context.Context, items []models.Item) (created []models.Item, err error) {
+	tx, err := s.storage.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO items (user_id, type, title, tags, favorite, folder_id, blind_index, payload,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+itemColumns)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	orNow := func(t time.Time) string {
+		if t.IsZero() {
+			return formatTime(now)
+		}
+		return formatTime(t)
+	}
+
+	created = make([]models.Item, 0, len(items))
+	for _, item := range items {
+		tags, err := json.Marshal(tagsOrEmpty(item.Tags))
+		if err != nil {
+			return nil, err
+		}
+		blindIndex, err := json.Marshal(tagsOrEmpty(item.BlindIndex))
+		if err != nil {
+			return nil, err
+		}
+		c, err := scanItem(stmt.QueryRowContext(ctx, item.UserID, item.Type, item.Title, string(tags),
+			item.Favorite, item.FolderID, string(blindIndex), item.Payload, orNow(item.CreatedAt), orNow(item.UpdatedAt)))
+		if err != nil {
+			s.log.Error("failed to create item", "error", err)
+			return nil, err
+		}
+		created = append(created, c)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetItem - возвращает запись пользователя по id.
+func (s *Storage) GetItem(ctx context.Context, userID int, id int64) (models.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items WHERE id = $1 AND user_id = $2 AND deleted = 0`
+
+	item, err := scanItem(s.storage.QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrItemNotFound
+		}
+		s.log.Error("failed to get item", "error", err)
+		return models.Item{}, err
+	}
+
+	return item, nil
+}
+
+// UpdateItem - перезаписывает метаданные и данные записи пользователя.
+func (s *Storage) UpdateItem(ctx context.Context, item models.Item) (models.Item, error) {
+	tags, err := json.Marshal(tagsOrEmpty(item.Tags))
+	if err != nil {
+		return models.Item{}, err
+	}
+	blindIndex, err := json.Marshal(tagsOrEmpty(item.BlindIndex))
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	query := `UPDATE items
+		SET type = $1, title = $2, tags = $3, favorite = $4, folder_id = $5, blind_index = $6, payload = $7, updated_at = $8,
+			dirty = 1, version = version + 1
+		WHERE id = $9 AND user_id = $10 AND deleted = 0
+		RETURNING ` + itemColumns
+
+	updated, err := scanItem(s.storage.QueryRowContext(ctx, query,
+		item.Type, item.Title, string(tags), item.Favorite, item.FolderID, string(blindIndex), item.Payload,
+		formatTime(time.Now()), item.ID, item.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Item{}, ErrItemNotFound
+		}
+		s.log.Error("failed to update item", "error", err)
+		return models.Item{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteItem - удаляет запись пользователя. Запись, уже отправленная на сервер, только
+// помечается удаленной, чтобы синхронизация удалила ее и на сервере.
+func (s *Storage) DeleteItem(ctx context.Context, userID int, id int64) error {
+	query := `UPDATE items SET deleted = 1, dirty = 1, version = version + 1
+		WHERE id = $1 AND user_id = $2 AND deleted = 0 AND server_id IS NOT NULL`
+
+	res, err := s.storage.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		s.log.Error("failed to delete item", "error", err)
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	query = `DELETE FROM items WHERE id = $1 AND user_id = $2 AND deleted = 0`
+
+	res, err = s.storage.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		s.log.Error("failed to delete item", "error", err)
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		s.log.Error("failed to get affected rows", "error", err)
+		return err
+	}
+	if n == 0 {
+		return ErrItemNotFound
+	}
+
+	return nil
+}
+
+// ListItems - возвращает страницу записей пользователя, при itemType отличном от
+// ItemTypeUnspecified только записи этого типа, при folderID отличном от
+// models.FolderAny только записи этой папки (0 - записи вне папок).
+func (s *Storage) ListItems(ctx context.Context, userID int, itemType models.ItemType, folderID int64, page models.Page) ([]models.Item, error) {
+	conditions := []string{"user_id = ?", "deleted = 0"}
+	args := []any{userID}
+
+	if itemType != models.ItemTypeUnspecified {
+		conditions = append(conditions, "type = ?")
+		args = append(args, itemType)
+	}
+	if folderID != models.FolderAny {
+		conditions = append(conditions, "folder_id = ?")
+		args = append(args, folderID)
+	}
+
+	column := "updated_at"
+	if page.Sort.Field == models.SortByTitle {
+		column = "title"
+	}
+	direction, operator := "ASC", ">"
+	if page.Sort.Descending {
+		direction, operator = "DESC", "<"
+	}
+
+	// курсор: строки строго после последней выданной записи в порядке сортировки
+	if page.After != nil {
+		value, err := cursorValue(page)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (?, ?)", column, operator))
+		args = append(args, value, page.After.ID)
+	}
+
+	query := `SELECT ` + itemColumns + ` FROM items
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + column + ` ` + direction + `, id ` + direction + `
+		LIMIT ?`
+	args = append(args, page.Limit)
+
+	rows, err := s.storage.QueryContext(ctx, query, args...)
+	if err != nil {
+		s.log.Error("failed to list items", "error", err)
+		return nil, err
+	}
+
+	return s.scanItems(rows)
+}
+
+// FindItemsByTitle - возвращает записи пользователя с данным названием без учета регистра.
+func (s *Storage) FindItemsByTitle(ctx context.Context, userID int, title string) ([]models.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items
+		WHERE user_id = $1 AND deleted = 0 AND title = $2 COLLATE NOCASE
+		ORDER BY id`
+
+	rows, err := s.storage.QueryContext(ctx, query, userID, title)
+	if err != nil {
+		s.log.Error("failed to find items", "error", err)
+		return nil, err
+	}
+
+	return s.scanItems(rows)
+}
+
+// AllItems - возвращает все записи пользователя, при itemType отличном от
+// ItemTypeUnspecified только записи этого типа. Используется для поиска по локальному кэшу.
+func (s *Storage) AllItems(ctx context.Context, userID int, itemType models.ItemType) ([]models.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items
+		WHERE user_id = $1 AND deleted = 0 AND ($2 = 0 OR type = $2)
+		ORDER BY updated_at DESC, id DESC`
+
+	rows, err := s.storage.QueryContext(ctx, query, userID, itemType)
+	if err != nil {
+		s.log.Error("failed to get items", "error", err)
+		return nil, err
+	}
+
+	return s.scanItems(rows)
+}
+
+// scanItems - сканирует все строки выборки и закрывает rows.
+func (s *Storage) scanItems(rows *sql.Rows) ([]models.Item, error) {
+	defer rows.Close()
+
+	var items []models.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			s.log.Error("failed to scan item", "error", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		s.log.Error("failed to iterate items", "error", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// cursorValue - переводит значение курсора в формат хранения колонки сортировки.
+func cursorValue(page models.Page) (string, error) {
+	if page.Sort.Field == models.SortByTitle {
+		return page.After.Value, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, page.After.Value)
+	if err != nil {
+		return "", err
+	}
+	return formatTime(t), nil
+}
+
+// tagsOrEmpty - заменяет nil на пустой срез, чтобы теги сохранялись как "[]".
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrNoSession = errors.New("no active session")
+)
+
+// SetSession - делает пользователя текущим.
+func (s *Storage) SetSession(ctx context.Context, userID int) error {
+	query := `INSERT INTO session (id, user_id) VALUES (1, $1)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id`
+
+	if _, err := s.storage.ExecContext(ctx, query, userID); err != nil {
+		s.log.Error("failed to set session", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetSessionToken - возвращает токен текущего пользователя.
+func (s *Storage) GetSessionToken(ctx context.Context) (string, error) {
+	query := `SELECT u.token FROM session s JOIN users u ON u.id = s.user_id WHERE s.id = 1`
+
+	var token sql.NullString
+	err := s.storage.QueryRowContext(ctx, query).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && token.String == "") {
+		return "", ErrNoSession
+	}
+	if err != nil {
+		s.log.Error("failed to get session", "error", err)
+		return "", err
+	}
+
+	return token.String, nil
+}
+
+// GetSessionLogin - возвращает логин текущего пользователя.
+func (s *Storage) GetSessionLogin(ctx context.Context) (string, error) {
+	query := `SELECT u.login FROM session s JOIN users u ON u.id = s.user_id WHERE s.id = 1`
+
+	var login string
+	err := s.storage.QueryRowContext(ctx, query).Scan(&login)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNoSession
+	}
+	if err != nil {
+		s.log.Error("failed to get session", "error", err)
+		return "", err
+	}
+
+	return login, nil
+}
+
+// DeleteSession - стирает токен текущего пользователя и завершает сессию.
+// Локальные записи пользователя остаются в кэше.
+func (s *Storage) DeleteSession(ctx context.Context) (err error) {
+	tx, err := s.storage.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := `UPDATE users SET token = NULL WHERE id = (SELECT user_id FROM session WHERE id = 1)`
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		s.log.Error("failed to clear token", "error", err)
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM session`); err != nil {
+		s.log.Error("failed to delete session", "error", err)
+		return err
+	}
+
+	return tx.Commit()
+}
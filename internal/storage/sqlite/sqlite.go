@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	_ "github.com/mattn/go-sqlite3"
+	"goph-keeper/internal/models"
 	"log/slog"
 	"os"
 	"time"
@@ -20,25 +21,27 @@ type Storage struct {
 	log     *slog.Logger
 }
 
-func NewSqlStorage(log *slog.Logger) (*Storage, error) {
+// NewSqlStorage - открывает базу клиента в каталоге baseDir/storage.
+// Пустой baseDir - текущий каталог.
+func NewSqlStorage(log *slog.Logger, baseDir string) (*Storage, error) {
 	db := &Storage{
 		log: log,
 	}
 
 	// Получаем путь для базы данных
-	dbPath, err := db.getDatabaseFilePath()
+	dbPath, err := db.getDatabaseFilePath(baseDir)
 	if err != nil {
-		log.Error("Ошибка определения пути базы данных", err)
+		log.Error("Ошибка определения пути базы данных", "error", err)
 		return nil, err
 	}
 
 	// Создаём базу данных
 	if err := db.init(dbPath); err != nil {
-		log.Error("Ошибка создания базы данных", err)
+		log.Error("Ошибка создания базы данных", "error", err)
 		return nil, err
 	}
 
-	fmt.Println("База данных и таблица успешно созданы!")
+	log.Info("database is ready", "path", dbPath)
 
 	return db, err
 }
@@ -59,10 +62,13 @@ func (s *Storage) init(dbPath string) error {
 	return nil
 }
 
-func (s *Storage) getDatabaseFilePath() (string, error) {
-	baseDir, err := os.Getwd()
-	if err != nil {
-		return "", err
+func (s *Storage) getDatabaseFilePath(baseDir string) (string, error) {
+	if baseDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		baseDir = wd
 	}
 
 	// Укажите имя каталога и файла базы данных
@@ -106,61 +112,94 @@ func (s *Storage) createTableIfNotExists() (err error) {
 		return err
 	}
 
-	// Создаем таблицу credentials
-	query = `CREATE TABLE IF NOT EXISTS credentials (
+	// Создаем таблицу items - единое хранилище записей всех типов
+	query = `CREATE TABLE IF NOT EXISTS items (
         id INTEGER PRIMARY KEY AUTOINCREMENT, 
         user_id INTEGER NOT NULL, 
-        resource TEXT NOT NULL,
-        login TEXT NOT NULL,
-        password TEXT NOT NULL,
-        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        type INTEGER NOT NULL,
+        title TEXT NOT NULL DEFAULT '',
+        tags TEXT NOT NULL DEFAULT '[]',
+        favorite INTEGER NOT NULL DEFAULT 0,
+        payload BLOB NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        server_id INTEGER,
+        dirty INTEGER NOT NULL DEFAULT 1,
+        deleted INTEGER NOT NULL DEFAULT 0,
+        folder_id INTEGER NOT NULL DEFAULT 0,
+        version INTEGER NOT NULL DEFAULT 0,
+        blind_index TEXT NOT NULL DEFAULT '[]',
         FOREIGN KEY (user_id) REFERENCES users(id)
     )`
 	_, err = tx.Exec(query)
 	if err != nil {
-		s.log.Error("failed to create table - credentials:", "error", err)
+		s.log.Error("failed to create table - items:", "error", err)
 		return err
 	}
 
-	// Создаем таблицу text_data
-	query = `CREATE TABLE IF NOT EXISTS text_data (
-        id INTEGER PRIMARY KEY AUTOINCREMENT, 
-        user_id INTEGER NOT NULL, 
-        text TEXT NOT NULL,
-        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-        FOREIGN KEY (user_id) REFERENCES users(id)
-    )`
-	_, err = tx.Exec(query)
-	if err != nil {
-		s.log.Error("failed to create table - text_data:", "error", err)
+	// Колонки синхронизации для баз, созданных до их появления
+	if err = s.addItemsSyncColumns(tx); err != nil {
 		return err
 	}
 
-	// Создаем таблицу binary_data
-	query = `CREATE TABLE IF NOT EXISTS binary_data (
-        id INTEGER PRIMARY KEY AUTOINCREMENT, 
-        user_id INTEGER NOT NULL, 
-        binary_data BLOB NOT NULL,
-        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	// Колонка папки для баз, созданных до появления папок
+	if err = s.addItemsFolderColumn(tx); err != nil {
+		return err
+	}
+
+	// Счетчик локальных изменений для баз, созданных до его появления
+	if err = s.addItemsVersionColumn(tx); err != nil {
+		return err
+	}
+
+	// Слепые токены поиска для баз, созданных до шифрования записей на клиенте
+	if err = s.addItemsBlindIndexColumn(tx); err != nil {
+		return err
+	}
+
+	// Создаем таблицу folders - копия дерева папок с сервера, id совпадает с id на сервере
+	query = `CREATE TABLE IF NOT EXISTS folders (
+        id INTEGER NOT NULL,
+        user_id INTEGER NOT NULL,
+        parent_id INTEGER NOT NULL DEFAULT 0,
+        name TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (user_id, id),
         FOREIGN KEY (user_id) REFERENCES users(id)
     )`
 	_, err = tx.Exec(query)
 	if err != nil {
-		s.log.Error("failed to create table - binary_data:", "error", err)
+		s.log.Error("failed to create table - folders:", "error", err)
 		return err
 	}
 
-	// Создаем таблицу cards
-	query = `CREATE TABLE IF NOT EXISTS cards (
-        id INTEGER PRIMARY KEY AUTOINCREMENT, 
-        user_id INTEGER NOT NULL, 
-        cards TEXT NOT NULL,
-        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	// Текущий пользователь для команд командной строки - не больше одной строки
+	query = `CREATE TABLE IF NOT EXISTS session (
+        id INTEGER PRIMARY KEY CHECK (id = 1),
+        user_id INTEGER NOT NULL,
         FOREIGN KEY (user_id) REFERENCES users(id)
     )`
 	_, err = tx.Exec(query)
 	if err != nil {
-		s.log.Error("failed to create table - cards:", "error", err)
+		s.log.Error("failed to create table - session:", "error", err)
+		return err
+	}
+
+	// Индексы под сортировку и курсор постраничной выдачи
+	for _, query := range []string{
+		`CREATE INDEX IF NOT EXISTS items_user_id_updated_at_idx ON items (user_id, updated_at, id)`,
+		`CREATE INDEX IF NOT EXISTS items_user_id_title_idx ON items (user_id, title, id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS items_user_id_server_id_idx ON items (user_id, server_id)`,
+		`CREATE INDEX IF NOT EXISTS items_user_id_folder_id_idx ON items (user_id, folder_id)`,
+	} {
+		if _, err = tx.Exec(query); err != nil {
+			s.log.Error("failed to create index - items:", "error", err)
+			return err
+		}
+	}
+
+	if err = s.migrateLegacyTables(tx); err != nil {
 		return err
 	}
 
@@ -205,7 +244,7 @@ func (s *Storage) GetUserIDWithToken(ctx context.Context, token string) (int, er
 // SaveLoginAndToken - сохраняет логин и токен в базе данных.
 func (s *Storage) SaveLoginAndToken(ctx context.Context, login, token string) error {
 
-	query := `INSERT INTO users (login, token) VALUE ($1, $2)`
+	query := `INSERT INTO users (login, token) VALUES ($1, $2)`
 	_, err := s.storage.ExecContext(ctx, query, login, token)
 	if err != nil {
 		s.log.Error("failed to update access token", "error", err)
@@ -229,72 +268,127 @@ func (s *Storage) UpdateLoginAndToken(ctx context.Context, userID int, token str
 	return nil
 }
 
-// SaveLoginAndPasswordInCredentials - сохраняет полученный логин и пароль от ресурса в базу.
-func (s *Storage) SaveLoginAndPasswordInCredentials(
-	ctx context.Context,
-	userID int,
-	resource string,
-	login string,
-	password string) error {
-
-	query := `INSERT INTO credentials (user_id, resource, login, password) VALUES ($1, $2, $3, $4)`
-
-	_, err := s.storage.ExecContext(ctx, query, userID, resource, login, password)
+// addItemsSyncColumns - добавляет в items колонки синхронизации, если их нет.
+// Записи, созданные до синхронизации, считаются несинхронизированными (dirty).
+func (s *Storage) addItemsSyncColumns(tx *sql.Tx) error {
+	var n int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('items') WHERE name = 'server_id'`).Scan(&n)
 	if err != nil {
-		s.log.Error("failed to save in credentials", "error", err)
+		s.log.Error("failed to check items columns", "error", err)
 		return err
 	}
+	if n > 0 {
+		return nil
+	}
+
+	for _, query := range []string{
+		`ALTER TABLE items ADD COLUMN server_id INTEGER`,
+		`ALTER TABLE items ADD COLUMN dirty INTEGER NOT NULL DEFAULT 1`,
+		`ALTER TABLE items ADD COLUMN deleted INTEGER NOT NULL DEFAULT 0`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			s.log.Error("failed to add items sync column", "error", err)
+			return err
+		}
+	}
 
 	return nil
 }
 
-// SaveTextData - сохраняет получены текст в базу.
-func (s *Storage) SaveTextDataInDatabase(ctx context.Context, data string) error {
-	query := `INSERT INTO text_data (data) VALUES ($1)`
-
-	_, err := s.storage.ExecContext(ctx, query, data)
+// addItemsFolderColumn - добавляет в items колонку папки, если база создана до ее появления.
+func (s *Storage) addItemsFolderColumn(tx *sql.Tx) error {
+	var n int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('items') WHERE name = 'folder_id'`).Scan(&n)
 	if err != nil {
-		s.log.Error("failed to save in credentials", "error", err)
+		s.log.Error("failed to check items columns", "error", err)
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(`ALTER TABLE items ADD COLUMN folder_id INTEGER NOT NULL DEFAULT 0`); err != nil {
+		s.log.Error("failed to add items folder column", "error", err)
 		return err
 	}
 
 	return nil
 }
 
-// SaveBinaryData - сохраняет полученные бинарные данные.
-func (s *Storage) SaveBinaryDataInDatabase(ctx context.Context, data string) error {
-	query := `INSERT INTO binary_data (data) VALUES ($1)`
-
-	_, err := s.storage.ExecContext(ctx, query, data)
+// addItemsVersionColumn - добавляет в items счетчик локальных изменений, если база создана до его появления.
+func (s *Storage) addItemsVersionColumn(tx *sql.Tx) error {
+	var n int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('items') WHERE name = 'version'`).Scan(&n)
 	if err != nil {
-		s.log.Error("failed to save in credentials", "error", err)
+		s.log.Error("failed to check items columns", "error", err)
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(`ALTER TABLE items ADD COLUMN version INTEGER NOT NULL DEFAULT 0`); err != nil {
+		s.log.Error("failed to add items version column", "error", err)
 		return err
 	}
 
 	return nil
 }
 
-// SaveCardsInDatabase - сохраняет полученные данные по картам в базу.
-func (s *Storage) SaveCardsInDatabase(ctx context.Context, cards string) error {
-	query := `INSERT INTO cards ( cards) VALUES ($1)`
-
-	_, err := s.storage.ExecContext(ctx, query, cards)
+// addItemsBlindIndexColumn - добавляет в items колонку слепых токенов поиска, если база создана до ее появления.
+func (s *Storage) addItemsBlindIndexColumn(tx *sql.Tx) error {
+	var n int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('items') WHERE name = 'blind_index'`).Scan(&n)
 	if err != nil {
-		s.log.Error("failed to save in cards", "error", err)
+		s.log.Error("failed to check items columns", "error", err)
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(`ALTER TABLE items ADD COLUMN blind_index TEXT NOT NULL DEFAULT '[]'`); err != nil {
+		s.log.Error("failed to add items blind_index column", "error", err)
 		return err
 	}
 
 	return nil
 }
 
-// GetAll - возвращает все данные из базы данных.
-func (s *Storage) GetAll(ctx context.Context, tableName string) (*sql.Rows, error) {
-	query := fmt.Sprintf("SELECT * FROM %s", tableName)
-	rows, err := s.storage.QueryContext(ctx, query)
-	if err != nil {
-		s.log.Error("failed to get all data from database", "error", err)
-		return nil, err
+// migrateLegacyTables - переносит записи из таблиц прежних версий в items и удаляет эти таблицы.
+// Сохранение в text_data, binary_data и cards никогда не работало (запросы не совпадали со схемой),
+// поэтому переносить из них нечего, таблицы просто удаляются.
+//
+// Те же учетные данные миграция сервера уже перенесла в items на сервере, поэтому записи
+// сохраняются без признака изменений: первая синхронизация связывает их с копиями на сервере
+// (ApplyServerItems), а не отправляет дубликаты. Время приводится к формату timeLayout.
+func (s *Storage) migrateLegacyTables(tx *sql.Tx) error {
+	var name string
+	err := tx.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'credentials'`).Scan(&name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// таблиц прежней версии нет, переносить нечего
+	case err != nil:
+		s.log.Error("failed to check legacy tables", "error", err)
+		return err
+	default:
+		query := `INSERT INTO items (user_id, type, title, payload, created_at, updated_at, dirty)
+			SELECT user_id, $1, resource,
+			       json_object('login', json_object('resource', resource, 'login', login, 'password', password)),
+			       strftime('%Y-%m-%d %H:%M:%f000', updated_at), strftime('%Y-%m-%d %H:%M:%f000', updated_at), 0
+			FROM credentials`
+		if _, err := tx.Exec(query, models.ItemTypeLogin); err != nil {
+			s.log.Error("failed to migrate credentials", "error", err)
+			return err
+		}
 	}
 
-	return rows, nil
+	for _, table := range []string{"credentials", "text_data", "binary_data", "cards"} {
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			s.log.Error("failed to drop legacy table", "table", table, "error", err)
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"goph-keeper/internal/models"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newLegacyStorage - база прежней версии с таблицей credentials, открытая текущей версией.
+func newLegacyStorage(t *testing.T) (*Storage, int) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "storage"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "storage", "client.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{
+		`CREATE TABLE users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			login TEXT NOT NULL UNIQUE,
+			token TEXT,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE credentials (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			resource TEXT NOT NULL,
+			login TEXT NOT NULL,
+			password TEXT NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT INTO users (login, token) VALUES ('alice', 'tok')`,
+		`INSERT INTO credentials (user_id, resource, login, password, updated_at)
+			VALUES (1, 'github.com', 'alice', 's3cret', '2024-01-02 03:04:05'),
+			       (1, 'gitlab.com', 'alice', 'other', '2024-01-02 03:04:06')`,
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSqlStorage(slog.New(slog.NewTextHandler(io.Discard, nil)), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	userID, err := s.GetUserIDWithLogin(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, userID
+}
+
+func TestMigrateLegacyTables(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newLegacyStorage(t)
+
+	// время в формате timeLayout, иначе курсор сравнивает строки неверно
+	var updatedAt string
+	if err := s.storage.QueryRow(`SELECT CAST(updated_at AS TEXT) FROM items WHERE title = 'github.com'`).Scan(&updatedAt); err != nil {
+		t.Fatal(err)
+	}
+	if updatedAt != "2024-01-02 03:04:05.000000" {
+		t.Errorf("updated_at = %q, want the fixed-width layout", updatedAt)
+	}
+
+	newer, err := s.CreateItem(ctx, models.Item{UserID: userID, Type: models.ItemTypeLogin, Title: "new", Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.ListItems(ctx, userID, models.ItemTypeUnspecified, models.FolderAny, models.Page{Limit: 1})
+	if err != nil || len(first) != 1 || first[0].Title != "github.com" {
+		t.Fatalf("first page = %+v, %v", first, err)
+	}
+	rest, err := s.ListItems(ctx, userID, models.ItemTypeUnspecified, models.FolderAny, models.Page{
+		Limit: 10,
+		After: &models.Cursor{Value: first[0].UpdatedAt.Format(time.RFC3339Nano), ID: first[0].ID},
+	})
+	if err != nil || len(rest) != 2 || rest[0].Title != "gitlab.com" || rest[1].ID != newer.ID {
+		t.Fatalf("next page = %+v, %v", rest, err)
+	}
+
+	// перенесенные записи не отправляются на сервер как новые
+	if dirty := dirtyIDs(t, s, userID); len(dirty) != 1 || dirty[newer.ID].ID == 0 {
+		t.Fatalf("dirty items = %v, want only the new item", dirty)
+	}
+
+	// копия github.com уже на сервере (миграция сервера), сериализована иначе
+	server := []models.Item{{
+		ID:        10,
+		Type:      models.ItemTypeLogin,
+		Title:     "github.com",
+		Payload:   []byte(`{"login": {"password": "s3cret", "login": "alice", "resource": "github.com"}}`),
+		UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+	if _, _, err := s.ApplyServerItems(ctx, userID, server); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := s.AllItems(ctx, userID, models.ItemTypeLogin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected no duplicates after sync, got %d items", len(all))
+	}
+	var github, gitlab models.Item
+	for _, item := range all {
+		switch item.Title {
+		case "github.com":
+			github = item
+		case "gitlab.com":
+			gitlab = item
+		}
+	}
+	if serverID, err := s.ServerID(ctx, userID, github.ID); err != nil || serverID != 10 {
+		t.Errorf("github.com server id = %d, %v, want 10", serverID, err)
+	}
+
+	// записи без пары на сервере уходят как новые
+	dirty := dirtyIDs(t, s, userID)
+	if _, ok := dirty[gitlab.ID]; !ok || len(dirty) != 2 {
+		t.Errorf("dirty items = %v, want gitlab.com and the new item", dirty)
+	}
+}
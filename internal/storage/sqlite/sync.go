@@ -0,0 +1,273 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+)
+
+// DirtyItems - записи пользователя с изменениями, которые еще не отправлены на сервер.
+func (s *Storage) DirtyItems(ctx context.Context, userID int) ([]models.LocalItem, error) {
+	query := `SELECT ` + itemColumns + `, COALESCE(server_id, 0), deleted, version FROM items
+		WHERE user_id = $1 AND dirty = 1
+		ORDER BY id`
+
+	rows, err := s.storage.QueryContext(ctx, query, userID)
+	if err != nil {
+		s.log.Error("failed to get dirty items", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.LocalItem
+	for rows.Next() {
+		var (
+			item       models.LocalItem
+			tags       string
+			blindIndex string
+		)
+		err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Type,
+			&item.Title,
+			&tags,
+			&item.Favorite,
+			&item.FolderID,
+			&blindIndex,
+			&item.Payload,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.ServerID,
+			&item.Deleted,
+			&item.Version,
+		)
+		if err != nil {
+			s.log.Error("failed to scan dirty item", "error", err)
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(tags), &item.Tags); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(blindIndex), &item.BlindIndex); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// MarkSynced - запоминает id записи на сервере и снимает признак изменений, если запись
+// не менялась с версии version, которая ушла на сервер. Изменение, сделанное во время
+// отправки (TUI, агент, другой процесс), остается неотправленным до следующей синхронизации.
+func (s *Storage) MarkSynced(ctx context.Context, id, serverID, version int64) error {
+	query := `UPDATE items SET server_id = $1, dirty = CASE WHEN version = $2 THEN 0 ELSE dirty END WHERE id = $3`
+
+	if _, err := s.storage.ExecContext(ctx, query, serverID, version, id); err != nil {
+		s.log.Error("failed to mark item synced", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ServerID - id записи пользователя на сервере, 0 - запись еще не отправлялась.
+func (s *Storage) ServerID(ctx context.Context, userID int, id int64) (int64, error) {
+	query := `SELECT COALESCE(server_id, 0) FROM items WHERE id = $1 AND user_id = $2 AND deleted = 0`
+
+	var serverID int64
+	if err := s.storage.QueryRowContext(ctx, query, id, userID).Scan(&serverID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrItemNotFound
+		}
+		s.log.Error("failed to get server id", "error", err)
+		return 0, err
+	}
+	return serverID, nil
+}
+
+// PurgeItem - окончательно удаляет запись из локального кэша.
+func (s *Storage) PurgeItem(ctx context.Context, id int64) error {
+	if _, err := s.storage.ExecContext(ctx, `DELETE FROM items WHERE id = $1`, id); err != nil {
+		s.log.Error("failed to purge item", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ApplyServerItems - приводит синхронизированные записи кэша к состоянию сервера:
+// обновляет и добавляет записи из items (ID - id на сервере) и удаляет записи,
+// которых на сервере больше нет или которые пришли надгробиями (DeletedAt - запись в корзине).
+// Записи с неотправленными изменениями не трогаются.
+//
+// Записи, перенесенные из таблиц прежней версии (без server_id и без изменений), связываются
+// с записью сервера с теми же типом, названием и данными, чтобы не было дубликатов. Оставшиеся
+// без пары помечаются измененными и уходят на сервер при следующей синхронизации.
+// Возвращает число добавленных или обновленных и удаленных записей.
+func (s *Storage) ApplyServerItems(ctx context.Context, userID int, items []models.Item) (pulled, removed int, err error) {
+	tx, err := s.storage.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// server_id -> dirty для уже известных записей
+	known := make(map[int64]bool)
+	rows, err := tx.QueryContext(ctx, `SELECT server_id, dirty FROM items WHERE user_id = $1 AND server_id IS NOT NULL`, userID)
+	if err != nil {
+		s.log.Error("failed to get synced items", "error", err)
+		return 0, 0, err
+	}
+	for rows.Next() {
+		var (
+			serverID int64
+			dirty    bool
+		)
+		if err = rows.Scan(&serverID, &dirty); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		known[serverID] = dirty
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	unlinked, err := s.unlinkedItems(ctx, tx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	onServer := make(map[int64]struct{}, len(items))
+	for _, item := range items {
+		// надгробие не считается записью на сервере: синхронизированная копия удаляется ниже
+		if !item.DeletedAt.IsZero() {
+			continue
+		}
+		onServer[item.ID] = struct{}{}
+
+		dirty, ok := known[item.ID]
+		if dirty {
+			continue
+		}
+
+		if key := unlinkedKey(item.Type, item.Title, item.Payload); !ok && len(unlinked[key]) > 0 {
+			id := unlinked[key][0]
+			unlinked[key] = unlinked[key][1:]
+			if _, err = tx.ExecContext(ctx, `UPDATE items SET server_id = $1 WHERE id = $2`, item.ID, id); err != nil {
+				s.log.Error("failed to link migrated item", "error", err)
+				return 0, 0, err
+			}
+			ok = true
+		}
+
+		tags, err := json.Marshal(tagsOrEmpty(item.Tags))
+		if err != nil {
+			return 0, 0, err
+		}
+		blindIndex, err := json.Marshal(tagsOrEmpty(item.BlindIndex))
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if ok {
+			// драйвер связывает $N в порядке первого появления, поэтому номера идут по возрастанию
+			query := `UPDATE items
+				SET type = $1, title = $2, tags = $3, favorite = $4, payload = $5, created_at = $6, updated_at = $7, folder_id = $8,
+					blind_index = $9
+				WHERE user_id = $10 AND server_id = $11 AND
+				      (type, title, tags, favorite, payload, updated_at, folder_id, blind_index) IS NOT ($1, $2, $3, $4, $5, $7, $8, $9)`
+			res, err := tx.ExecContext(ctx, query, item.Type, item.Title, string(tags), item.Favorite, item.Payload,
+				formatTime(item.CreatedAt), formatTime(item.UpdatedAt), item.FolderID, string(blindIndex), userID, item.ID)
+			if err != nil {
+				s.log.Error("failed to update item from server", "error", err)
+				return 0, 0, err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				pulled++
+			}
+			continue
+		}
+
+		query := `INSERT INTO items (user_id, type, title, tags, favorite, payload, created_at, updated_at, server_id, dirty,
+				folder_id, blind_index)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 0, $10, $11)`
+		if _, err := tx.ExecContext(ctx, query, userID, item.Type, item.Title, string(tags), item.Favorite, item.Payload,
+			formatTime(item.CreatedAt), formatTime(item.UpdatedAt), item.ID, item.FolderID, string(blindIndex)); err != nil {
+			s.log.Error("failed to insert item from server", "error", err)
+			return 0, 0, err
+		}
+		pulled++
+	}
+
+	for serverID, dirty := range known {
+		if _, ok := onServer[serverID]; ok || dirty {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, `DELETE FROM items WHERE user_id = $1 AND server_id = $2`, userID, serverID); err != nil {
+			s.log.Error("failed to remove item deleted on server", "error", err)
+			return 0, 0, err
+		}
+		removed++
+	}
+
+	// перенесенные записи, которых на сервере нет, отправятся как новые
+	query := `UPDATE items SET dirty = 1, version = version + 1 WHERE user_id = $1 AND server_id IS NULL AND dirty = 0`
+	if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+		s.log.Error("failed to mark migrated items dirty", "error", err)
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return pulled, removed, nil
+}
+
+// unlinkedItems - записи без server_id и без изменений (перенесенные из таблиц прежней версии),
+// ключ - unlinkedKey, значения - id записей по возрастанию.
+func (s *Storage) unlinkedItems(ctx context.Context, tx *sql.Tx, userID int) (map[string][]int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, type, title, payload FROM items
+		WHERE user_id = $1 AND server_id IS NULL AND dirty = 0 AND deleted = 0
+		ORDER BY id`, userID)
+	if err != nil {
+		s.log.Error("failed to get migrated items", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlinked := make(map[string][]int64)
+	for rows.Next() {
+		var (
+			id       int64
+			itemType models.ItemType
+			title    string
+			payload  []byte
+		)
+		if err := rows.Scan(&id, &itemType, &title, &payload); err != nil {
+			return nil, err
+		}
+		key := unlinkedKey(itemType, title, payload)
+		unlinked[key] = append(unlinked[key], id)
+	}
+	return unlinked, rows.Err()
+}
+
+// unlinkedKey - ключ сопоставления перенесенной записи с записью сервера. Данные
+// перекодируются, потому что сервер и миграция сериализуют JSON по-разному.
+func unlinkedKey(itemType models.ItemType, title string, payload []byte) string {
+	if p, err := models.DecodePayload(payload); err == nil {
+		if encoded, err := models.EncodePayload(p); err == nil {
+			payload = encoded
+		}
+	}
+	return fmt.Sprintf("%d\x00%s\x00%s", itemType, title, payload)
+}
@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"context"
+	"goph-keeper/internal/models"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// newTestStorage - база в отдельном каталоге и пользователь с токеном tok.
+func newTestStorage(t *testing.T) (*Storage, int) {
+	t.Helper()
+	s, err := NewSqlStorage(slog.New(slog.NewTextHandler(io.Discard, nil)), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	if err := s.SaveLoginAndToken(ctx, "alice", "tok"); err != nil {
+		t.Fatal(err)
+	}
+	userID, err := s.GetUserIDWithLogin(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, userID
+}
+
+// createSynced - запись, уже отправленная на сервер под serverID.
+func createSynced(t *testing.T, s *Storage, userID int, title string, serverID int64) models.Item {
+	t.Helper()
+	ctx := context.Background()
+	item, err := s.CreateItem(ctx, models.Item{UserID: userID, Type: models.ItemTypeNote, Title: title, Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkSynced(ctx, item.ID, serverID, 0); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+// dirtyIDs - id записей с неотправленными изменениями и их версии.
+func dirtyIDs(t *testing.T, s *Storage, userID int) map[int64]models.LocalItem {
+	t.Helper()
+	items, err := s.DirtyItems(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirty := make(map[int64]models.LocalItem, len(items))
+	for _, item := range items {
+		dirty[item.ID] = item
+	}
+	return dirty
+}
+
+func TestDirtyItemsAndMarkSynced(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestStorage(t)
+
+	item, err := s.CreateItem(ctx, models.Item{UserID: userID, Type: models.ItemTypeNote, Title: "note", Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending := dirtyIDs(t, s, userID)
+	change, ok := pending[item.ID]
+	if !ok || change.ServerID != 0 || change.Version != 0 {
+		t.Fatalf("new item must be pending without server id: %+v", pending)
+	}
+
+	if err := s.MarkSynced(ctx, item.ID, 100, change.Version); err != nil {
+		t.Fatal(err)
+	}
+	if pending := dirtyIDs(t, s, userID); len(pending) != 0 {
+		t.Fatalf("synced item is still pending: %+v", pending)
+	}
+	if serverID, err := s.ServerID(ctx, userID, item.ID); err != nil || serverID != 100 {
+		t.Fatalf("ServerID = %d, %v, want 100", serverID, err)
+	}
+
+	// изменение, сделанное, пока предыдущая версия уходила на сервер
+	item.Title = "v1"
+	if _, err := s.UpdateItem(ctx, item); err != nil {
+		t.Fatal(err)
+	}
+	pushed := dirtyIDs(t, s, userID)[item.ID]
+	item.Title = "v2"
+	if _, err := s.UpdateItem(ctx, item); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkSynced(ctx, item.ID, 100, pushed.Version); err != nil {
+		t.Fatal(err)
+	}
+
+	change, ok = dirtyIDs(t, s, userID)[item.ID]
+	if !ok {
+		t.Fatal("edit made during push was marked synced")
+	}
+	if change.Title != "v2" || change.Version != pushed.Version+1 {
+		t.Errorf("pending change = %q v%d, want v2 v%d", change.Title, change.Version, pushed.Version+1)
+	}
+
+	// удаление отправленной записи ждет синхронизации
+	if err := s.DeleteItem(ctx, userID, item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if change := dirtyIDs(t, s, userID)[item.ID]; !change.Deleted || change.ServerID != 100 {
+		t.Errorf("deleted item must be pending removal on server: %+v", change)
+	}
+}
+
+func TestApplyServerItems(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	serverItem := func(id int64, title string) models.Item {
+		return models.Item{ID: id, Type: models.ItemTypeNote, Title: title, Payload: []byte(`{}`), CreatedAt: now, UpdatedAt: now}
+	}
+
+	tests := []struct {
+		name        string
+		dirtyLocal  bool
+		server      []models.Item
+		wantTitle   string
+		wantPresent bool
+		wantPulled  int
+		wantRemoved int
+	}{
+		{
+			name:        "server change applied",
+			server:      []models.Item{serverItem(10, "server")},
+			wantTitle:   "server",
+			wantPresent: true,
+			wantPulled:  1,
+		},
+		{
+			name:        "dirty local wins",
+			dirtyLocal:  true,
+			server:      []models.Item{serverItem(10, "server")},
+			wantTitle:   "local",
+			wantPresent: true,
+		},
+		{
+			name: "tombstone removes synced copy",
+			server: []models.Item{func() models.Item {
+				item := serverItem(10, "server")
+				item.DeletedAt = now
+				return item
+			}()},
+			wantRemoved: 1,
+		},
+		{
+			name:        "missing on server removes synced copy",
+			wantRemoved: 1,
+		},
+		{
+			name:        "missing on server keeps dirty copy",
+			dirtyLocal:  true,
+			wantTitle:   "local",
+			wantPresent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, userID := newTestStorage(t)
+			item := createSynced(t, s, userID, "synced", 10)
+			if tt.dirtyLocal {
+				item.Title = "local"
+				if _, err := s.UpdateItem(ctx, item); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pulled, removed, err := s.ApplyServerItems(ctx, userID, tt.server)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pulled != tt.wantPulled || removed != tt.wantRemoved {
+				t.Errorf("pulled, removed = %d, %d, want %d, %d", pulled, removed, tt.wantPulled, tt.wantRemoved)
+			}
+
+			got, err := s.GetItem(ctx, userID, item.ID)
+			switch {
+			case !tt.wantPresent && err == nil:
+				t.Errorf("item %q must be removed", got.Title)
+			case tt.wantPresent && err != nil:
+				t.Errorf("item must stay: %v", err)
+			case tt.wantPresent && got.Title != tt.wantTitle:
+				t.Errorf("title = %q, want %q", got.Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestApplyServerItems_NewAndUnchanged(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestStorage(t)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	items := []models.Item{
+		{ID: 20, Type: models.ItemTypeNote, Title: "from server", Tags: []string{"a"}, Payload: []byte(`{}`), CreatedAt: now, UpdatedAt: now},
+	}
+
+	pulled, removed, err := s.ApplyServerItems(ctx, userID, items)
+	if err != nil || pulled != 1 || removed != 0 {
+		t.Fatalf("first apply = %d, %d, %v, want 1, 0", pulled, removed, err)
+	}
+	if pending := dirtyIDs(t, s, userID); len(pending) != 0 {
+		t.Errorf("items from server must not be pending: %+v", pending)
+	}
+
+	// повторная синхронизация без изменений на сервере ничего не меняет
+	pulled, removed, err = s.ApplyServerItems(ctx, userID, items)
+	if err != nil || pulled != 0 || removed != 0 {
+		t.Errorf("second apply = %d, %d, %v, want 0, 0", pulled, removed, err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package totp - одноразовые коды RFC 6238 для записей хранилища.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"goph-keeper/internal/models"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметры по умолчанию, как у Google Authenticator.
+const (
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+)
+
+var (
+	ErrInvalidSecret = errors.New("invalid totp secret")
+	ErrInvalidURI    = errors.New("invalid otpauth uri")
+)
+
+// Parse - запись TOTP из ссылки otpauth://totp/... или секрета в base32.
+func Parse(s string) (models.TOTPPayload, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		return parseURI(s)
+	}
+
+	secret, err := normalizeSecret(s)
+	if err != nil {
+		return models.TOTPPayload{}, err
+	}
+	return models.TOTPPayload{
+		Secret:    secret,
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}, nil
+}
+
+// parseURI - разбирает ссылку Key Uri Format:
+// otpauth://totp/Issuer:account?secret=...&issuer=...&algorithm=...&digits=...&period=...
+func parseURI(s string) (models.TOTPPayload, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return models.TOTPPayload{}, fmt.Errorf("%w: %s", ErrInvalidURI, err)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return models.TOTPPayload{}, fmt.Errorf("%w: only totp is supported, got %q", ErrInvalidURI, u.Host)
+	}
+
+	q := u.Query()
+	secret, err := normalizeSecret(q.Get("secret"))
+	if err != nil {
+		return models.TOTPPayload{}, err
+	}
+
+	p := models.TOTPPayload{
+		Secret:    secret,
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		p.Issuer, p.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		p.Account = label
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		p.Issuer = issuer
+	}
+
+	if algorithm := q.Get("algorithm"); algorithm != "" {
+		p.Algorithm = strings.ToUpper(algorithm)
+		if _, err := hashFunc(p.Algorithm); err != nil {
+			return models.TOTPPayload{}, err
+		}
+	}
+	if digits := q.Get("digits"); digits != "" {
+		if p.Digits, err = strconv.Atoi(digits); err != nil || p.Digits < 6 || p.Digits > 10 {
+			return models.TOTPPayload{}, fmt.Errorf("%w: digits must be 6..10, got %q", ErrInvalidURI, digits)
+		}
+	}
+	if period := q.Get("period"); period != "" {
+		if p.Period, err = strconv.Atoi(period); err != nil || p.Period <= 0 {
+			return models.TOTPPayload{}, fmt.Errorf("%w: invalid period %q", ErrInvalidURI, period)
+		}
+	}
+	return p, nil
+}
+
+// normalizeSecret - секрет base32 в верхнем регистре без пробелов и выравнивания.
+func normalizeSecret(s string) (string, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	if s == "" {
+		return "", fmt.Errorf("%w: empty secret", ErrInvalidSecret)
+	}
+	if _, err := decodeSecret(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// decodeSecret - байты секрета из base32 без выравнивания.
+func decodeSecret(s string) ([]byte, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSecret, err)
+	}
+	return key, nil
+}
+
+// hashFunc - хеш-функция HMAC по названию алгоритма.
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidURI, algorithm)
+	}
+}
+
+// Code - код на момент t.
+func Code(p models.TOTPPayload, t time.Time) (string, error) {
+	key, err := decodeSecret(p.Secret)
+	if err != nil {
+		return "", err
+	}
+	h, err := hashFunc(p.Algorithm)
+	if err != nil {
+		return "", err
+	}
+	digits := p.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix())/uint64(period(p)))
+
+	mac := hmac.New(h, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// динамическое усечение, RFC 4226 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint64(1)
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, uint64(value)%mod), nil
+}
+
+// Remaining - сколько действует код, выданный в момент t.
+func Remaining(p models.TOTPPayload, t time.Time) time.Duration {
+	step := int64(period(p))
+	return time.Duration(step-t.Unix()%step) * time.Second
+}
+
+// period - шаг в секундах.
+func period(p models.TOTPPayload) int {
+	if p.Period <= 0 {
+		return DefaultPeriod
+	}
+	return p.Period
+}
@@ -0,0 +1,96 @@
+package totp
+
+import (
+	"encoding/base32"
+	"errors"
+	"goph-keeper/internal/models"
+	"testing"
+	"time"
+)
+
+func secret(s string) string {
+	return base32.StdEncoding.EncodeToString([]byte(s))
+}
+
+// Тестовые значения из приложения B RFC 6238.
+func TestCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix      int64
+		algorithm string
+		secret    string
+		want      string
+	}{
+		{59, "SHA1", "12345678901234567890", "94287082"},
+		{59, "SHA256", "12345678901234567890123456789012", "46119246"},
+		{59, "SHA512", "1234567890123456789012345678901234567890123456789012345678901234", "90693936"},
+		{1111111109, "SHA1", "12345678901234567890", "07081804"},
+		{1234567890, "SHA256", "12345678901234567890123456789012", "91819424"},
+		{2000000000, "SHA512", "1234567890123456789012345678901234567890123456789012345678901234", "38618901"},
+		{20000000000, "SHA1", "12345678901234567890", "65353130"},
+	}
+	for _, tt := range tests {
+		p := models.TOTPPayload{Secret: secret(tt.secret), Algorithm: tt.algorithm, Digits: 8, Period: 30}
+		got, err := Code(p, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%s, %d) = %s, want %s", tt.algorithm, tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	p, err := Parse("otpauth://totp/GitHub:alice%40example.com?secret=jbsw y3dp ehpk 3pxp&issuer=GitHub&digits=8&period=60&algorithm=sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.TOTPPayload{Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Account: "alice@example.com",
+		Algorithm: "SHA256", Digits: 8, Period: 60}
+	if p != want {
+		t.Errorf("Parse = %+v, want %+v", p, want)
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	p, err := Parse("jbsw-y3dp-ehpk-3pxp====")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Secret != "JBSWY3DPEHPK3PXP" || p.Digits != DefaultDigits || p.Period != DefaultPeriod || p.Algorithm != DefaultAlgorithm {
+		t.Errorf("Parse = %+v", p)
+	}
+	code, err := Code(p, time.Unix(0, 0))
+	if err != nil || len(code) != 6 {
+		t.Errorf("Code = %q, %v", code, err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"", ErrInvalidSecret},
+		{"not base32!", ErrInvalidSecret},
+		{"otpauth://hotp/x?secret=JBSWY3DPEHPK3PXP", ErrInvalidURI},
+		{"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&algorithm=md5", ErrInvalidURI},
+		{"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=4", ErrInvalidURI},
+		{"otpauth://totp/x", ErrInvalidSecret},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) err = %v, want %v", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	p := models.TOTPPayload{Period: 30}
+	if got := Remaining(p, time.Unix(59, 0)); got != time.Second {
+		t.Errorf("Remaining = %v, want 1s", got)
+	}
+	if got := Remaining(p, time.Unix(60, 0)); got != 30*time.Second {
+		t.Errorf("Remaining = %v, want 30s", got)
+	}
+}
@@ -0,0 +1,146 @@
+package vaultcrypto
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+// shareInfo - контекст HKDF для ключа, которым шифруется ключ записи получателя.
+const shareInfo = "goph-keeper:share"
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid public key")
+)
+
+// GenerateKeyPair - создает пару ключей X25519 пользователя. Закрытый ключ
+// возвращается зашифрованным ключом хранилища: сервер хранит его, не имея доступа.
+func GenerateKeyPair(vaultKey []byte) (publicKey, sealedPrivateKey []byte, err error) {
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sealedPrivateKey, err = encrypt(vaultKey, private.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return private.PublicKey().Bytes(), sealedPrivateKey, nil
+}
+
+// OpenPrivateKey - расшифровывает закрытый ключ, созданный GenerateKeyPair.
+func OpenPrivateKey(vaultKey, sealedPrivateKey []byte) ([]byte, error) {
+	return decrypt(vaultKey, sealedPrivateKey)
+}
+
+// ShareKey - перешифровывает ключ записи, зашифрованный Seal ключом хранилища,
+// для открытого ключа получателя. Результат - эфемерный открытый ключ и
+// зашифрованный ключ записи; расшифровать его может только владелец закрытого ключа.
+func ShareKey(vaultKey, wrappedKey, recipientPublicKey []byte) ([]byte, error) {
+	itemKey, err := decrypt(vaultKey, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(itemKey)
+
+	recipient, err := ecdh.X25519().NewPublicKey(recipientPublicKey)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := shareKEK(ephemeral, recipient)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(kek)
+
+	sealed, err := encrypt(kek, itemKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ephemeral.PublicKey().Bytes(), sealed...), nil
+}
+
+// OpenShared - расшифровывает данные чужой записи ключом, полученным через ShareKey.
+func OpenShared(privateKey, ciphertext, sharedKey []byte) ([]byte, error) {
+	itemKey, err := openSharedKey(privateKey, sharedKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(itemKey)
+
+	return decrypt(itemKey, ciphertext)
+}
+
+// ResealShared - шифрует новые данные чужой записи ключом записи, полученным через
+// ShareKey. Ключ записи тот же, поэтому владелец и другие получатели читают изменение.
+func ResealShared(privateKey, sharedKey, plaintext []byte) ([]byte, error) {
+	itemKey, err := openSharedKey(privateKey, sharedKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(itemKey)
+
+	return encrypt(itemKey, plaintext)
+}
+
+// openSharedKey - расшифровывает ключ записи, перешифрованный ShareKey.
+func openSharedKey(privateKey, sharedKey []byte) ([]byte, error) {
+	private, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	size := len(private.PublicKey().Bytes())
+	if len(sharedKey) < size {
+		return nil, ErrDecryptFailed
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sharedKey[:size])
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+
+	secret, err := private.ECDH(ephemeral)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	kek, err := deriveKEK(secret, ephemeral.Bytes(), private.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer clear(kek)
+
+	return decrypt(kek, sharedKey[size:])
+}
+
+// shareKEK - ключ шифрования ключа записи со стороны отправителя.
+func shareKEK(ephemeral *ecdh.PrivateKey, recipient *ecdh.PublicKey) ([]byte, error) {
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return deriveKEK(secret, ephemeral.PublicKey().Bytes(), recipient.Bytes())
+}
+
+// deriveKEK - выводит ключ из общего секрета X25519 (HKDF-SHA256). Оба открытых
+// ключа входят в контекст, чтобы ключ был привязан к конкретной паре.
+func deriveKEK(secret, ephemeralPublic, recipientPublic []byte) ([]byte, error) {
+	info := append([]byte(shareInfo), ephemeralPublic...)
+	info = append(info, recipientPublic...)
+
+	kek := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), kek); err != nil {
+		return nil, err
+	}
+	return kek, nil
+}
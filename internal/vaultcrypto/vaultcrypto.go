@@ -0,0 +1,151 @@
+package vaultcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/argon2"
+	"strings"
+	"unicode"
+)
+
+const (
+	// KeySize - размер ключа хранилища и ключей записей.
+	KeySize = 32
+
+	// blindTokenSize - длина слепого токена в байтах до hex-кодирования.
+	blindTokenSize = 16
+)
+
+var (
+	ErrInvalidKey    = errors.New("invalid key size")
+	ErrDecryptFailed = errors.New("failed to decrypt data")
+)
+
+// DeriveKey - получает ключ хранилища из логина и мастер-пароля (Argon2id).
+// Мастер-пароль - отдельный от пароля входа секрет, который никогда не уходит на сервер:
+// пароль входа сервер видит при каждом входе. Соль вычисляется из логина, поэтому на
+// всех устройствах пользователя ключ совпадает.
+func DeriveKey(login, password string) []byte {
+	salt := sha256.Sum256([]byte("goph-keeper:" + login))
+	return argon2.IDKey([]byte(password), salt[:], 1, 64*1024, 4, KeySize)
+}
+
+// Seal - шифрует данные записи. Для каждой записи создается свой ключ,
+// который шифруется ключом хранилища и возвращается вместе с шифротекстом.
+func Seal(vaultKey, plaintext []byte) (ciphertext, wrappedKey []byte, err error) {
+	itemKey := make([]byte, KeySize)
+	if _, err := rand.Read(itemKey); err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err = encrypt(itemKey, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey, err = encrypt(vaultKey, itemKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ciphertext, wrappedKey, nil
+}
+
+// Open - расшифровывает данные записи, зашифрованные Seal.
+func Open(vaultKey, ciphertext, wrappedKey []byte) ([]byte, error) {
+	itemKey, err := decrypt(vaultKey, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(itemKey, ciphertext)
+}
+
+// Reseal - шифрует новые данные записи прежним ключом записи. Зашифрованный ключ
+// не меняется, поэтому выданные другим пользователям копии ключа остаются верными.
+func Reseal(vaultKey, wrappedKey, plaintext []byte) ([]byte, error) {
+	itemKey, err := decrypt(vaultKey, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(itemKey)
+
+	return encrypt(itemKey, plaintext)
+}
+
+// BlindTokens - вычисляет слепые токены поиска по словам текста.
+// Сервер сравнивает токены на равенство, не зная исходных слов.
+func BlindTokens(vaultKey []byte, text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]struct{}, len(words))
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+
+		mac := hmac.New(sha256.New, vaultKey)
+		mac.Write([]byte("blind:" + word))
+		tokens = append(tokens, hex.EncodeToString(mac.Sum(nil)[:blindTokenSize]))
+	}
+
+	return tokens
+}
+
+// encrypt - шифрует данные AES-256-GCM, nonce записывается перед шифротекстом.
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decrypt - расшифровывает данные, зашифрованные encrypt.
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrDecryptFailed
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+
+	return plaintext, nil
+}
+
+// newGCM - создает AEAD AES-GCM для ключа размера KeySize.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,142 @@
+package vaultcrypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	key := DeriveKey("user", "master-password")
+
+	ciphertext, wrappedKey, err := Seal(key, []byte("secret payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("secret payload")) {
+		t.Errorf("ciphertext contains plaintext")
+	}
+
+	plaintext, err := Open(key, ciphertext, wrappedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(plaintext) != "secret payload" {
+		t.Errorf("unexpected plaintext: got %q", plaintext)
+	}
+
+	otherKey := DeriveKey("user", "wrong-password")
+	if _, err := Open(otherKey, ciphertext, wrappedKey); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("expected ErrDecryptFailed, got %v", err)
+	}
+}
+
+func TestReseal(t *testing.T) {
+	key := DeriveKey("user", "master-password")
+
+	_, wrappedKey, err := Seal(key, []byte("old payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciphertext, err := Reseal(key, wrappedKey, []byte("new payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plaintext, err := Open(key, ciphertext, wrappedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(plaintext) != "new payload" {
+		t.Errorf("unexpected plaintext: got %q", plaintext)
+	}
+
+	if _, err := Reseal(DeriveKey("user", "wrong-password"), wrappedKey, []byte("x")); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("expected ErrDecryptFailed, got %v", err)
+	}
+}
+
+func TestBlindTokens(t *testing.T) {
+	key := DeriveKey("user", "master-password")
+
+	tokens := BlindTokens(key, "Mail.ru mail")
+	if len(tokens) != 2 {
+		t.Fatalf("expected 2 unique tokens, got %d", len(tokens))
+	}
+
+	query := BlindTokens(key, "MAIL")
+	if query[0] != tokens[0] {
+		t.Errorf("tokens for the same word must match")
+	}
+
+	other := BlindTokens(DeriveKey("other", "master-password"), "mail")
+	if other[0] == tokens[0] {
+		t.Errorf("tokens must depend on the vault key")
+	}
+}
+
+func TestShareKey(t *testing.T) {
+	ownerKey := DeriveKey("owner", "master-password")
+	ciphertext, wrappedKey, err := Seal(ownerKey, []byte("shared payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recipientKey := DeriveKey("recipient", "other-password")
+	publicKey, sealedPrivate, err := GenerateKeyPair(recipientKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sharedKey, err := ShareKey(ownerKey, wrappedKey, publicKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	privateKey, err := OpenPrivateKey(recipientKey, sealedPrivate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plaintext, err := OpenShared(privateKey, ciphertext, sharedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(plaintext) != "shared payload" {
+		t.Errorf("unexpected plaintext: got %q", plaintext)
+	}
+
+	// изменение получателя шифруется тем же ключом записи и читается владельцем
+	edited, err := ResealShared(privateKey, sharedKey, []byte("edited payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plaintext, err = Open(ownerKey, edited, wrappedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(plaintext) != "edited payload" {
+		t.Errorf("unexpected plaintext: got %q", plaintext)
+	}
+
+	// закрытый ключ не открывается чужим ключом хранилища
+	if _, err := OpenPrivateKey(ownerKey, sealedPrivate); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("expected ErrDecryptFailed, got %v", err)
+	}
+
+	// ключ, выданный одному получателю, не подходит другому
+	_, otherSealed, err := GenerateKeyPair(ownerKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherPrivate, err := OpenPrivateKey(ownerKey, otherSealed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := OpenShared(otherPrivate, ciphertext, sharedKey); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("expected ErrDecryptFailed, got %v", err)
+	}
+
+	if _, err := ShareKey(ownerKey, wrappedKey, []byte("short")); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("expected ErrInvalidPublicKey, got %v", err)
+	}
+}
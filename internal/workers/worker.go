@@ -6,24 +6,29 @@ import (
 	"time"
 )
 
+// service - задача, которую воркер выполняет периодически.
 type service interface {
-	PushData()
+	Do(ctx context.Context)
 }
 
+// Worker - запускает задачу раз в interval, пока не отменен контекст.
 type Worker struct {
-	service service
-	wg      *sync.WaitGroup
+	service  service
+	interval time.Duration
+	wg       *sync.WaitGroup
 }
 
-func NewWorker(service service) *Worker {
+func NewWorker(service service, interval time.Duration) *Worker {
 	return &Worker{
-		service: service,
-		wg:      &sync.WaitGroup{},
+		service:  service,
+		interval: interval,
+		wg:       &sync.WaitGroup{},
 	}
 }
 
+// Run - выполняет задачу по таймеру. После отмены контекста дожидается запущенных задач.
 func (w *Worker) Run(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Second)
+	ticker := time.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	for {
@@ -32,7 +37,7 @@ func (w *Worker) Run(ctx context.Context) {
 			w.wg.Add(1)
 			go func() {
 				defer w.wg.Done()
-				w.service.PushData()
+				w.service.Do(ctx)
 			}()
 		case <-ctx.Done():
 			w.wg.Wait()
@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingService - задача, которая считает запуски.
+type countingService struct {
+	calls atomic.Int32
+}
+
+func (s *countingService) Do(ctx context.Context) {
+	s.calls.Add(1)
+}
+
+func TestWorker_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	service := &countingService{}
+	worker := NewWorker(service, 5*time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for service.calls.Load() < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("job was called %d times", service.calls.Load())
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancel")
+	}
+}